@@ -5,35 +5,78 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"log/slog"
 	"math/big"
 	"os"
+	"os/signal"
+	"runtime"
 	"strings"
+	"syscall"
+	"time"
 
 	"evmbal/pkg/config"
 	"evmbal/pkg/models"
+	"evmbal/pkg/rpc"
 	"evmbal/pkg/server"
 	"evmbal/pkg/tui"
+	"evmbal/pkg/utils"
 	"evmbal/pkg/watcher"
 
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/charmbracelet/x/term"
+	"github.com/ethereum/go-ethereum/common"
 )
 
-// Version should be set during build
-var Version = "dev"
+// Version, Commit and BuildDate should be set during build
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildDate = "unknown"
+)
+
+// versionInfo is printed by -version -json.
+type versionInfo struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+	Go      string `json:"go"`
+	Built   string `json:"built"`
+}
 
 func main() {
 	testFlag := flag.Bool("t", false, "Test configuration and exit")
 	testLongFlag := flag.Bool("test", false, "Test configuration and exit")
-	jsonFlag := flag.Bool("json", false, "Output test results as JSON")
+	jsonFlag := flag.Bool("json", false, "Output test results (or -version) as JSON")
 	dryRunFlag := flag.Bool("dry-run", false, "Perform a trial run with no changes made")
 	configFlag := flag.String("config", "", "Path to configuration file")
 	versionFlag := flag.Bool("version", false, "Print version and exit")
 	serverFlag := flag.Bool("server", false, "Run in headless server mode")
 	portFlag := flag.Int("port", 8080, "Port for API server")
+	logFileFlag := flag.String("log-file", "", "Write logs to this file (disabled by default)")
+	dashboardFlag := flag.Bool("dashboard", false, "Show a full-screen, non-interactive dashboard of totals and gas for unattended displays")
+	debugFlag := flag.Bool("debug", false, "Enable the debug overlay (ctrl+d) in the TUI")
+	proxyFlag := flag.String("proxy", "", "HTTP proxy URL for RPC and API requests (overrides http_proxy in config and HTTP_PROXY/HTTPS_PROXY)")
+	replayFlag := flag.String("replay", "", "Serve balances, prices, gas and transactions from the fixture files in this directory instead of live RPCs")
+	recordFlag := flag.String("record", "", "Save fetched balances, prices, gas and transactions as fixture files in this directory (replay with -replay)")
+	pruneBackupsFlag := flag.Bool("prune-backups", false, "Delete config backups, keeping the most recent backup_retention, and exit")
+	accountsFlag := flag.Bool("accounts", false, "With -test, also look up every address's native balance on every chain")
+	printSchemaFlag := flag.Bool("print-schema", false, "Print a JSON Schema for the config file and exit")
+	initFlag := flag.Bool("init", false, "Write a default config with popular chains to the config path (if none exists) and exit")
+	checkUpdateFlag := flag.Bool("check-update", false, "Check GitHub for a newer release and exit")
 	flag.Parse()
 
 	if *versionFlag {
-		fmt.Printf("evmbal version %s\n", Version)
+		if *jsonFlag {
+			out, _ := json.Marshal(versionInfo{Version: Version, Commit: Commit, Go: runtime.Version(), Built: BuildDate})
+			fmt.Println(string(out))
+		} else {
+			fmt.Printf("evmbal version %s\n", Version)
+		}
+		os.Exit(0)
+	}
+
+	if *printSchemaFlag {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(config.Schema())
 		os.Exit(0)
 	}
 
@@ -47,11 +90,76 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *initFlag {
+		if err := config.WriteDefaultConfig(path); err != nil {
+			fmt.Printf("Error writing default config: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote a default config with %d chains to %s\n", len(config.DefaultChains()), path)
+		fmt.Println("Add the addresses to watch under \"addresses\", or run evmbal and press 'a'.")
+		os.Exit(0)
+	}
+
+	// Only ask for a passphrase at startup; the TUI owns the terminal afterwards.
+	config.PassphrasePrompt = promptPassphrase
 	savedAddrs, savedChains, activeChainIdx, savedGlobalCfg, err := config.LoadConfigFromFile(path)
+	config.PassphrasePrompt = nil
 	if err != nil {
 		fmt.Printf("Error loading config from %s: %v\n", path, err)
 		os.Exit(1)
 	}
+	// Unknown keys are usually typos; they are ignored rather than fatal so
+	// configs written by newer versions still load.
+	unknownKeys, _ := config.UnknownFieldsInFile(path)
+
+	if *pruneBackupsFlag {
+		removed, err := config.PruneBackups(path, savedGlobalCfg.BackupRetention)
+		fmt.Printf("Removed %d backup(s) of %s\n", removed, path)
+		if err != nil {
+			fmt.Printf("Error pruning backups: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	proxyURL := savedGlobalCfg.HTTPProxy
+	if *proxyFlag != "" {
+		proxyURL = *proxyFlag
+	}
+	if err := rpc.SetProxy(proxyURL); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	rpc.SetRPCHeaders(savedChains)
+
+	if *checkUpdateFlag {
+		rel, err := rpc.LatestRelease(rpc.ReleaseRepo)
+		if err != nil {
+			fmt.Printf("Error checking for updates: %v\n", err)
+			os.Exit(1)
+		}
+		newer, ok := rpc.IsNewerVersion(Version, rel.Tag)
+		switch {
+		case !ok:
+			fmt.Printf("Latest release is %s (this build is %s)\n", rel.Tag, Version)
+		case newer:
+			fmt.Printf("A newer version is available: %s (you have %s)\n", rel.Tag, Version)
+		default:
+			fmt.Printf("evmbal %s is up to date\n", Version)
+		}
+		if rel.URL != "" && (newer || !ok) {
+			fmt.Printf("Download: %s\n", rel.URL)
+		}
+		os.Exit(0)
+	}
+	if err := utils.SetNumberFormat(savedGlobalCfg.NumberFormat); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if _, err := utils.ParseRoundingMode(savedGlobalCfg.RoundingMode); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
 
 	if *testFlag || *testLongFlag {
 		var report models.TestReport
@@ -62,6 +170,12 @@ func main() {
 		if !*jsonFlag {
 			fmt.Printf("Testing configuration at: %s\n", path)
 		}
+		report.UnknownKeys = unknownKeys
+		if !*jsonFlag {
+			for _, k := range unknownKeys {
+				fmt.Printf("Warning: unknown config key %q is ignored (misspelled?)\n", k)
+			}
+		}
 
 		if len(savedChains) == 0 {
 			report.ValidStructure = false
@@ -93,6 +207,14 @@ func main() {
 					fmt.Printf("Error: %s\n", msg)
 				}
 			}
+			if th := chain.GasThresholds; th != [2]float64{} && (th[0] <= 0 || th[1] <= th[0]) {
+				msg := fmt.Sprintf("Chain '%s' has invalid gas_thresholds %v; need 0 < low < high.", chain.Name, th)
+				report.StructureErrors = append(report.StructureErrors, msg)
+				report.ValidStructure = false
+				if !*jsonFlag {
+					fmt.Printf("Error: %s\n", msg)
+				}
+			}
 		}
 
 		if !report.ValidStructure {
@@ -115,6 +237,12 @@ func main() {
 		configUpdated := false
 		for i := range savedChains {
 			chain := &savedChains[i]
+			if !chain.IsEnabled() {
+				if !*jsonFlag {
+					fmt.Printf("Skipping Chain: %s (%s), disabled\n", chain.Name, chain.Symbol)
+				}
+				continue
+			}
 			cResult := models.ChainResult{
 				Name:          chain.Name,
 				Symbol:        chain.Symbol,
@@ -126,12 +254,20 @@ func main() {
 			}
 			var observedChainID *big.Int
 			chainInconsistent := false
-			for _, rpc := range chain.RPCURLs {
-				rResult := models.RPCResult{URL: rpc}
+			for _, rpcURL := range chain.RPCURLs {
+				rResult := models.RPCResult{URL: rpcURL}
+				if chain.RPCDisabled(rpcURL) {
+					rResult.Status = "disabled"
+					if !*jsonFlag {
+						fmt.Printf("  RPC: %s ... Disabled\n", rpcURL)
+					}
+					cResult.RPCs = append(cResult.RPCs, rResult)
+					continue
+				}
 				if !*jsonFlag {
-					fmt.Printf("  RPC: %s ... ", rpc)
+					fmt.Printf("  RPC: %s ... ", rpcURL)
 				}
-				client, err := ethclient.Dial(rpc)
+				id, err := rpc.FetchChainID(rpcURL, chain.Timeout(rpc.DefaultRequestTimeout))
 				if err != nil {
 					rResult.Status = "error"
 					rResult.Error = err.Error()
@@ -141,58 +277,51 @@ func main() {
 					cResult.RPCs = append(cResult.RPCs, rResult)
 					continue
 				}
-				id, err := client.ChainID(context.Background())
-				if err != nil {
-					rResult.Status = "error"
-					rResult.Error = fmt.Sprintf("Failed to get ChainID: %v", err)
-					if !*jsonFlag {
-						fmt.Printf("Failed to get ChainID: %v\n", err)
-					}
-				} else {
-					rResult.Status = "ok"
-					rResult.ChainID = id.Int64()
+				rResult.Status = "ok"
+				rResult.ChainID = id.Int64()
+				if !*jsonFlag {
+					fmt.Printf("OK (ChainID: %s)", id.String())
+				}
+				if observedChainID == nil {
+					observedChainID = id
+					cResult.ObservedChainID = id.Int64()
+				} else if observedChainID.Cmp(id) != 0 {
 					if !*jsonFlag {
-						fmt.Printf("OK (ChainID: %s)", id.String())
-					}
-					if observedChainID == nil {
-						observedChainID = id
-						cResult.ObservedChainID = id.Int64()
-					} else if observedChainID.Cmp(id) != 0 {
-						if !*jsonFlag {
-							fmt.Printf(" - WARNING: ChainID mismatch with previous RPC (%s)", observedChainID.String())
-						}
-						chainInconsistent = true
+						fmt.Printf(" - WARNING: ChainID mismatch with previous RPC (%s)", observedChainID.String())
 					}
+					chainInconsistent = true
+				}
 
-					if chain.ChainID != 0 {
-						if id.Cmp(big.NewInt(chain.ChainID)) != 0 {
-							rResult.Error = fmt.Sprintf("Mismatch! Expected %d", chain.ChainID)
-							if !*jsonFlag {
-								fmt.Printf(" - MISMATCH! Expected %d", chain.ChainID)
-							}
-						} else {
-							if !*jsonFlag {
-								fmt.Printf(" - Verified")
-							}
+				if chain.ChainID != 0 {
+					if id.Cmp(big.NewInt(chain.ChainID)) != 0 {
+						rResult.Error = fmt.Sprintf("Mismatch! Expected %d", chain.ChainID)
+						if !*jsonFlag {
+							fmt.Printf(" - MISMATCH! Expected %d", chain.ChainID)
 						}
 					} else {
-						chain.ChainID = id.Int64()
-						configUpdated = true
-						cResult.ChainIDUpdated = true
 						if !*jsonFlag {
-							fmt.Printf(" - UPDATED CONFIG")
-							if *dryRunFlag {
-								fmt.Printf(" (DRY RUN)")
-							}
+							fmt.Printf(" - Verified")
 						}
 					}
+				} else {
+					chain.ChainID = id.Int64()
+					configUpdated = true
+					cResult.ChainIDUpdated = true
 					if !*jsonFlag {
-						fmt.Println()
+						fmt.Printf(" - UPDATED CONFIG")
+						if *dryRunFlag {
+							fmt.Printf(" (DRY RUN)")
+						}
 					}
 				}
-				client.Close()
+				if !*jsonFlag {
+					fmt.Println()
+				}
 				cResult.RPCs = append(cResult.RPCs, rResult)
 			}
+			if observedChainID != nil {
+				cResult.Tokens = testTokens(*chain, !*jsonFlag)
+			}
 			if chainInconsistent {
 				cResult.Inconsistent = true
 				inconsistentChains = append(inconsistentChains, chain.Name)
@@ -202,6 +331,10 @@ func main() {
 
 		report.InconsistentChains = inconsistentChains
 
+		if *accountsFlag {
+			report.Accounts = testAccounts(savedAddrs, savedChains, !*jsonFlag)
+		}
+
 		if len(inconsistentChains) > 0 {
 			if !*jsonFlag {
 				fmt.Println("\nWARNING: Inconsistent RPCs detected!")
@@ -243,16 +376,59 @@ func main() {
 		os.Exit(0)
 	}
 
-	if len(savedChains) == 0 {
+	// Without chains the TUI opens its setup wizard; server mode has nothing to serve.
+	if len(savedChains) == 0 && *serverFlag {
 		fmt.Println("Error: No Chains found in configuration.")
-		fmt.Printf("Please create a config file at %s with 'chains'.\n", path)
+		fmt.Printf("Please create a config file at %s with 'chains', or run evmbal -init to write one with popular chains.\n", path)
 		os.Exit(1)
 	}
 
+	for _, k := range unknownKeys {
+		fmt.Fprintf(os.Stderr, "Warning: unknown config key %q in %s is ignored (misspelled?)\n", k, path)
+	}
+
+	rpc.SetRequestsPerSecond(savedGlobalCfg.RequestsPerSecond)
+
 	w := watcher.NewWatcher(savedAddrs, savedChains, savedGlobalCfg, path)
+	if *replayFlag != "" && *recordFlag != "" {
+		fmt.Println("Error: -replay and -record cannot be used together")
+		os.Exit(1)
+	}
+	if *replayFlag != "" {
+		ds, err := watcher.NewFixtureDataSource(*replayFlag, savedChains)
+		if err != nil {
+			fmt.Printf("Error loading replay fixtures: %v\n", err)
+			os.Exit(1)
+		}
+		w.SetDataSource(ds)
+	}
+	if *logFileFlag != "" {
+		// Logs never go to stdout/stderr: that would corrupt the TUI's alt-screen.
+		logFile, err := os.OpenFile(*logFileFlag, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			fmt.Printf("Error opening log file %s: %v\n", *logFileFlag, err)
+			os.Exit(1)
+		}
+		defer func() { _ = logFile.Close() }()
+		logger := slog.New(slog.NewTextHandler(logFile, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		rpc.SetLogger(logger)
+		w.SetLogger(logger)
+		logger.Info("starting evmbal", "version", Version, "config", path)
+	}
+	if *recordFlag != "" {
+		ds, err := watcher.NewRecordingDataSource(&watcher.RealDataSource{}, *recordFlag, savedChains)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		ds.SetLogger(w.Logger())
+		w.SetDataSource(ds)
+	}
 	go w.Start(context.Background())
 
 	srv := server.NewServer(w)
+	srv.SetBasicAuth(savedGlobalCfg.ServerUsername, savedGlobalCfg.ServerPassword)
+	srv.SetAllowedOrigins(savedGlobalCfg.ServerAllowedOrigins)
 	go func() {
 		if err := srv.Start(*portFlag); err != nil {
 			fmt.Printf("Server error: %v\n", err)
@@ -261,8 +437,125 @@ func main() {
 
 	if *serverFlag {
 		fmt.Printf("Running in server mode on port %d...\n", *portFlag)
-		select {} // Keep alive
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		<-ctx.Done()
+
+		fmt.Println("Shutting down...")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			fmt.Printf("Server shutdown error: %v\n", err)
+		}
+		w.Stop()
+		return
+	}
+
+	tui.Start(w, savedAddrs, savedChains, activeChainIdx, savedGlobalCfg, path, Version, *debugFlag, *dashboardFlag)
+}
+
+// promptPassphrase reads the passphrase of an encrypted config from the terminal
+// without echoing it.
+func promptPassphrase(path string) (string, error) {
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return "", config.ErrEncryptedConfig
+	}
+	fmt.Printf("Passphrase for %s: ", path)
+	p, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Println()
+	if err != nil {
+		return "", err
 	}
+	return string(p), nil
+}
+
+// testTokens calls symbol() and decimals() on each of the chain's tokens and
+// compares the results with the config, printing them if verbose. Tokens read
+// with a non-ERC-20 balance_method only need a valid method; their metadata is
+// checked if the contract has it.
+func testTokens(chain config.ChainConfig, verbose bool) []models.TokenResult {
+	var results []models.TokenResult
+	for _, t := range chain.Tokens {
+		res := models.TokenResult{Symbol: t.Symbol, Address: t.Address, ConfiguredDecimals: t.Decimals, Status: "ok"}
+		_, methodErr := rpc.BalanceCallData(t, common.Address{})
+		meta, err := rpc.FetchTokenMetadata(chain.ActiveRPCURLs(), t.Address, chain.Timeout(rpc.DefaultRequestTimeout))
+		switch {
+		case methodErr != nil:
+			res.Status = "error"
+			res.Error = methodErr.Error()
+		case err != nil && !t.IsERC20():
+			res.DecimalsMatch = true
+		case err != nil:
+			res.Status = "error"
+			res.Error = fmt.Sprintf("contract did not respond to symbol() or decimals(): %v", err)
+		case meta.DecimalsDefaulted:
+			res.Status = "error"
+			res.ObservedSymbol = meta.Symbol
+			res.Error = "decimals() could not be read"
+		default:
+			decimals := meta.Decimals
+			res.ObservedSymbol = meta.Symbol
+			res.OnChainDecimals = &decimals
+			res.DecimalsMatch = decimals == t.Decimals
+		}
+		results = append(results, res)
 
-	tui.Start(w, savedAddrs, savedChains, activeChainIdx, savedGlobalCfg, path, Version)
+		if !verbose {
+			continue
+		}
+		fmt.Printf("  Token %s (%s) ... ", t.Symbol, t.Address)
+		switch {
+		case res.Status == "error":
+			fmt.Printf("Failed: %s\n", res.Error)
+		case res.OnChainDecimals == nil:
+			fmt.Printf("OK (%s balance method, no token metadata)\n", t.BalanceMethod)
+		case !res.DecimalsMatch:
+			fmt.Printf("OK (symbol %s) - WARNING: configured with %d decimals, contract reports %d\n", res.ObservedSymbol, t.Decimals, *res.OnChainDecimals)
+		default:
+			fmt.Printf("OK (symbol %s, %d decimals)\n", res.ObservedSymbol, *res.OnChainDecimals)
+		}
+	}
+	return results
+}
+
+// testAccounts looks up the native balance of every address on every enabled
+// chain, printing the results if verbose.
+func testAccounts(addrs []config.AddressConfig, chains []config.ChainConfig, verbose bool) []models.AccountResult {
+	if verbose {
+		fmt.Println("\nTesting accounts:")
+	}
+	var results []models.AccountResult
+	for _, chain := range chains {
+		if !chain.IsEnabled() {
+			continue
+		}
+		for _, a := range addrs {
+			res := models.AccountResult{Address: a.Address, Name: a.Name, Chain: chain.Name, Status: "ok"}
+			wei, err := rpc.FetchNativeBalance(chain.ActiveRPCURLs(), a.Address, chain.Timeout(rpc.DefaultRequestTimeout))
+			balance := new(big.Float)
+			if err != nil {
+				res.Status = "error"
+				res.Error = err.Error()
+			} else {
+				balance.Quo(new(big.Float).SetInt(wei), big.NewFloat(1e18))
+				res.Balance = balance.Text('f', 6)
+				res.BalanceWei = wei.String()
+			}
+			results = append(results, res)
+
+			if !verbose {
+				continue
+			}
+			label := a.Address
+			if a.Name != "" {
+				label = fmt.Sprintf("%s (%s)", a.Name, a.Address)
+			}
+			if err != nil {
+				fmt.Printf("  %s on %s ... Failed: %v\n", label, chain.Name, err)
+			} else {
+				fmt.Printf("  %s on %s ... OK (%s %s)\n", label, chain.Name, utils.FormatBigFloat(balance, 6), chain.Symbol)
+			}
+		}
+	}
+	return results
 }
@@ -1,11 +1,13 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -13,38 +15,153 @@ import (
 
 const ConfigFileName = ".evmbal.json"
 
-// TokenConfig holds configuration for an ERC-20 token.
+// TokenConfig holds configuration for an ERC-20 token, or any contract with a
+// balance getter (see BalanceMethod).
 type TokenConfig struct {
-	Symbol      string `json:"symbol"`
-	Address     string `json:"address"`
-	Decimals    int    `json:"decimals"`
-	CoinGeckoID string `json:"coingecko_id"`
+	Symbol          string   `json:"symbol"`
+	Address         string   `json:"address"`
+	Decimals        int      `json:"decimals"`
+	CoinGeckoID     string   `json:"coingecko_id"`
+	DisplayDecimals *int     `json:"display_decimals,omitempty"` // Overrides GlobalConfig.TokenDecimals when set
+	PriceAlias      string   `json:"price_alias,omitempty"`      // Borrow another coin's price, e.g. "ethereum" for WETH
+	PriceFeed       string   `json:"price_feed,omitempty"`       // Chainlink USD feed on this chain, read when CoinGecko has no price
+	BalanceMethod   string   `json:"balance_method,omitempty"`   // BalanceMethodERC20 (default), BalanceMethodERC1155, or a 4-byte selector such as "0x70a08231" called with the holder's address
+	TokenID         string   `json:"token_id,omitempty"`         // ERC-1155 token ID, decimal or 0x-prefixed hex
+	Custom          bool     `json:"custom,omitempty"`           // A position read with an arbitrary view call, e.g. staked or LP balances; see CallArgs
+	CallArgs        []string `json:"call_args,omitempty"`        // Static arguments of a custom call, each one 32-byte word; CallArgHolder stands for the watched address
+}
+
+// CallArgHolder in TokenConfig.CallArgs is replaced by the watched address.
+const CallArgHolder = "holder"
+
+// Balance methods for TokenConfig.BalanceMethod.
+const (
+	BalanceMethodERC20   = "erc20"   // balanceOf(address)
+	BalanceMethodERC1155 = "erc1155" // balanceOf(address,uint256) with TokenID
+)
+
+// IsERC20 reports whether the token's balance is read with the standard ERC-20
+// balanceOf(address), so symbol() and decimals() can be expected too.
+func (t TokenConfig) IsERC20() bool {
+	return !t.Custom && (t.BalanceMethod == "" || strings.EqualFold(t.BalanceMethod, BalanceMethodERC20))
+}
+
+// PriceID returns the CoinGecko ID used to price the token, preferring PriceAlias.
+func (t TokenConfig) PriceID() string {
+	if t.PriceAlias != "" {
+		return t.PriceAlias
+	}
+	return t.CoinGeckoID
 }
 
 // AddressConfig holds configuration for a monitored address.
 type AddressConfig struct {
-	Address string `json:"address"`
-	Name    string `json:"name,omitempty"`
+	Address          string            `json:"address"`
+	Name             string            `json:"name,omitempty"`
+	Note             string            `json:"note,omitempty"`               // Free-form memo shown in the detail view, e.g. where the seed is kept
+	BaselineBalances map[string]string `json:"baseline_balances,omitempty"`  // Key: Chain Name, value: native balance
+	ExcludeFromTotal bool              `json:"exclude_from_total,omitempty"` // Watched but not counted in portfolio totals, e.g. a treasury
 }
 
 // ChainConfig holds configuration for a specific EVM chain.
 type ChainConfig struct {
-	Name        string        `json:"name"`
-	RPCURLs     []string      `json:"rpc_urls"`
-	Symbol      string        `json:"symbol"`
-	CoinGeckoID string        `json:"coingecko_id"`
-	ChainID     int64         `json:"chain_id,omitempty"`
-	ExplorerURL string        `json:"explorer_url,omitempty"`
-	Tokens      []TokenConfig `json:"tokens"`
+	Name           string            `json:"name"`
+	RPCURLs        []string          `json:"rpc_urls"`
+	Symbol         string            `json:"symbol"`
+	CoinGeckoID    string            `json:"coingecko_id"`
+	ChainID        int64             `json:"chain_id,omitempty"`
+	ExplorerURL    string            `json:"explorer_url,omitempty"`
+	ExplorerAPIURL string            `json:"explorer_api_url,omitempty"` // Etherscan-compatible API, e.g. https://api.etherscan.io/v2/api?chainid=1
+	ExplorerAPIKey string            `json:"explorer_api_key,omitempty"`
+	RPCHeaders     map[string]string `json:"rpc_headers,omitempty"`     // Sent with every RPC request, e.g. Authorization
+	GasAlertGwei   float64           `json:"gas_alert_gwei,omitempty"`  // Alert when gas drops below this; 0 disables
+	GasThresholds  [2]float64        `json:"gas_thresholds,omitzero"`   // Gwei below which gas shows green and amber; unset uses 30 and 100
+	TimeoutSeconds int               `json:"timeout_seconds,omitempty"` // Per-request RPC timeout; 0 uses the defaults (30s for balances, 10s for gas and transactions)
+	Enabled        *bool             `json:"enabled,omitempty"`         // Polled by the watcher; nil means enabled
+	DisabledRPCs   []string          `json:"disabled_rpcs,omitempty"`   // RPCs from rpc_urls switched off in the network status view
+	PriceFeed      string            `json:"price_feed,omitempty"`      // Chainlink USD feed for the native asset, read when CoinGecko has no price
+	Tokens         []TokenConfig     `json:"tokens"`
+}
+
+// IsEnabled reports whether the chain should be polled. Chains are enabled unless
+// Enabled is explicitly false.
+func (c ChainConfig) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+// RPCDisabled reports whether rpcURL was switched off in the network status view.
+func (c ChainConfig) RPCDisabled(rpcURL string) bool {
+	return slices.Contains(c.DisabledRPCs, rpcURL)
+}
+
+// ActiveRPCURLs returns the chain's RPCs that are not disabled, in configured order.
+func (c ChainConfig) ActiveRPCURLs() []string {
+	if len(c.DisabledRPCs) == 0 {
+		return c.RPCURLs
+	}
+	var urls []string
+	for _, u := range c.RPCURLs {
+		if !c.RPCDisabled(u) {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// Timeout returns the chain's RPC request timeout, or def if timeout_seconds
+// is unset.
+func (c ChainConfig) Timeout(def time.Duration) time.Duration {
+	if c.TimeoutSeconds <= 0 {
+		return def
+	}
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+// Default gas-color cutoffs in Gwei, suited to Ethereum mainnet.
+const (
+	DefaultGasLowGwei  = 30
+	DefaultGasHighGwei = 100
+)
+
+// GasLevels returns the Gwei cutoffs below which the chain's gas price is
+// shown as low and moderate. Unset or inverted thresholds use the defaults.
+func (c ChainConfig) GasLevels() (low, high float64) {
+	low, high = c.GasThresholds[0], c.GasThresholds[1]
+	if low <= 0 || high <= low {
+		return DefaultGasLowGwei, DefaultGasHighGwei
+	}
+	return low, high
 }
 
 // GlobalConfig holds application-wide settings.
 type GlobalConfig struct {
-	PrivacyTimeoutSeconds    int  `json:"privacy_timeout_seconds"`
-	FiatDecimals             int  `json:"fiat_decimals"`
-	TokenDecimals            int  `json:"token_decimals"`
-	AutoCycleEnabled         bool `json:"auto_cycle_enabled"`
-	AutoCycleIntervalSeconds int  `json:"auto_cycle_interval_seconds"`
+	PrivacyTimeoutSeconds     int      `json:"privacy_timeout_seconds"`
+	FiatDecimals              int      `json:"fiat_decimals"`
+	TokenDecimals             int      `json:"token_decimals"`
+	AutoCycleEnabled          bool     `json:"auto_cycle_enabled"`
+	AutoCycleIntervalSeconds  int      `json:"auto_cycle_interval_seconds"`
+	CompactNumbers            bool     `json:"compact_numbers"`
+	HideZeroBalances          bool     `json:"hide_zero_balances"`
+	RequestsPerSecond         float64  `json:"requests_per_second,omitempty"` // Per RPC host, websocket dials only for ws RPCs; 0 uses the default
+	HTTPProxy                 string   `json:"http_proxy,omitempty"`          // Overrides HTTP_PROXY/HTTPS_PROXY
+	ServerUsername            string   `json:"server_username,omitempty"`     // Basic auth for the API server; empty disables auth
+	ServerPassword            string   `json:"server_password,omitempty"`
+	ServerAllowedOrigins      []string `json:"server_allowed_origins,omitempty"`        // Websocket origins; empty allows same-origin only
+	PauseWhenUnfocused        bool     `json:"pause_when_unfocused,omitempty"`          // Pause polling while the terminal is unfocused
+	PrimaryChain              string   `json:"primary_chain,omitempty"`                 // Chain whose price and gas the top bar always shows
+	BackupRetention           int      `json:"backup_retention,omitempty"`              // Backups kept by -prune-backups; 0 removes all
+	NumberFormat              string   `json:"number_format,omitempty"`                 // Thousands/decimal separators: "us" (1,234.56, the default) or "eu" (1.234,56)
+	FiatSymbol                string   `json:"fiat_symbol,omitempty"`                   // Currency symbol shown with fiat amounts; defaults to "$"
+	FiatSymbolSuffix          bool     `json:"fiat_symbol_suffix,omitempty"`            // Show the symbol after the amount, separated by a space (e.g. "1.234,56 kr")
+	MainViewTxCount           int      `json:"main_view_tx_count,omitempty"`            // Transactions listed in the main view; 0 means 3
+	HighValueUSD              float64  `json:"high_value_usd,omitempty"`                // Highlight holdings worth at least this much; 0 disables
+	DustValueUSD              float64  `json:"dust_value_usd,omitempty"`                // Dim holdings worth less than this; 0 disables
+	AutoCycleMode             string   `json:"auto_cycle_mode,omitempty"`               // "accounts" (default), "chains" or "both"
+	RoundingMode              string   `json:"rounding_mode,omitempty"`                 // "round" (default, half to even) or "truncate"
+	MarkTinyValues            bool     `json:"mark_tiny_values,omitempty"`              // Show nonzero values that round to zero as "< 0.01"
+	MinDisplayValueUSD        float64  `json:"min_display_value_usd,omitempty"`         // Hide accounts worth less than this from the summary; 0 disables
+	ResetAccountOnChainSwitch bool     `json:"reset_account_on_chain_switch,omitempty"` // Jump to the first account when switching chains; off keeps the selected account
+	LatencyHistoryPoints      int      `json:"latency_history_points,omitempty"`        // Latency samples kept per RPC for the network status sparkline; 0 uses 15
 }
 
 func GetConfigPath(customPath string) (string, error) {
@@ -59,28 +176,54 @@ func GetConfigPath(customPath string) (string, error) {
 }
 
 func LoadConfigFromFile(path string) ([]AddressConfig, []ChainConfig, int, GlobalConfig, error) {
-	f, err := os.Open(path)
+	data, err := os.ReadFile(path)
 	if os.IsNotExist(err) {
 		return []AddressConfig{}, nil, 0, GlobalConfig{PrivacyTimeoutSeconds: 60, FiatDecimals: 2, TokenDecimals: 2}, nil
 	}
 	if err != nil {
 		return nil, nil, 0, GlobalConfig{}, err
 	}
-	defer func() { _ = f.Close() }()
-	return LoadConfig(f)
+	if IsEncrypted(data) {
+		if data, err = decryptConfig(path, data); err != nil {
+			return nil, nil, 0, GlobalConfig{}, err
+		}
+	}
+	return LoadConfig(bytes.NewReader(data))
 }
 
 func LoadConfig(r io.Reader) ([]AddressConfig, []ChainConfig, int, GlobalConfig, error) {
 	var cfg struct {
-		Addresses                json.RawMessage `json:"addresses"`
-		RPCURLs                  []string        `json:"rpc_urls"` // Legacy
-		Chains                   []ChainConfig   `json:"chains"`
-		SelectedChain            string          `json:"selected_chain"`
-		PrivacyTimeoutSeconds    *int            `json:"privacy_timeout_seconds"`
-		FiatDecimals             *int            `json:"fiat_decimals"`
-		TokenDecimals            *int            `json:"token_decimals"`
-		AutoCycleEnabled         *bool           `json:"auto_cycle_enabled"`
-		AutoCycleIntervalSeconds *int            `json:"auto_cycle_interval_seconds"`
+		Addresses                 json.RawMessage `json:"addresses"`
+		RPCURLs                   []string        `json:"rpc_urls"` // Legacy
+		Chains                    []ChainConfig   `json:"chains"`
+		SelectedChain             string          `json:"selected_chain"`
+		PrivacyTimeoutSeconds     *int            `json:"privacy_timeout_seconds"`
+		FiatDecimals              *int            `json:"fiat_decimals"`
+		TokenDecimals             *int            `json:"token_decimals"`
+		AutoCycleEnabled          *bool           `json:"auto_cycle_enabled"`
+		AutoCycleIntervalSeconds  *int            `json:"auto_cycle_interval_seconds"`
+		CompactNumbers            *bool           `json:"compact_numbers"`
+		HideZeroBalances          *bool           `json:"hide_zero_balances"`
+		RequestsPerSecond         float64         `json:"requests_per_second"`
+		HTTPProxy                 string          `json:"http_proxy"`
+		ServerUsername            string          `json:"server_username"`
+		ServerPassword            string          `json:"server_password"`
+		ServerAllowedOrigins      []string        `json:"server_allowed_origins"`
+		PauseWhenUnfocused        bool            `json:"pause_when_unfocused"`
+		PrimaryChain              string          `json:"primary_chain"`
+		BackupRetention           int             `json:"backup_retention"`
+		NumberFormat              string          `json:"number_format"`
+		FiatSymbol                string          `json:"fiat_symbol"`
+		FiatSymbolSuffix          bool            `json:"fiat_symbol_suffix"`
+		MainViewTxCount           int             `json:"main_view_tx_count"`
+		HighValueUSD              float64         `json:"high_value_usd"`
+		DustValueUSD              float64         `json:"dust_value_usd"`
+		AutoCycleMode             string          `json:"auto_cycle_mode"`
+		RoundingMode              string          `json:"rounding_mode"`
+		MarkTinyValues            bool            `json:"mark_tiny_values"`
+		MinDisplayValueUSD        float64         `json:"min_display_value_usd"`
+		ResetAccountOnChainSwitch bool            `json:"reset_account_on_chain_switch"`
+		LatencyHistoryPoints      int             `json:"latency_history_points"`
 	}
 	if err := json.NewDecoder(r).Decode(&cfg); err != nil {
 		return nil, nil, 0, GlobalConfig{}, err
@@ -111,6 +254,10 @@ func LoadConfig(r io.Reader) ([]AddressConfig, []ChainConfig, int, GlobalConfig,
 		cfg.SelectedChain = "Ethereum"
 	}
 
+	for i := range cfg.Chains {
+		cfg.Chains[i].Tokens = DedupeTokens(cfg.Chains[i].Tokens)
+	}
+
 	selectedIdx := 0
 	for i, c := range cfg.Chains {
 		if c.Name == cfg.SelectedChain {
@@ -141,23 +288,121 @@ func LoadConfig(r io.Reader) ([]AddressConfig, []ChainConfig, int, GlobalConfig,
 	if cfg.AutoCycleIntervalSeconds != nil {
 		globalCfg.AutoCycleIntervalSeconds = *cfg.AutoCycleIntervalSeconds
 	}
+	if cfg.CompactNumbers != nil {
+		globalCfg.CompactNumbers = *cfg.CompactNumbers
+	}
+	if cfg.HideZeroBalances != nil {
+		globalCfg.HideZeroBalances = *cfg.HideZeroBalances
+	}
+	globalCfg.RequestsPerSecond = cfg.RequestsPerSecond
+	globalCfg.HTTPProxy = cfg.HTTPProxy
+	globalCfg.ServerUsername = cfg.ServerUsername
+	globalCfg.ServerPassword = cfg.ServerPassword
+	globalCfg.ServerAllowedOrigins = cfg.ServerAllowedOrigins
+	globalCfg.PauseWhenUnfocused = cfg.PauseWhenUnfocused
+	globalCfg.PrimaryChain = cfg.PrimaryChain
+	globalCfg.BackupRetention = cfg.BackupRetention
+	globalCfg.NumberFormat = cfg.NumberFormat
+	globalCfg.FiatSymbol = cfg.FiatSymbol
+	globalCfg.FiatSymbolSuffix = cfg.FiatSymbolSuffix
+	globalCfg.MainViewTxCount = cfg.MainViewTxCount
+	globalCfg.HighValueUSD = cfg.HighValueUSD
+	globalCfg.DustValueUSD = cfg.DustValueUSD
+	globalCfg.AutoCycleMode = cfg.AutoCycleMode
+	globalCfg.RoundingMode = cfg.RoundingMode
+	globalCfg.MarkTinyValues = cfg.MarkTinyValues
+	globalCfg.MinDisplayValueUSD = cfg.MinDisplayValueUSD
+	globalCfg.ResetAccountOnChainSwitch = cfg.ResetAccountOnChainSwitch
+	globalCfg.LatencyHistoryPoints = cfg.LatencyHistoryPoints
 
 	return addresses, cfg.Chains, selectedIdx, globalCfg, nil
 }
 
+// DedupeTokens drops tokens whose address (compared case-insensitively) already
+// appeared earlier in the list, keeping the first occurrence.
+func DedupeTokens(tokens []TokenConfig) []TokenConfig {
+	if len(tokens) == 0 {
+		return tokens
+	}
+	seen := make(map[string]bool)
+	deduped := make([]TokenConfig, 0, len(tokens))
+	for _, t := range tokens {
+		key := strings.ToLower(t.Address)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		deduped = append(deduped, t)
+	}
+	return deduped
+}
+
+// SaveConfig writes the config to path, backing up the previous file. A config
+// that was opened with a passphrase stays encrypted.
 func SaveConfig(addresses []AddressConfig, chains []ChainConfig, selectedIdx int, globalCfg GlobalConfig, path string) error {
+	data, err := encodeConfig(addresses, chains, selectedIdx, globalCfg)
+	if err != nil {
+		return err
+	}
+	if passphrase, ok := passphraseFor(path); ok {
+		if data, err = Encrypt(data, passphrase); err != nil {
+			return err
+		}
+	}
+
+	// Create a backup of the existing file
+	if _, err := os.Stat(path); err == nil {
+		backupPath := fmt.Sprintf("%s.%s.bak", path, time.Now().Format(backupTimeLayout))
+		input, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read existing config for backup: %w", err)
+		}
+		if err := os.WriteFile(backupPath, input, 0644); err != nil {
+			return fmt.Errorf("failed to write backup config: %w", err)
+		}
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// ExportConfig writes a copy of the config to path, readable only by its owner
+// since it may hold passwords, API keys and RPC headers. With a passphrase the
+// copy is encrypted.
+func ExportConfig(addresses []AddressConfig, chains []ChainConfig, selectedIdx int, globalCfg GlobalConfig, path, passphrase string) error {
+	data, err := encodeConfig(addresses, chains, selectedIdx, globalCfg)
+	if err != nil {
+		return err
+	}
+	if passphrase != "" {
+		if data, err = Encrypt(data, passphrase); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of a file it overwrites.
+	return os.Chmod(path, 0600)
+}
+
+// encodeConfig validates the config and marshals it to JSON.
+func encodeConfig(addresses []AddressConfig, chains []ChainConfig, selectedIdx int, globalCfg GlobalConfig) ([]byte, error) {
 	// Validation: Ensure we have at least one chain
 	if len(chains) == 0 {
-		return fmt.Errorf("validation failed: configuration must have at least one chain")
+		return nil, fmt.Errorf("validation failed: configuration must have at least one chain")
 	}
 
 	// Validation: Ensure chains have names and RPCs
 	for i, c := range chains {
 		if strings.TrimSpace(c.Name) == "" {
-			return fmt.Errorf("validation failed: chain at index %d has no name", i)
+			return nil, fmt.Errorf("validation failed: chain at index %d has no name", i)
 		}
 		if len(c.RPCURLs) == 0 {
-			return fmt.Errorf("validation failed: chain %s has no RPC URLs", c.Name)
+			return nil, fmt.Errorf("validation failed: chain %s has no RPC URLs", c.Name)
 		}
 	}
 
@@ -166,66 +411,236 @@ func SaveConfig(addresses []AddressConfig, chains []ChainConfig, selectedIdx int
 		selectedName = chains[selectedIdx].Name
 	}
 	cfg := struct {
-		Addresses                []AddressConfig `json:"addresses"`
-		Chains                   []ChainConfig   `json:"chains"`
-		SelectedChain            string          `json:"selected_chain"`
-		PrivacyTimeoutSeconds    int             `json:"privacy_timeout_seconds"`
-		FiatDecimals             int             `json:"fiat_decimals"`
-		TokenDecimals            int             `json:"token_decimals"`
-		AutoCycleEnabled         bool            `json:"auto_cycle_enabled"`
-		AutoCycleIntervalSeconds int             `json:"auto_cycle_interval_seconds"`
+		Addresses                 []AddressConfig `json:"addresses"`
+		Chains                    []ChainConfig   `json:"chains"`
+		SelectedChain             string          `json:"selected_chain"`
+		PrivacyTimeoutSeconds     int             `json:"privacy_timeout_seconds"`
+		FiatDecimals              int             `json:"fiat_decimals"`
+		TokenDecimals             int             `json:"token_decimals"`
+		AutoCycleEnabled          bool            `json:"auto_cycle_enabled"`
+		AutoCycleIntervalSeconds  int             `json:"auto_cycle_interval_seconds"`
+		CompactNumbers            bool            `json:"compact_numbers"`
+		HideZeroBalances          bool            `json:"hide_zero_balances"`
+		RequestsPerSecond         float64         `json:"requests_per_second,omitempty"`
+		HTTPProxy                 string          `json:"http_proxy,omitempty"`
+		ServerUsername            string          `json:"server_username,omitempty"`
+		ServerPassword            string          `json:"server_password,omitempty"`
+		ServerAllowedOrigins      []string        `json:"server_allowed_origins,omitempty"`
+		PauseWhenUnfocused        bool            `json:"pause_when_unfocused,omitempty"`
+		PrimaryChain              string          `json:"primary_chain,omitempty"`
+		BackupRetention           int             `json:"backup_retention,omitempty"`
+		NumberFormat              string          `json:"number_format,omitempty"`
+		FiatSymbol                string          `json:"fiat_symbol,omitempty"`
+		FiatSymbolSuffix          bool            `json:"fiat_symbol_suffix,omitempty"`
+		MainViewTxCount           int             `json:"main_view_tx_count,omitempty"`
+		HighValueUSD              float64         `json:"high_value_usd,omitempty"`
+		DustValueUSD              float64         `json:"dust_value_usd,omitempty"`
+		AutoCycleMode             string          `json:"auto_cycle_mode,omitempty"`
+		RoundingMode              string          `json:"rounding_mode,omitempty"`
+		MarkTinyValues            bool            `json:"mark_tiny_values,omitempty"`
+		MinDisplayValueUSD        float64         `json:"min_display_value_usd,omitempty"`
+		ResetAccountOnChainSwitch bool            `json:"reset_account_on_chain_switch,omitempty"`
+		LatencyHistoryPoints      int             `json:"latency_history_points,omitempty"`
 	}{
-		Addresses:                addresses,
-		Chains:                   chains,
-		SelectedChain:            selectedName,
-		PrivacyTimeoutSeconds:    globalCfg.PrivacyTimeoutSeconds,
-		FiatDecimals:             globalCfg.FiatDecimals,
-		TokenDecimals:            globalCfg.TokenDecimals,
-		AutoCycleEnabled:         globalCfg.AutoCycleEnabled,
-		AutoCycleIntervalSeconds: globalCfg.AutoCycleIntervalSeconds,
+		Addresses:                 addresses,
+		Chains:                    chains,
+		SelectedChain:             selectedName,
+		PrivacyTimeoutSeconds:     globalCfg.PrivacyTimeoutSeconds,
+		FiatDecimals:              globalCfg.FiatDecimals,
+		TokenDecimals:             globalCfg.TokenDecimals,
+		AutoCycleEnabled:          globalCfg.AutoCycleEnabled,
+		AutoCycleIntervalSeconds:  globalCfg.AutoCycleIntervalSeconds,
+		CompactNumbers:            globalCfg.CompactNumbers,
+		HideZeroBalances:          globalCfg.HideZeroBalances,
+		RequestsPerSecond:         globalCfg.RequestsPerSecond,
+		HTTPProxy:                 globalCfg.HTTPProxy,
+		ServerUsername:            globalCfg.ServerUsername,
+		ServerPassword:            globalCfg.ServerPassword,
+		ServerAllowedOrigins:      globalCfg.ServerAllowedOrigins,
+		PauseWhenUnfocused:        globalCfg.PauseWhenUnfocused,
+		PrimaryChain:              globalCfg.PrimaryChain,
+		BackupRetention:           globalCfg.BackupRetention,
+		NumberFormat:              globalCfg.NumberFormat,
+		FiatSymbol:                globalCfg.FiatSymbol,
+		FiatSymbolSuffix:          globalCfg.FiatSymbolSuffix,
+		MainViewTxCount:           globalCfg.MainViewTxCount,
+		HighValueUSD:              globalCfg.HighValueUSD,
+		DustValueUSD:              globalCfg.DustValueUSD,
+		AutoCycleMode:             globalCfg.AutoCycleMode,
+		RoundingMode:              globalCfg.RoundingMode,
+		MarkTinyValues:            globalCfg.MarkTinyValues,
+		MinDisplayValueUSD:        globalCfg.MinDisplayValueUSD,
+		ResetAccountOnChainSwitch: globalCfg.ResetAccountOnChainSwitch,
+		LatencyHistoryPoints:      globalCfg.LatencyHistoryPoints,
 	}
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if len(data) == 0 {
-		return fmt.Errorf("validation failed: encoded configuration is empty")
+		return nil, fmt.Errorf("validation failed: encoded configuration is empty")
 	}
+	return data, nil
+}
 
-	// Create a backup of the existing file
-	if _, err := os.Stat(path); err == nil {
-		backupPath := fmt.Sprintf("%s.%s.bak", path, time.Now().Format("20060102-150405"))
-		input, err := os.ReadFile(path)
+// BackupPaths returns the backups SaveConfig made of configPath, oldest first.
+func BackupPaths(configPath string) ([]string, error) {
+	matches, err := filepath.Glob(configPath + ".*.bak")
+	if err != nil {
+		return nil, err
+	}
+	// The timestamp in the name sorts chronologically.
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// DeleteBackup removes a single backup of configPath. It refuses paths that are
+// not one of configPath's backups.
+func DeleteBackup(configPath, backupPath string) error {
+	if ok, _ := filepath.Match(configPath+".*.bak", backupPath); !ok {
+		return fmt.Errorf("%s is not a backup of %s", backupPath, configPath)
+	}
+	return os.Remove(backupPath)
+}
+
+// PruneBackups deletes all but the keep most recent backups of configPath and
+// returns how many were removed.
+func PruneBackups(configPath string, keep int) (int, error) {
+	matches, err := BackupPaths(configPath)
+	if err != nil {
+		return 0, err
+	}
+	if keep < 0 {
+		keep = 0
+	}
+	removed := 0
+	for i := 0; i < len(matches)-keep; i++ {
+		if err := os.Remove(matches[i]); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// backupTimeLayout is the timestamp SaveConfig puts in backup file names.
+const backupTimeLayout = "20060102-150405"
+
+// BackupInfo describes one config backup.
+type BackupInfo struct {
+	Path      string
+	Timestamp time.Time // When the backup was taken, from its name or else its modification time
+	Size      int64
+}
+
+// ListBackups returns the backups of configPath, newest first.
+func ListBackups(configPath string) ([]BackupInfo, error) {
+	paths, err := BackupPaths(configPath)
+	if err != nil {
+		return nil, err
+	}
+	backups := make([]BackupInfo, 0, len(paths))
+	for i := len(paths) - 1; i >= 0; i-- {
+		st, err := os.Stat(paths[i])
 		if err != nil {
-			return fmt.Errorf("failed to read existing config for backup: %w", err)
+			continue
 		}
-		if err := os.WriteFile(backupPath, input, 0644); err != nil {
-			return fmt.Errorf("failed to write backup config: %w", err)
+		info := BackupInfo{Path: paths[i], Timestamp: st.ModTime(), Size: st.Size()}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(paths[i], configPath+"."), ".bak")
+		if ts, err := time.ParseInLocation(backupTimeLayout, stamp, time.Local); err == nil {
+			info.Timestamp = ts
 		}
+		backups = append(backups, info)
 	}
+	return backups, nil
+}
 
-	tmpPath := path + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+// RestoreBackup replaces configPath with the contents of one of its backups.
+func RestoreBackup(configPath, backupPath string) error {
+	if ok, _ := filepath.Match(configPath+".*.bak", backupPath); !ok {
+		return fmt.Errorf("%s is not a backup of %s", backupPath, configPath)
+	}
+	data, err := os.ReadFile(backupPath)
+	if err != nil {
 		return err
 	}
-	return os.Rename(tmpPath, path)
+	return os.WriteFile(configPath, data, 0644)
 }
 
 func RestoreLastBackup(configPath string) error {
-	matches, err := filepath.Glob(configPath + ".*.bak")
+	matches, err := BackupPaths(configPath)
 	if err != nil {
 		return err
 	}
 	if len(matches) == 0 {
 		return fmt.Errorf("no backup files found")
 	}
-	sort.Strings(matches)
-	lastBackup := matches[len(matches)-1]
+	return RestoreBackup(configPath, matches[len(matches)-1])
+}
 
-	data, err := os.ReadFile(lastBackup)
-	if err != nil {
-		return err
+// ConfigDiff counts what switching from one config to another would add and
+// remove. Addresses and token contracts compare case-insensitively, chains by name.
+type ConfigDiff struct {
+	AddressesAdded, AddressesRemoved int
+	ChainsAdded, ChainsRemoved       int
+	TokensAdded, TokensRemoved       int
+}
+
+// IsEmpty reports whether the two configs have the same addresses, chains and tokens.
+func (d ConfigDiff) IsEmpty() bool {
+	return d == ConfigDiff{}
+}
+
+// DiffConfigs compares the addresses, chains and tokens of the from config with
+// those of the to config.
+func DiffConfigs(fromAddrs []AddressConfig, fromChains []ChainConfig, toAddrs []AddressConfig, toChains []ChainConfig) ConfigDiff {
+	var d ConfigDiff
+	d.AddressesAdded, d.AddressesRemoved = diffKeys(addressKeys(fromAddrs), addressKeys(toAddrs))
+	d.ChainsAdded, d.ChainsRemoved = diffKeys(chainKeys(fromChains), chainKeys(toChains))
+	d.TokensAdded, d.TokensRemoved = diffKeys(tokenKeys(fromChains), tokenKeys(toChains))
+	return d
+}
+
+func addressKeys(addrs []AddressConfig) map[string]bool {
+	keys := make(map[string]bool, len(addrs))
+	for _, a := range addrs {
+		keys[strings.ToLower(a.Address)] = true
 	}
-	return os.WriteFile(configPath, data, 0644)
+	return keys
+}
+
+func chainKeys(chains []ChainConfig) map[string]bool {
+	keys := make(map[string]bool, len(chains))
+	for _, c := range chains {
+		keys[c.Name] = true
+	}
+	return keys
+}
+
+// tokenKeys keys each token by its chain and contract, so the same contract on
+// two chains counts twice.
+func tokenKeys(chains []ChainConfig) map[string]bool {
+	keys := make(map[string]bool)
+	for _, c := range chains {
+		for _, t := range c.Tokens {
+			keys[c.Name+"/"+strings.ToLower(t.Address)] = true
+		}
+	}
+	return keys
+}
+
+// diffKeys returns how many keys are only in to (added) and only in from (removed).
+func diffKeys(from, to map[string]bool) (added, removed int) {
+	for k := range to {
+		if !from[k] {
+			added++
+		}
+	}
+	for k := range from {
+		if !to[k] {
+			removed++
+		}
+	}
+	return added, removed
 }
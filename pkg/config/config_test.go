@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoadConfig_Malformed(t *testing.T) {
@@ -119,6 +120,39 @@ func TestLoadConfig_TableDriven(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Address Baselines",
+			jsonContent: `{
+				"addresses": [{"address": "0x123", "baseline_balances": {"Eth": "1.5"}}],
+				"chains": [{"name": "Eth", "rpc_urls": ["http://eth"]}]
+			}`,
+			expectError: false,
+			validate: func(t *testing.T, addrs []AddressConfig, chains []ChainConfig, g GlobalConfig) {
+				if len(addrs) != 1 || addrs[0].BaselineBalances["Eth"] != "1.5" {
+					t.Errorf("Baseline mismatch: %+v", addrs)
+				}
+			},
+		},
+		{
+			name: "Duplicate Tokens",
+			jsonContent: `{
+				"addresses": ["0x123"],
+				"chains": [{"name": "Eth", "rpc_urls": ["http://eth"], "tokens": [
+					{"symbol": "USDC", "address": "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", "decimals": 6},
+					{"symbol": "USDC", "address": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", "decimals": 6},
+					{"symbol": "USDT", "address": "0xdAC17F958D2ee523a2206206994597C13D831ec7", "decimals": 6}
+				]}]
+			}`,
+			expectError: false,
+			validate: func(t *testing.T, addrs []AddressConfig, chains []ChainConfig, g GlobalConfig) {
+				if len(chains) != 1 || len(chains[0].Tokens) != 2 {
+					t.Fatalf("Expected 2 tokens after dedupe, got %+v", chains)
+				}
+				if chains[0].Tokens[0].Address != "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48" || chains[0].Tokens[1].Symbol != "USDT" {
+					t.Errorf("Unexpected tokens after dedupe: %+v", chains[0].Tokens)
+				}
+			},
+		},
 		{
 			name:        "Malformed JSON",
 			jsonContent: `{ "addresses": [ unclosed_array`,
@@ -166,6 +200,42 @@ func TestLoadConfig_TableDriven(t *testing.T) {
 	}
 }
 
+func TestChainConfig_GasLevels(t *testing.T) {
+	tests := []struct {
+		thresholds [2]float64
+		low, high  float64
+	}{
+		{[2]float64{}, DefaultGasLowGwei, DefaultGasHighGwei},
+		{[2]float64{0.05, 0.5}, 0.05, 0.5},
+		{[2]float64{50, 10}, DefaultGasLowGwei, DefaultGasHighGwei},
+	}
+	for _, tt := range tests {
+		low, high := ChainConfig{GasThresholds: tt.thresholds}.GasLevels()
+		if low != tt.low || high != tt.high {
+			t.Errorf("GasLevels(%v) = %v, %v; want %v, %v", tt.thresholds, low, high, tt.low, tt.high)
+		}
+	}
+
+	// Unset thresholds stay out of saved configs.
+	data, err := encodeConfig(nil, []ChainConfig{{Name: "Eth", RPCURLs: []string{"http://x"}}}, 0, GlobalConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "gas_thresholds") {
+		t.Errorf("saved config contains unset gas_thresholds: %s", data)
+	}
+}
+
+func TestChainConfig_ActiveRPCURLs(t *testing.T) {
+	c := ChainConfig{RPCURLs: []string{"http://a", "http://b", "http://c"}, DisabledRPCs: []string{"http://b"}}
+	if got := c.ActiveRPCURLs(); strings.Join(got, ",") != "http://a,http://c" {
+		t.Errorf("ActiveRPCURLs() = %v", got)
+	}
+	if !c.RPCDisabled("http://b") || c.RPCDisabled("http://a") {
+		t.Error("RPCDisabled does not match DisabledRPCs")
+	}
+}
+
 func TestSaveConfig_PermissionError(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "readonly_test")
 	if err != nil {
@@ -189,3 +259,144 @@ func TestSaveConfig_PermissionError(t *testing.T) {
 		t.Error("Expected permission error, got nil")
 	}
 }
+
+func writeBackups(t *testing.T, configPath string, stamps ...string) []string {
+	t.Helper()
+	var paths []string
+	for _, s := range stamps {
+		p := configPath + "." + s + ".bak"
+		if err := os.WriteFile(p, []byte(`{}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+func TestPruneBackups(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	paths := writeBackups(t, configPath, "20240103-000000", "20240101-000000", "20240102-000000")
+
+	removed, err := PruneBackups(configPath, 1)
+	if err != nil {
+		t.Fatalf("PruneBackups failed: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Expected 2 backups removed, got %d", removed)
+	}
+	left, _ := BackupPaths(configPath)
+	if len(left) != 1 || left[0] != paths[0] {
+		t.Errorf("Expected only the newest backup to remain, got %v", left)
+	}
+
+	removed, err = PruneBackups(configPath, 0)
+	if err != nil || removed != 1 {
+		t.Errorf("Expected the last backup removed with retention 0, got %d (%v)", removed, err)
+	}
+}
+
+func TestDeleteBackup(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	paths := writeBackups(t, configPath, "20240101-000000")
+
+	other := filepath.Join(dir, "other.json")
+	if err := os.WriteFile(other, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteBackup(configPath, other); err == nil {
+		t.Error("Expected an error deleting a file that is not a backup")
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("Non-backup file should be untouched: %v", err)
+	}
+
+	if err := DeleteBackup(configPath, paths[0]); err != nil {
+		t.Fatalf("DeleteBackup failed: %v", err)
+	}
+	if left, _ := BackupPaths(configPath); len(left) != 0 {
+		t.Errorf("Expected no backups left, got %v", left)
+	}
+}
+
+func TestListBackupsAndRestore(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	paths := writeBackups(t, configPath, "20240101-000000", "20240102-120000")
+	if err := os.WriteFile(paths[0], []byte(`{"addresses":[]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, err := ListBackups(configPath)
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if len(backups) != 2 || backups[0].Path != paths[1] || backups[1].Path != paths[0] {
+		t.Fatalf("Expected backups newest first, got %+v", backups)
+	}
+	want := time.Date(2024, 1, 2, 12, 0, 0, 0, time.Local)
+	if !backups[0].Timestamp.Equal(want) {
+		t.Errorf("Expected timestamp %v, got %v", want, backups[0].Timestamp)
+	}
+	if backups[1].Size != int64(len(`{"addresses":[]}`)) {
+		t.Errorf("Expected size %d, got %d", len(`{"addresses":[]}`), backups[1].Size)
+	}
+
+	if err := RestoreBackup(configPath, backups[1].Path); err != nil {
+		t.Fatalf("RestoreBackup failed: %v", err)
+	}
+	if data, _ := os.ReadFile(configPath); string(data) != `{"addresses":[]}` {
+		t.Errorf("Expected the older backup restored, got %s", data)
+	}
+	if err := RestoreBackup(configPath, configPath); err == nil {
+		t.Error("Expected an error restoring a file that is not a backup")
+	}
+}
+
+func TestDiffConfigs(t *testing.T) {
+	from := []ChainConfig{
+		{Name: "Eth", Tokens: []TokenConfig{{Symbol: "USDC", Address: "0xA0b8"}, {Symbol: "DAI", Address: "0x6B17"}}},
+		{Name: "Base"},
+	}
+	to := []ChainConfig{
+		{Name: "Eth", Tokens: []TokenConfig{{Symbol: "USDC", Address: "0xa0b8"}}},
+		{Name: "Arbitrum", Tokens: []TokenConfig{{Symbol: "ARB", Address: "0x912C"}}},
+	}
+	fromAddrs := []AddressConfig{{Address: "0xAAAA"}, {Address: "0xBBBB"}}
+	toAddrs := []AddressConfig{{Address: "0xaaaa"}}
+
+	got := DiffConfigs(fromAddrs, from, toAddrs, to)
+	want := ConfigDiff{AddressesRemoved: 1, ChainsAdded: 1, ChainsRemoved: 1, TokensAdded: 1, TokensRemoved: 1}
+	if got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+	if !DiffConfigs(fromAddrs, from, fromAddrs, from).IsEmpty() {
+		t.Error("Expected no difference between identical configs")
+	}
+}
+
+func TestWriteDefaultConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := WriteDefaultConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	addrs, chains, _, _, err := LoadConfigFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addrs) != 0 || len(chains) < 4 {
+		t.Errorf("got %d addresses and %d chains, want none and the popular chains", len(addrs), len(chains))
+	}
+	for _, c := range chains {
+		if len(c.RPCURLs) == 0 || c.Symbol == "" || c.CoinGeckoID == "" || c.ChainID == 0 {
+			t.Errorf("default chain %s is incomplete: %+v", c.Name, c)
+		}
+	}
+	data, _ := os.ReadFile(path)
+	if unknown, _ := UnknownFields(data); len(unknown) > 0 {
+		t.Errorf("default config has unknown keys %v", unknown)
+	}
+
+	if err := WriteDefaultConfig(path); err == nil {
+		t.Error("expected an error when the config already exists")
+	}
+}
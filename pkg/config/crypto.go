@@ -0,0 +1,160 @@
+package config
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// EncryptedExtension is the file extension used for encrypted config exports.
+const EncryptedExtension = ".enc"
+
+// encryptedMagic starts every encrypted config, followed by the scrypt salt,
+// the AES-GCM nonce and the sealed JSON.
+var encryptedMagic = []byte("EVMBALENC1")
+
+const (
+	saltSize = 16
+	// scrypt parameters recommended for interactive logins.
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+var (
+	// ErrEncryptedConfig is returned when a config is encrypted and no passphrase
+	// is available to open it.
+	ErrEncryptedConfig = errors.New("config is encrypted and no passphrase was given")
+	// ErrWrongPassphrase is returned when an encrypted config fails to decrypt.
+	ErrWrongPassphrase = errors.New("wrong passphrase or corrupted file")
+)
+
+// PassphrasePrompt, when set, is asked for the passphrase of an encrypted config
+// that LoadConfigFromFile cannot open with a passphrase it has already seen.
+var PassphrasePrompt func(path string) (string, error)
+
+var (
+	passphraseMu sync.Mutex
+	// passphrases remembers what opened each encrypted config, so SaveConfig
+	// keeps it encrypted and its backups open without asking again.
+	passphrases = make(map[string]string)
+)
+
+// IsEncrypted reports whether data is an encrypted config.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedMagic)
+}
+
+// Encrypt seals plaintext with AES-256-GCM under a key derived from passphrase
+// with scrypt.
+func Encrypt(plaintext []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := append([]byte{}, encryptedMagic...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	// The header is authenticated so it cannot be swapped out.
+	return gcm.Seal(out, nonce, plaintext, out), nil
+}
+
+// Decrypt opens data produced by Encrypt.
+func Decrypt(data []byte, passphrase string) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return nil, fmt.Errorf("not an encrypted config")
+	}
+	rest := data[len(encryptedMagic):]
+	if len(rest) < saltSize {
+		return nil, ErrWrongPassphrase
+	}
+	salt := rest[:saltSize]
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	headerLen := len(encryptedMagic) + saltSize + gcm.NonceSize()
+	if len(data) < headerLen {
+		return nil, ErrWrongPassphrase
+	}
+	nonce := data[headerLen-gcm.NonceSize() : headerLen]
+	plaintext, err := gcm.Open(nil, nonce, data[headerLen:], data[:headerLen])
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plaintext, nil
+}
+
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// decryptConfig opens the encrypted config read from path, trying passphrases
+// that opened other configs before asking PassphrasePrompt.
+func decryptConfig(path string, data []byte) ([]byte, error) {
+	passphraseMu.Lock()
+	known := make([]string, 0, len(passphrases)+1)
+	if p, ok := passphrases[path]; ok {
+		known = append(known, p)
+	}
+	for _, p := range passphrases {
+		known = append(known, p)
+	}
+	passphraseMu.Unlock()
+
+	for _, p := range known {
+		if plaintext, err := Decrypt(data, p); err == nil {
+			rememberPassphrase(path, p)
+			return plaintext, nil
+		}
+	}
+	if PassphrasePrompt == nil {
+		return nil, ErrEncryptedConfig
+	}
+	p, err := PassphrasePrompt(path)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := Decrypt(data, p)
+	if err != nil {
+		return nil, err
+	}
+	rememberPassphrase(path, p)
+	return plaintext, nil
+}
+
+func rememberPassphrase(path, passphrase string) {
+	passphraseMu.Lock()
+	defer passphraseMu.Unlock()
+	passphrases[path] = passphrase
+}
+
+func passphraseFor(path string) (string, bool) {
+	passphraseMu.Lock()
+	defer passphraseMu.Unlock()
+	p, ok := passphrases[path]
+	return p, ok
+}
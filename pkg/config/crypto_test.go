@@ -0,0 +1,101 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	plaintext := []byte(`{"addresses":[{"address":"0x1111","name":"Cold"}]}`)
+	data, err := Encrypt(plaintext, "hunter2")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if !IsEncrypted(data) {
+		t.Fatal("Expected encrypted data to be detected")
+	}
+	if bytes.Contains(data, []byte("Cold")) {
+		t.Error("Encrypted data contains plaintext")
+	}
+
+	got, err := Decrypt(data, "hunter2")
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("Expected %s, got %s", plaintext, got)
+	}
+	if _, err := Decrypt(data, "wrong"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Expected ErrWrongPassphrase, got %v", err)
+	}
+	data[len(data)-1] ^= 1
+	if _, err := Decrypt(data, "hunter2"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Expected tampered data to fail, got %v", err)
+	}
+}
+
+func TestExportConfig_PlainIsOwnerOnly(t *testing.T) {
+	chains := []ChainConfig{{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://localhost:8545"}}}
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ExportConfig(nil, chains, 0, GlobalConfig{}, path, ""); err != nil {
+		t.Fatalf("ExportConfig failed: %v", err)
+	}
+	if st, err := os.Stat(path); err != nil || st.Mode().Perm() != 0600 {
+		t.Errorf("Expected an owner-only file, got %v %v", st, err)
+	}
+}
+
+func TestExportConfig_EncryptedRoundTrip(t *testing.T) {
+	passphrases = make(map[string]string)
+	t.Cleanup(func() {
+		passphrases = make(map[string]string)
+		PassphrasePrompt = nil
+	})
+
+	addrs := []AddressConfig{{Address: "0x1111111111111111111111111111111111111111", Name: "Cold"}}
+	chains := []ChainConfig{{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://localhost:8545"}}}
+	path := filepath.Join(t.TempDir(), "config.json.enc")
+	if err := ExportConfig(addrs, chains, 0, GlobalConfig{}, path, "hunter2"); err != nil {
+		t.Fatalf("ExportConfig failed: %v", err)
+	}
+	if st, err := os.Stat(path); err != nil || st.Mode().Perm() != 0600 {
+		t.Errorf("Expected an owner-only file, got %v %v", st, err)
+	}
+
+	if _, _, _, _, err := LoadConfigFromFile(path); !errors.Is(err, ErrEncryptedConfig) {
+		t.Fatalf("Expected ErrEncryptedConfig without a prompt, got %v", err)
+	}
+
+	prompts := 0
+	PassphrasePrompt = func(string) (string, error) {
+		prompts++
+		return "hunter2", nil
+	}
+	gotAddrs, gotChains, _, _, err := LoadConfigFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile failed: %v", err)
+	}
+	if len(gotAddrs) != 1 || gotAddrs[0].Name != "Cold" || len(gotChains) != 1 {
+		t.Errorf("Unexpected config: %+v %+v", gotAddrs, gotChains)
+	}
+
+	// Saving keeps the file encrypted, and reloading needs no second prompt.
+	if err := SaveConfig(addrs, chains, 0, GlobalConfig{}, path); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); !IsEncrypted(data) {
+		t.Error("Expected SaveConfig to keep the config encrypted")
+	}
+	if _, _, _, _, err := LoadConfigFromFile(path); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if prompts != 1 {
+		t.Errorf("Expected one prompt, got %d", prompts)
+	}
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+)
+
+// defaultConfig is written by -init: popular chains with public RPCs and no
+// addresses.
+//
+//go:embed default_config.json
+var defaultConfig []byte
+
+// DefaultChains returns the chains of the bundled default config.
+func DefaultChains() []ChainConfig {
+	_, chains, _, _, err := LoadConfig(bytes.NewReader(defaultConfig))
+	if err != nil {
+		panic(fmt.Sprintf("invalid bundled default config: %v", err))
+	}
+	return chains
+}
+
+// WriteDefaultConfig writes the bundled default config to path. It refuses to
+// overwrite an existing file.
+func WriteDefaultConfig(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if os.IsExist(err) {
+		return fmt.Errorf("%s already exists", path)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(defaultConfig); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// configFile mirrors the top level of a config file, which holds the global
+// settings alongside the addresses and chains.
+type configFile struct {
+	Addresses     []AddressConfig `json:"addresses"`
+	Chains        []ChainConfig   `json:"chains"`
+	SelectedChain string          `json:"selected_chain,omitempty"` // Name of the chain shown at startup
+	RPCURLs       []string        `json:"rpc_urls,omitempty"`       // Legacy single-chain configs
+	GlobalConfig
+}
+
+// schemaRequired lists the keys each config type cannot do without.
+var schemaRequired = map[reflect.Type][]string{
+	reflect.TypeOf(AddressConfig{}): {"address"},
+	reflect.TypeOf(ChainConfig{}):   {"name", "rpc_urls"},
+	reflect.TypeOf(TokenConfig{}):   {"symbol", "address", "decimals"},
+}
+
+// Schema returns a JSON Schema (draft 2020-12) for config files, derived from
+// the config types so editors can validate and autocomplete them.
+func Schema() map[string]interface{} {
+	s := typeSchema(reflect.TypeOf(configFile{}))
+	s["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	s["title"] = "evmbal configuration"
+
+	props := s["properties"].(map[string]interface{})
+	// Lets a config point editors at this schema.
+	props["$schema"] = map[string]interface{}{"type": "string"}
+	// Addresses may also be plain strings, as written by older versions.
+	addrs := props["addresses"].(map[string]interface{})
+	addrs["items"] = map[string]interface{}{
+		"oneOf": []interface{}{map[string]interface{}{"type": "string"}, addrs["items"]},
+	}
+	props["number_format"].(map[string]interface{})["enum"] = []string{"us", "eu"}
+	props["auto_cycle_mode"].(map[string]interface{})["enum"] = []string{"accounts", "chains", "both"}
+	props["rounding_mode"].(map[string]interface{})["enum"] = []string{"round", "truncate"}
+	return s
+}
+
+func typeSchema(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem())
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem()), "minItems": t.Len(), "maxItems": t.Len()}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		props := make(map[string]interface{})
+		addStructProperties(t, props)
+		s := map[string]interface{}{"type": "object", "properties": props, "additionalProperties": false}
+		if req, ok := schemaRequired[t]; ok {
+			s["required"] = req
+		}
+		return s
+	}
+	return map[string]interface{}{}
+}
+
+// addStructProperties adds a property per JSON field of t, inlining embedded
+// structs the way encoding/json does.
+func addStructProperties(t reflect.Type, props map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if f.Anonymous && tag == "" {
+			addStructProperties(f.Type, props)
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = typeSchema(f.Type)
+	}
+}
+
+// UnknownFields returns the keys in a config file that evmbal does not know,
+// e.g. misspelled settings, as paths like "chains[0].rpc_url". encoding/json
+// silently drops such keys; they are reported rather than rejected so configs
+// written by newer versions still load.
+func UnknownFields(data []byte) ([]string, error) {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	var unknown []string
+	collectUnknownFields(Schema(), raw, "", &unknown)
+	sort.Strings(unknown)
+	return unknown, nil
+}
+
+// UnknownFieldsInFile is UnknownFields for the config at path, decrypting it if
+// needed. A missing file has no unknown fields.
+func UnknownFieldsInFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if IsEncrypted(data) {
+		if data, err = decryptConfig(path, data); err != nil {
+			return nil, err
+		}
+	}
+	return UnknownFields(data)
+}
+
+func collectUnknownFields(s map[string]interface{}, v interface{}, path string, unknown *[]string) {
+	if oneOf, ok := s["oneOf"].([]interface{}); ok {
+		// Only the object form of a oneOf has keys to check.
+		s = oneOf[len(oneOf)-1].(map[string]interface{})
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		props, _ := s["properties"].(map[string]interface{})
+		for k, child := range v {
+			childPath := k
+			if path != "" {
+				childPath = path + "." + k
+			}
+			if props == nil {
+				if extra, ok := s["additionalProperties"].(map[string]interface{}); ok {
+					collectUnknownFields(extra, child, childPath, unknown)
+				}
+				continue
+			}
+			ps, ok := lookupProperty(props, k)
+			if !ok {
+				*unknown = append(*unknown, childPath)
+				continue
+			}
+			collectUnknownFields(ps, child, childPath, unknown)
+		}
+	case []interface{}:
+		items, _ := s["items"].(map[string]interface{})
+		for i, child := range v {
+			collectUnknownFields(items, child, fmt.Sprintf("%s[%d]", path, i), unknown)
+		}
+	}
+}
+
+// lookupProperty finds key in props, ignoring case like encoding/json does.
+func lookupProperty(props map[string]interface{}, key string) (map[string]interface{}, bool) {
+	if ps, ok := props[key].(map[string]interface{}); ok {
+		return ps, true
+	}
+	for name, ps := range props {
+		if strings.EqualFold(name, key) {
+			return ps.(map[string]interface{}), true
+		}
+	}
+	return nil, false
+}
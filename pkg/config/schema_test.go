@@ -0,0 +1,58 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestSchema_CoversExampleConfig(t *testing.T) {
+	data, err := os.ReadFile("../../example_config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unknown, err := UnknownFields(data); err != nil || len(unknown) > 0 {
+		t.Errorf("example config has keys missing from the schema: %v (err %v)", unknown, err)
+	}
+
+	// The schema must also cover everything SaveConfig writes.
+	flag := true
+	saved, err := encodeConfig(
+		[]AddressConfig{{Address: "0x1", Name: "a", BaselineBalances: map[string]string{"Eth": "1"}}},
+		[]ChainConfig{{Name: "Eth", RPCURLs: []string{"http://x"}, Enabled: &flag, GasThresholds: [2]float64{0.05, 0.5}, Tokens: []TokenConfig{{Symbol: "T", Address: "0x2", DisplayDecimals: new(int)}}}},
+		0,
+		GlobalConfig{NumberFormat: "eu", ServerAllowedOrigins: []string{"x"}, FiatSymbol: "kr"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unknown, err := UnknownFields(saved); err != nil || len(unknown) > 0 {
+		t.Errorf("saved config has keys missing from the schema: %v (err %v)", unknown, err)
+	}
+}
+
+func TestUnknownFields(t *testing.T) {
+	data := []byte(`{
+		"$schema": "./evmbal.schema.json",
+		"addresses": [{"address": "0x1"}, {"address": "0x2", "nmae": "typo"}],
+		"chains": [{"name": "Eth", "rpc_urls": ["http://x"], "rpc_url": "http://y",
+			"tokens": [{"symbol": "T", "address": "0x3", "decimals": 6, "Coingecko_ID": "t"}]}],
+		"privacy_timout_seconds": 30,
+		"Fiat_Decimals": 2
+	}`)
+	unknown, err := UnknownFields(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"addresses[1].nmae", "chains[0].rpc_url", "privacy_timout_seconds"}
+	if !reflect.DeepEqual(unknown, want) {
+		t.Errorf("UnknownFields = %v, want %v", unknown, want)
+	}
+
+	// Unknown keys are reported, not rejected.
+	addrs, chains, _, cfg, err := LoadConfig(bytes.NewReader(data))
+	if err != nil || len(addrs) != 2 || len(chains) != 1 || cfg.FiatDecimals != 2 {
+		t.Errorf("LoadConfig = %d addrs, %d chains, fiat decimals %d, err %v", len(addrs), len(chains), cfg.FiatDecimals, err)
+	}
+}
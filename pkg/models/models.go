@@ -15,17 +15,70 @@ type Transaction struct {
 	GasLimit    uint64
 	GasPrice    string
 	Nonce       uint64
+	TokenSymbol string // Set for ERC-20 transfers; Value is then in token units
+	Chain       string // Name of the chain the transaction is on
 }
 
 // Account holds the data for a single monitored address.
 type Account struct {
 	Address       string
 	Name          string
+	Note          string
 	Balances      map[string]*big.Float            // Key: Chain Name
 	TokenBalances map[string]map[string]*big.Float // Key: Chain Name -> Token Symbol
 	Balances24h   map[string]*big.Float            // Key: Chain Name
 	Errors        map[string]error                 // Key: Chain Name
+	Fetched       map[string]bool                  // Key: Chain Name; true once a balance has been confirmed
+	Baselines     map[string]*big.Float            // Key: Chain Name; user-pinned reference balance
 	Transactions  []Transaction
+	// ExcludeFromTotal leaves the account out of portfolio totals; it is still listed.
+	ExcludeFromTotal bool
+	// IsContract is set once code has been found at the address on any chain,
+	// e.g. a treasury or multisig rather than a wallet.
+	IsContract bool
+}
+
+// Clone returns a deep copy of the account, including its balance maps, so the copy
+// can be read while the original keeps being updated.
+func (a *Account) Clone() *Account {
+	c := &Account{
+		Address:          a.Address,
+		Name:             a.Name,
+		Note:             a.Note,
+		Balances:         cloneBalances(a.Balances),
+		TokenBalances:    make(map[string]map[string]*big.Float, len(a.TokenBalances)),
+		Balances24h:      cloneBalances(a.Balances24h),
+		Errors:           make(map[string]error, len(a.Errors)),
+		Fetched:          make(map[string]bool, len(a.Fetched)),
+		Baselines:        cloneBalances(a.Baselines),
+		Transactions:     append([]Transaction(nil), a.Transactions...),
+		ExcludeFromTotal: a.ExcludeFromTotal,
+		IsContract:       a.IsContract,
+	}
+	for chain, tokens := range a.TokenBalances {
+		c.TokenBalances[chain] = cloneBalances(tokens)
+	}
+	for k, v := range a.Errors {
+		c.Errors[k] = v
+	}
+	for k, v := range a.Fetched {
+		c.Fetched[k] = v
+	}
+	return c
+}
+
+func cloneBalances(m map[string]*big.Float) map[string]*big.Float {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]*big.Float, len(m))
+	for k, v := range m {
+		if v != nil {
+			v = new(big.Float).Copy(v)
+		}
+		c[k] = v
+	}
+	return c
 }
 
 // AccountChainData holds fetched data for an account on a specific chain.
@@ -34,6 +87,7 @@ type AccountChainData struct {
 	Balance       *big.Float
 	Balance24h    *big.Float
 	TokenBalances map[string]*big.Float
+	IsContract    bool // Code is deployed at the address on this chain
 }
 
 // ChainData contains the result of a bulk fetch for a chain.
@@ -46,18 +100,29 @@ type ChainData struct {
 
 // PriceData contains the current ETH price in USD.
 type PriceData struct {
-	CoinID string
-	Price  float64
-	Err    error
+	CoinID    string
+	Price     float64
+	Change24h float64 // Percentage change over 24h as reported by CoinGecko
+	Trend     int     // Direction relative to the previous fetch: 1 up, -1 down, 0 unchanged
+	UpdatedAt time.Time
+	Err       error
 }
 
 // GasPriceData contains the current gas price.
 type GasPriceData struct {
+	ChainName  string
 	Price      *big.Int
 	FailedRPCs []string
 	Err        error
 }
 
+// GasAlert is raised when a chain's gas price drops below its configured threshold.
+type GasAlert struct {
+	ChainName     string
+	GasGwei       float64
+	ThresholdGwei float64
+}
+
 // GasPricePoint holds a timestamped gas price value.
 type GasPricePoint struct {
 	Timestamp time.Time
@@ -66,47 +131,96 @@ type GasPricePoint struct {
 
 // RPCLatencyData contains the result of a latency check.
 type RPCLatencyData struct {
-	RPCURL  string
-	Latency time.Duration
-	Err     error
+	RPCURL      string
+	Latency     time.Duration
+	BlockNumber uint64    // Head reported by the RPC
+	BlockTime   time.Time // Timestamp of that head
+	Lagging     bool      // Head is stale or behind the other RPCs of the chain
+	Err         error
+}
+
+// RPCProbeData is the result of a full diagnostic of one RPC, run on demand
+// from the network status view.
+type RPCProbeData struct {
+	RPCURL       string
+	Latency      time.Duration // Round trip of the block number request
+	ChainID      int64
+	BlockNumber  uint64
+	Syncing      bool
+	CurrentBlock uint64 // Sync progress, when Syncing
+	HighestBlock uint64
+	SyncErr      error // eth_syncing failed; some providers do not serve it
+	Err          error
 }
 
 // TokenMetadata contains the result of a token metadata fetch.
 type TokenMetadata struct {
 	Symbol   string
+	Name     string
 	Decimals int
-	Err      error
+	Partial  bool // Some fields were inferred (symbol from name, default decimals)
+	// DecimalsDefaulted is set when decimals() could not be read and Decimals
+	// holds the default rather than the token's own value.
+	DecimalsDefaulted bool
+	RPCURL            string // RPC that answered
+	Err               error
 }
 
 // ChainResult holds test results for a specific chain.
 type ChainResult struct {
-	Name            string      `json:"name"`
-	Symbol          string      `json:"symbol"`
-	ConfigChainID   int64       `json:"config_chain_id"`
-	RPCs            []RPCResult `json:"rpcs"`
-	Inconsistent    bool        `json:"inconsistent"`
-	ChainIDUpdated  bool        `json:"chain_id_updated"`
-	ObservedChainID int64       `json:"observed_chain_id,omitempty"`
+	Name            string        `json:"name"`
+	Symbol          string        `json:"symbol"`
+	ConfigChainID   int64         `json:"config_chain_id"`
+	RPCs            []RPCResult   `json:"rpcs"`
+	Inconsistent    bool          `json:"inconsistent"`
+	ChainIDUpdated  bool          `json:"chain_id_updated"`
+	ObservedChainID int64         `json:"observed_chain_id,omitempty"`
+	Tokens          []TokenResult `json:"tokens,omitempty"`
+}
+
+// TokenResult holds test results for a configured token contract.
+type TokenResult struct {
+	Symbol             string `json:"symbol"`
+	Address            string `json:"address"`
+	Status             string `json:"status"` // "ok" or "error"
+	ObservedSymbol     string `json:"observed_symbol,omitempty"`
+	ConfiguredDecimals int    `json:"configured_decimals"`
+	OnChainDecimals    *int   `json:"on_chain_decimals,omitempty"` // Nil when decimals() could not be read
+	DecimalsMatch      bool   `json:"decimals_match"`
+	Error              string `json:"error,omitempty"`
 }
 
 // RPCResult holds test results for a specific RPC URL.
 type RPCResult struct {
 	URL     string `json:"url"`
-	Status  string `json:"status"` // "ok" or "error"
+	Status  string `json:"status"` // "ok", "error" or "disabled"
 	ChainID int64  `json:"chain_id,omitempty"`
 	Error   string `json:"error,omitempty"`
 }
 
 // TestReport holds the results of the configuration test.
 type TestReport struct {
-	ConfigPath         string        `json:"config_path"`
-	ValidStructure     bool          `json:"valid_structure"`
-	StructureErrors    []string      `json:"structure_errors,omitempty"`
-	AddressCount       int           `json:"address_count"`
-	ChainCount         int           `json:"chain_count"`
-	Chains             []ChainResult `json:"chains,omitempty"`
-	InconsistentChains []string      `json:"inconsistent_chains,omitempty"`
-	ConfigUpdated      bool          `json:"config_updated"`
-	SaveError          string        `json:"save_error,omitempty"`
-	DryRun             bool          `json:"dry_run"`
+	ConfigPath         string          `json:"config_path"`
+	ValidStructure     bool            `json:"valid_structure"`
+	StructureErrors    []string        `json:"structure_errors,omitempty"`
+	UnknownKeys        []string        `json:"unknown_keys,omitempty"` // Keys evmbal ignores, e.g. typos
+	AddressCount       int             `json:"address_count"`
+	ChainCount         int             `json:"chain_count"`
+	Chains             []ChainResult   `json:"chains,omitempty"`
+	InconsistentChains []string        `json:"inconsistent_chains,omitempty"`
+	ConfigUpdated      bool            `json:"config_updated"`
+	SaveError          string          `json:"save_error,omitempty"`
+	DryRun             bool            `json:"dry_run"`
+	Accounts           []AccountResult `json:"accounts,omitempty"` // Only with -accounts
+}
+
+// AccountResult holds the result of a balance lookup for one address on one chain.
+type AccountResult struct {
+	Address    string `json:"address"`
+	Name       string `json:"name,omitempty"`
+	Chain      string `json:"chain"`
+	Status     string `json:"status"`                // "ok" or "error"
+	Balance    string `json:"balance,omitempty"`     // Whole coins, rounded to 6 decimals
+	BalanceWei string `json:"balance_wei,omitempty"` // Exact
+	Error      string `json:"error,omitempty"`
 }
@@ -0,0 +1,132 @@
+// Package portfolio values account balances in USD. Prices are keyed by CoinGecko
+// ID, so chains sharing a native asset (e.g. L2s using ETH) share one price.
+package portfolio
+
+import (
+	"math"
+	"math/big"
+
+	"evmbal/pkg/config"
+	"evmbal/pkg/models"
+)
+
+// Prec is the mantissa precision, in bits, of values and totals. Balances can
+// be 10^30 tokens or more, far beyond the 53 bits big.NewFloat would carry over
+// from a float64, so sums keep every digit down to the cent.
+const Prec = 256
+
+// NewTotal returns a zero value with Prec bits to accumulate values in.
+func NewTotal() *big.Float {
+	return new(big.Float).SetPrec(Prec)
+}
+
+// ChainValue is an account's USD value on one chain. Balances without a price
+// are left out.
+type ChainValue struct {
+	Chain  string
+	Native *big.Float            // Value of the native balance; nil if unpriced or not fetched
+	Tokens map[string]*big.Float // Key: Token Symbol
+	Total  *big.Float
+}
+
+// Value returns bal priced at prices[coinID], or nil if the balance or price is
+// missing. A NaN or infinite price counts as missing: big.Float cannot hold NaN,
+// and one infinite value would swamp every total it is added to.
+func Value(bal *big.Float, prices map[string]float64, coinID string) *big.Float {
+	if bal == nil {
+		return nil
+	}
+	price, ok := prices[coinID]
+	if !ok || math.IsNaN(price) || math.IsInf(price, 0) {
+		return nil
+	}
+	return NewTotal().Mul(bal, big.NewFloat(price))
+}
+
+// ChainValues returns the account's value on each of chains, in order. Native
+// balances use the chain's CoinGeckoID and tokens their PriceID.
+func ChainValues(acc *models.Account, chains []config.ChainConfig, prices map[string]float64) []ChainValue {
+	values := make([]ChainValue, 0, len(chains))
+	for _, chain := range chains {
+		cv := ChainValue{Chain: chain.Name, Tokens: make(map[string]*big.Float), Total: NewTotal()}
+		if v := Value(acc.Balances[chain.Name], prices, chain.CoinGeckoID); v != nil {
+			cv.Native = v
+			cv.Total.Add(cv.Total, v)
+		}
+		tokens := acc.TokenBalances[chain.Name]
+		for _, t := range chain.Tokens {
+			if v := Value(tokens[t.Symbol], prices, t.PriceID()); v != nil {
+				cv.Tokens[t.Symbol] = v
+				cv.Total.Add(cv.Total, v)
+			}
+		}
+		values = append(values, cv)
+	}
+	return values
+}
+
+// AccountTotal returns the account's value across all chains.
+func AccountTotal(acc *models.Account, chains []config.ChainConfig, prices map[string]float64) *big.Float {
+	total := NewTotal()
+	for _, cv := range ChainValues(acc, chains, prices) {
+		total.Add(total, cv.Total)
+	}
+	return total
+}
+
+// Total returns the combined value of all accounts, leaving out those marked
+// ExcludeFromTotal.
+func Total(accounts []*models.Account, chains []config.ChainConfig, prices map[string]float64) *big.Float {
+	total := NewTotal()
+	for _, acc := range accounts {
+		if acc.ExcludeFromTotal {
+			continue
+		}
+		total.Add(total, AccountTotal(acc, chains, prices))
+	}
+	return total
+}
+
+// ChainTotals returns the combined value of accounts on each of chains, in
+// order, leaving out accounts marked ExcludeFromTotal.
+func ChainTotals(accounts []*models.Account, chains []config.ChainConfig, prices map[string]float64) []ChainValue {
+	totals := make([]ChainValue, len(chains))
+	for i, chain := range chains {
+		totals[i] = ChainValue{Chain: chain.Name, Tokens: make(map[string]*big.Float), Total: NewTotal()}
+	}
+	for _, acc := range accounts {
+		if acc.ExcludeFromTotal {
+			continue
+		}
+		for i, cv := range ChainValues(acc, chains, prices) {
+			totals[i].Total.Add(totals[i].Total, cv.Total)
+			if cv.Native != nil {
+				if totals[i].Native == nil {
+					totals[i].Native = NewTotal()
+				}
+				totals[i].Native.Add(totals[i].Native, cv.Native)
+			}
+			for sym, v := range cv.Tokens {
+				if totals[i].Tokens[sym] == nil {
+					totals[i].Tokens[sym] = NewTotal()
+				}
+				totals[i].Tokens[sym].Add(totals[i].Tokens[sym], v)
+			}
+		}
+	}
+	return totals
+}
+
+// Positions returns the part of Total held in custom positions, i.e. tokens
+// configured with Custom such as staked or LP balances.
+func Positions(accounts []*models.Account, chains []config.ChainConfig, prices map[string]float64) *big.Float {
+	total := NewTotal()
+	for i, ct := range ChainTotals(accounts, chains, prices) {
+		for _, t := range chains[i].Tokens {
+			if v := ct.Tokens[t.Symbol]; t.Custom && v != nil {
+				total.Add(total, v)
+			}
+		}
+	}
+	return total
+}
@@ -0,0 +1,149 @@
+package portfolio
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"evmbal/pkg/config"
+	"evmbal/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func toFloat(f *big.Float) float64 {
+	v, _ := f.Float64()
+	return v
+}
+
+var testChains = []config.ChainConfig{
+	{Name: "Ethereum", CoinGeckoID: "ethereum", Tokens: []config.TokenConfig{
+		{Symbol: "USDC", CoinGeckoID: "usd-coin"},
+		{Symbol: "USDC.e", CoinGeckoID: "bridged-usdc", PriceAlias: "usd-coin"},
+	}},
+	{Name: "Base", CoinGeckoID: "ethereum"},
+	{Name: "Polygon", CoinGeckoID: "polygon-ecosystem-token"},
+}
+
+func TestChainValues_MultiChain(t *testing.T) {
+	acc := &models.Account{
+		Balances: map[string]*big.Float{"Ethereum": big.NewFloat(1), "Base": big.NewFloat(0.5)},
+		TokenBalances: map[string]map[string]*big.Float{
+			"Ethereum": {"USDC": big.NewFloat(100), "USDC.e": big.NewFloat(50)},
+		},
+	}
+	prices := map[string]float64{"ethereum": 2000, "usd-coin": 1}
+
+	values := ChainValues(acc, testChains, prices)
+	assert.Len(t, values, 3)
+	assert.Equal(t, 2000.0, toFloat(values[0].Native))
+	assert.Equal(t, 100.0, toFloat(values[0].Tokens["USDC"]))
+	assert.Equal(t, 50.0, toFloat(values[0].Tokens["USDC.e"]), "tokens are priced by PriceID")
+	assert.Equal(t, 2150.0, toFloat(values[0].Total))
+	assert.Equal(t, 1000.0, toFloat(values[1].Total), "chains sharing a native asset share its price")
+	assert.Nil(t, values[2].Native)
+	assert.Equal(t, 0.0, toFloat(values[2].Total))
+
+	assert.Equal(t, 3150.0, toFloat(AccountTotal(acc, testChains, prices)))
+	assert.Equal(t, 6300.0, toFloat(Total([]*models.Account{acc, acc}, testChains, prices)))
+}
+
+func TestChainValues_MissingPrices(t *testing.T) {
+	acc := &models.Account{
+		Balances:      map[string]*big.Float{"Ethereum": big.NewFloat(1), "Polygon": big.NewFloat(10)},
+		TokenBalances: map[string]map[string]*big.Float{"Ethereum": {"USDC": big.NewFloat(100)}},
+	}
+	prices := map[string]float64{"usd-coin": 1}
+
+	values := ChainValues(acc, testChains, prices)
+	assert.Nil(t, values[0].Native, "unpriced native balances are left out")
+	assert.Equal(t, 100.0, toFloat(values[0].Total))
+	assert.Equal(t, 100.0, toFloat(AccountTotal(acc, testChains, prices)))
+	assert.Equal(t, 0.0, toFloat(AccountTotal(acc, testChains, nil)))
+}
+
+func TestChainValues_NilBalances(t *testing.T) {
+	acc := &models.Account{
+		Balances:      map[string]*big.Float{"Ethereum": nil},
+		TokenBalances: map[string]map[string]*big.Float{"Ethereum": {"USDC": nil}},
+	}
+	prices := map[string]float64{"ethereum": 2000, "usd-coin": 1}
+
+	assert.NotPanics(t, func() {
+		assert.Equal(t, 0.0, toFloat(AccountTotal(acc, testChains, prices)))
+	})
+	assert.Equal(t, 0.0, toFloat(AccountTotal(&models.Account{}, testChains, prices)), "accounts with no fetched data are worth nothing")
+	assert.Nil(t, Value(nil, prices, "ethereum"))
+}
+
+func TestTotal_ExcludeFromTotal(t *testing.T) {
+	acc := &models.Account{Balances: map[string]*big.Float{"Ethereum": big.NewFloat(1)}}
+	treasury := &models.Account{Balances: map[string]*big.Float{"Ethereum": big.NewFloat(100)}, ExcludeFromTotal: true}
+	prices := map[string]float64{"ethereum": 2000}
+
+	assert.Equal(t, 2000.0, toFloat(Total([]*models.Account{acc, treasury}, testChains, prices)))
+	assert.Equal(t, 200000.0, toFloat(AccountTotal(treasury, testChains, prices)), "the account's own value is still known")
+}
+
+func TestChainTotals(t *testing.T) {
+	a := &models.Account{
+		Balances:      map[string]*big.Float{"Ethereum": big.NewFloat(1), "Base": big.NewFloat(2)},
+		TokenBalances: map[string]map[string]*big.Float{"Ethereum": {"USDC": big.NewFloat(10)}},
+	}
+	b := &models.Account{Balances: map[string]*big.Float{"Ethereum": big.NewFloat(1)}}
+	excluded := &models.Account{Balances: map[string]*big.Float{"Ethereum": big.NewFloat(50)}, ExcludeFromTotal: true}
+	prices := map[string]float64{"ethereum": 2000, "usd-coin": 1}
+
+	totals := ChainTotals([]*models.Account{a, b, excluded}, testChains, prices)
+	assert.Len(t, totals, 3)
+	assert.Equal(t, 4010.0, toFloat(totals[0].Total))
+	assert.Equal(t, 4000.0, toFloat(totals[0].Native))
+	assert.Equal(t, 10.0, toFloat(totals[0].Tokens["USDC"]))
+	assert.Equal(t, 4000.0, toFloat(totals[1].Total))
+	assert.Nil(t, totals[2].Native)
+}
+
+func TestPositions(t *testing.T) {
+	chains := []config.ChainConfig{{
+		Name:        "Ethereum",
+		CoinGeckoID: "ethereum",
+		Tokens: []config.TokenConfig{
+			{Symbol: "USDC", CoinGeckoID: "usd-coin"},
+			{Symbol: "stETH-pool", PriceAlias: "ethereum", Custom: true},
+		},
+	}}
+	acc := &models.Account{
+		Balances:      map[string]*big.Float{"Ethereum": big.NewFloat(1)},
+		TokenBalances: map[string]map[string]*big.Float{"Ethereum": {"USDC": big.NewFloat(10), "stETH-pool": big.NewFloat(2)}},
+	}
+	excluded := &models.Account{
+		TokenBalances:    map[string]map[string]*big.Float{"Ethereum": {"stETH-pool": big.NewFloat(5)}},
+		ExcludeFromTotal: true,
+	}
+	prices := map[string]float64{"ethereum": 2000, "usd-coin": 1}
+
+	accounts := []*models.Account{acc, excluded}
+	assert.Equal(t, 4000.0, toFloat(Positions(accounts, chains, prices)))
+	assert.Equal(t, 6010.0, toFloat(Total(accounts, chains, prices)), "positions are part of the total")
+}
+
+func TestTotal_HugeTokenBalance(t *testing.T) {
+	// 10^30 tokens with 18 decimals, converted the way the RPC client does.
+	wei := new(big.Int).Exp(big.NewInt(10), big.NewInt(48), nil)
+	huge := new(big.Float).Quo(new(big.Float).SetInt(wei), new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)))
+	acc := &models.Account{
+		Balances:      map[string]*big.Float{"Ethereum": big.NewFloat(1)},
+		TokenBalances: map[string]map[string]*big.Float{"Ethereum": {"USDC": huge}},
+	}
+	prices := map[string]float64{"ethereum": 3, "usd-coin": 0.5}
+
+	total := Total([]*models.Account{acc}, testChains, prices)
+	assert.False(t, total.IsInf())
+	assert.Equal(t, "500000000000000000000000000003.00", total.Text('f', 2), "no precision is lost to float64")
+}
+
+func TestValue_InvalidPrice(t *testing.T) {
+	bal := big.NewFloat(1)
+	assert.Nil(t, Value(bal, map[string]float64{"x": math.NaN()}, "x"))
+	assert.Nil(t, Value(bal, map[string]float64{"x": math.Inf(1)}, "x"))
+}
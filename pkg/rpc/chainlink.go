@@ -0,0 +1,74 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// latestRoundDataSelector is latestRoundData() on a Chainlink aggregator.
+var latestRoundDataSelector = []byte{0xfe, 0xaf, 0x96, 0x8c}
+
+// MaxPriceFeedAge is how old a feed's latest answer may be. Chainlink USD feeds
+// update at least daily, so anything older means the feed is no longer served.
+const MaxPriceFeedAge = 26 * time.Hour
+
+// FetchChainlinkPrice reads the latest answer of the Chainlink price feed at
+// feedAddr, which must quote in USD, through rpcURL. It is the fallback for
+// when CoinGecko has no price.
+func FetchChainlinkPrice(rpcURL, feedAddr string, timeout time.Duration) (float64, error) {
+	if !common.IsHexAddress(feedAddr) {
+		return 0, fmt.Errorf("invalid price feed address %q", feedAddr)
+	}
+	client, err := defaultPool.Get(rpcURL)
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	feed := common.HexToAddress(feedAddr)
+	resDecimals, err := client.CallContract(ctx, ethereum.CallMsg{To: &feed, Data: decimalsSelector}, nil)
+	if err == nil {
+		var resRound []byte
+		resRound, err = client.CallContract(ctx, ethereum.CallMsg{To: &feed, Data: latestRoundDataSelector}, nil)
+		if err == nil {
+			return decodeLatestRoundData(resRound, resDecimals, time.Now())
+		}
+	}
+	defaultPool.MarkFailed(rpcURL, err)
+	logger.Warn("price feed read failed", "rpc", rpcURL, "feed", feedAddr, "err", err)
+	return 0, err
+}
+
+// decodeLatestRoundData returns the price in a latestRoundData() result, which
+// is (uint80 roundId, int256 answer, uint256 startedAt, uint256 updatedAt,
+// uint80 answeredInRound), scaled by the feed's decimals() result.
+func decodeLatestRoundData(res, resDecimals []byte, now time.Time) (float64, error) {
+	if len(res) < 5*32 {
+		return 0, fmt.Errorf("short latestRoundData result (%d bytes)", len(res))
+	}
+	if len(resDecimals) < 32 {
+		return 0, fmt.Errorf("short decimals result (%d bytes)", len(resDecimals))
+	}
+	answer := new(big.Int).SetBytes(res[32:64])
+	if res[32]&0x80 != 0 || answer.Sign() == 0 {
+		// A negative int256 has its top bit set; neither it nor zero is a price.
+		return 0, fmt.Errorf("price feed returned no positive answer")
+	}
+	updatedAt := time.Unix(new(big.Int).SetBytes(res[96:128]).Int64(), 0)
+	if now.Sub(updatedAt) > MaxPriceFeedAge {
+		return 0, fmt.Errorf("price feed is stale (last updated %s)", updatedAt.UTC().Format(time.RFC3339))
+	}
+	decimals := new(big.Int).SetBytes(resDecimals[:32])
+	if !decimals.IsInt64() || decimals.Int64() > 36 {
+		return 0, fmt.Errorf("price feed has invalid decimals %s", decimals)
+	}
+	scale := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), decimals, nil))
+	price, _ := new(big.Float).Quo(new(big.Float).SetInt(answer), scale).Float64()
+	return price, nil
+}
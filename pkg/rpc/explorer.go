@@ -0,0 +1,217 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"evmbal/pkg/config"
+	"evmbal/pkg/models"
+	"evmbal/pkg/utils"
+)
+
+// ExplorerPageSize is how many transactions are requested per explorer API page.
+var ExplorerPageSize = 50
+
+// DefaultExplorerTimeout bounds an explorer API request, unless the chain sets
+// timeout_seconds.
+const DefaultExplorerTimeout = 15 * time.Second
+
+// explorerClient is shared by all Etherscan-compatible API requests. Each request
+// carries its chain's timeout.
+var explorerClient = &http.Client{Transport: &rateLimitedTransport{}}
+
+// explorerResponse is the envelope used by Etherscan-compatible APIs. Result is a
+// list on success and an error string on failure.
+type explorerResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// explorerTx is one entry of an account&action=txlist response.
+type explorerTx struct {
+	BlockNumber string `json:"blockNumber"`
+	Hash        string `json:"hash"`
+	Nonce       string `json:"nonce"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Value       string `json:"value"`
+	Gas         string `json:"gas"`
+	GasPrice    string `json:"gasPrice"`
+}
+
+// explorerTokenTx is one entry of an account&action=tokentx response.
+type explorerTokenTx struct {
+	explorerTx
+	TokenSymbol  string `json:"tokenSymbol"`
+	TokenDecimal string `json:"tokenDecimal"`
+}
+
+// explorerGet calls an account module action on the chain's explorer API and
+// decodes the result list into out. Query parameters already present in
+// ExplorerAPIURL (e.g. chainid for Etherscan V2) are kept.
+func explorerGet(chain config.ChainConfig, params url.Values, out interface{}) error {
+	u, err := url.Parse(chain.ExplorerAPIURL)
+	if err != nil {
+		return fmt.Errorf("invalid explorer API URL: %w", err)
+	}
+	q := u.Query()
+	for k, v := range params {
+		q[k] = v
+	}
+	if chain.ExplorerAPIKey != "" {
+		q.Set("apikey", chain.ExplorerAPIKey)
+	}
+	u.RawQuery = q.Encode()
+
+	ctx, cancel := context.WithTimeout(context.Background(), chain.Timeout(DefaultExplorerTimeout))
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", "application/json")
+	resp, err := explorerClient.Do(req)
+	if err != nil {
+		// The request URL in a transport error carries the API key.
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			uerr.URL = redactAPIKey(u)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("explorer API returned %s", resp.Status)
+	}
+
+	var envelope explorerResponse
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("failed to decode explorer response: %w", err)
+	}
+	if envelope.Status != "1" {
+		// An empty history is reported as status 0 with an empty list.
+		if strings.HasPrefix(envelope.Message, "No transactions found") {
+			return nil
+		}
+		var detail string
+		_ = json.Unmarshal(envelope.Result, &detail)
+		return fmt.Errorf("explorer API error: %s %s", envelope.Message, detail)
+	}
+	return json.Unmarshal(envelope.Result, out)
+}
+
+// redactAPIKey returns u as a string with the value of its apikey parameter hidden.
+func redactAPIKey(u *url.URL) string {
+	q := u.Query()
+	if q.Has("apikey") {
+		q.Set("apikey", "REDACTED")
+	}
+	redacted := *u
+	redacted.RawQuery = q.Encode()
+	return redacted.String()
+}
+
+// FetchExplorerTransactions returns one page (1-based) of the address's normal
+// transactions, newest first, from the chain's Etherscan-compatible API.
+func FetchExplorerTransactions(chain config.ChainConfig, addressHex string, page, tokenDecimals int) ([]models.Transaction, error) {
+	var list []explorerTx
+	err := explorerGet(chain, url.Values{
+		"module":  {"account"},
+		"action":  {"txlist"},
+		"address": {addressHex},
+		"page":    {strconv.Itoa(page)},
+		"offset":  {strconv.Itoa(ExplorerPageSize)},
+		"sort":    {"desc"},
+	}, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]models.Transaction, 0, len(list))
+	for _, e := range list {
+		t := models.Transaction{
+			Hash:     e.Hash,
+			From:     e.From,
+			To:       e.To,
+			Value:    utils.FormatBigFloat(weiToUnits(e.Value, 18), tokenDecimals),
+			GasPrice: fmt.Sprintf("%.2f Gwei", gweiFromWei(e.GasPrice)),
+		}
+		t.BlockNumber, _ = strconv.ParseUint(e.BlockNumber, 10, 64)
+		t.GasLimit, _ = strconv.ParseUint(e.Gas, 10, 64)
+		t.Nonce, _ = strconv.ParseUint(e.Nonce, 10, 64)
+		if t.To == "" {
+			t.To = "Contract"
+		}
+		txs = append(txs, t)
+	}
+	return txs, nil
+}
+
+// weiToUnits converts a base-10 integer string to a float scaled down by decimals.
+// Unparseable input yields zero.
+func weiToUnits(s string, decimals int) *big.Float {
+	i, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return new(big.Float)
+	}
+	f := new(big.Float).SetInt(i)
+	divisor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	return f.Quo(f, divisor)
+}
+
+func gweiFromWei(s string) float64 {
+	f, _ := weiToUnits(s, 9).Float64()
+	return f
+}
+
+// FetchTokenTransfers returns one page (1-based) of ERC-20 transfers to or from the
+// address, newest first, using the chain's Etherscan-compatible API. Amounts are
+// formatted with tokenDecimals and TokenSymbol is set on each entry.
+func FetchTokenTransfers(addressHex string, chain config.ChainConfig, page, tokenDecimals int) ([]models.Transaction, error) {
+	if chain.ExplorerAPIURL == "" {
+		return nil, fmt.Errorf("chain %s has no explorer API configured", chain.Name)
+	}
+	var list []explorerTokenTx
+	err := explorerGet(chain, url.Values{
+		"module":  {"account"},
+		"action":  {"tokentx"},
+		"address": {addressHex},
+		"page":    {strconv.Itoa(page)},
+		"offset":  {strconv.Itoa(ExplorerPageSize)},
+		"sort":    {"desc"},
+	}, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]models.Transaction, 0, len(list))
+	for _, e := range list {
+		decimals, err := strconv.Atoi(e.TokenDecimal)
+		if err != nil {
+			decimals = 18
+		}
+		t := models.Transaction{
+			Hash:        e.Hash,
+			From:        e.From,
+			To:          e.To,
+			Value:       utils.FormatBigFloat(weiToUnits(e.Value, decimals), tokenDecimals),
+			GasPrice:    fmt.Sprintf("%.2f Gwei", gweiFromWei(e.GasPrice)),
+			TokenSymbol: e.TokenSymbol,
+		}
+		t.BlockNumber, _ = strconv.ParseUint(e.BlockNumber, 10, 64)
+		t.GasLimit, _ = strconv.ParseUint(e.Gas, 10, 64)
+		t.Nonce, _ = strconv.ParseUint(e.Nonce, 10, 64)
+		txs = append(txs, t)
+	}
+	return txs, nil
+}
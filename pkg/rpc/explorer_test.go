@@ -0,0 +1,108 @@
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"evmbal/pkg/config"
+)
+
+func TestFetchTransactions_ExplorerAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("chainid") != "1" || q.Get("apikey") != "KEY" || q.Get("action") != "txlist" || q.Get("sort") != "desc" {
+			t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+		}
+		switch q.Get("address") {
+		case "0xempty":
+			_, _ = w.Write([]byte(`{"status":"0","message":"No transactions found","result":[]}`))
+		case "0xbad":
+			_, _ = w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Invalid API Key"}`))
+		default:
+			_, _ = w.Write([]byte(`{"status":"1","message":"OK","result":[
+				{"blockNumber":"19000000","hash":"0xabc","nonce":"7","from":"0xfrom","to":"0xto","value":"1500000000000000000","gas":"21000","gasPrice":"25000000000"},
+				{"blockNumber":"18999999","hash":"0xdef","nonce":"6","from":"0xfrom","to":"","value":"0","gas":"500000","gasPrice":"30000000000"}
+			]}`))
+		}
+	}))
+	defer server.Close()
+
+	chain := config.ChainConfig{Name: "Ethereum", ExplorerAPIURL: server.URL + "/v2/api?chainid=1", ExplorerAPIKey: "KEY"}
+
+	txs, failed, err := FetchTransactions("0xabc", chain, 4)
+	if err != nil || len(failed) != 0 {
+		t.Fatalf("Unexpected error: %v (failed %v)", err, failed)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("Expected 2 transactions, got %d", len(txs))
+	}
+	tx := txs[0]
+	if tx.Hash != "0xabc" || tx.BlockNumber != 19000000 || tx.Nonce != 7 || tx.GasLimit != 21000 {
+		t.Errorf("Unexpected transaction: %+v", tx)
+	}
+	if tx.Value != "1.5000" {
+		t.Errorf("Expected value '1.5000', got '%s'", tx.Value)
+	}
+	if tx.GasPrice != "25.00 Gwei" {
+		t.Errorf("Expected gas price '25.00 Gwei', got '%s'", tx.GasPrice)
+	}
+	if txs[1].To != "Contract" {
+		t.Errorf("Expected contract creation to map to 'Contract', got '%s'", txs[1].To)
+	}
+
+	txs, err = FetchExplorerTransactions(chain, "0xempty", 1, 4)
+	if err != nil || len(txs) != 0 {
+		t.Errorf("Expected empty history without error, got %v, %v", txs, err)
+	}
+
+	if _, err := FetchExplorerTransactions(chain, "0xbad", 1, 4); err == nil {
+		t.Error("Expected error for API failure")
+	}
+}
+
+func TestFetchTokenTransfers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("action") != "tokentx" || r.URL.Query().Get("page") != "2" {
+			t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`{"status":"1","message":"OK","result":[
+			{"blockNumber":"19000000","hash":"0xabc","nonce":"7","from":"0xfrom","to":"0xto","value":"2500000","gas":"60000","gasPrice":"25000000000","tokenSymbol":"USDC","tokenDecimal":"6"}
+		]}`))
+	}))
+	defer server.Close()
+
+	if _, err := FetchTokenTransfers("0xto", config.ChainConfig{Name: "Ethereum"}, 2, 4); err == nil {
+		t.Error("Expected error without an explorer API")
+	}
+
+	txs, err := FetchTokenTransfers("0xto", config.ChainConfig{Name: "Ethereum", ExplorerAPIURL: server.URL}, 2, 4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(txs) != 1 {
+		t.Fatalf("Expected 1 transfer, got %d", len(txs))
+	}
+	if txs[0].TokenSymbol != "USDC" || txs[0].Value != "2.5000" || txs[0].From != "0xfrom" {
+		t.Errorf("Unexpected transfer: %+v", txs[0])
+	}
+}
+
+func TestExplorerGet_RedactsAPIKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close() // Connections are refused, so the request fails in the transport.
+
+	chain := config.ChainConfig{Name: "Ethereum", ExplorerAPIURL: server.URL + "/api", ExplorerAPIKey: "SECRETKEY"}
+
+	_, err := FetchExplorerTransactions(chain, "0xabc", 1, 4)
+	if err == nil {
+		t.Fatal("Expected error for unreachable explorer")
+	}
+	if strings.Contains(err.Error(), "SECRETKEY") {
+		t.Errorf("Error leaks the API key: %v", err)
+	}
+	if !strings.Contains(err.Error(), "apikey=REDACTED") {
+		t.Errorf("Expected the redacted URL in the error, got: %v", err)
+	}
+}
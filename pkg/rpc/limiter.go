@@ -0,0 +1,76 @@
+package rpc
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// DefaultRequestsPerSecond is generous enough that unconfigured setups behave as before.
+const DefaultRequestsPerSecond = 50.0
+
+var (
+	limiterMu         sync.Mutex
+	limiters          = make(map[string]*rate.Limiter) // Key: URL host
+	requestsPerSecond = DefaultRequestsPerSecond
+)
+
+// SetRequestsPerSecond sets the per-host request rate shared by all calls in this
+// package. Websocket RPCs are only limited when dialing: calls over an open
+// connection bypass the HTTP transport and are not counted. Values <= 0 restore
+// the default. Existing limiters are replaced.
+func SetRequestsPerSecond(rps float64) {
+	if rps <= 0 {
+		rps = DefaultRequestsPerSecond
+	}
+	limiterMu.Lock()
+	defer limiterMu.Unlock()
+	requestsPerSecond = rps
+	limiters = make(map[string]*rate.Limiter)
+}
+
+// limiterFor returns the limiter for a host, creating it on first use.
+func limiterFor(host string) *rate.Limiter {
+	limiterMu.Lock()
+	defer limiterMu.Unlock()
+	l, ok := limiters[host]
+	if !ok {
+		burst := int(requestsPerSecond)
+		if burst < 1 {
+			burst = 1
+		}
+		l = rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+		limiters[host] = l
+	}
+	return l
+}
+
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Host
+}
+
+// rateLimitedTransport waits for the destination host's limiter before each request.
+// A nil base uses the package transport, which follows SetProxy.
+type rateLimitedTransport struct {
+	base http.RoundTripper
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := limiterFor(req.URL.Host).Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	base := t.base
+	if base == nil {
+		base = currentTransport()
+	}
+	return base.RoundTrip(req)
+}
+
+// limitedHTTPClient is used for every HTTP JSON-RPC connection in the client pool.
+var limitedHTTPClient = &http.Client{Transport: &rateLimitedTransport{}}
@@ -0,0 +1,24 @@
+package rpc
+
+import (
+	"testing"
+
+	"golang.org/x/time/rate"
+)
+
+func TestSetRequestsPerSecond(t *testing.T) {
+	defer SetRequestsPerSecond(0)
+
+	SetRequestsPerSecond(2)
+	if l := limiterFor("rpc.example"); l.Limit() != rate.Limit(2) {
+		t.Errorf("Expected limit 2, got %v", l.Limit())
+	}
+	if limiterFor("rpc.example") != limiterFor("rpc.example") {
+		t.Error("Expected one limiter per host")
+	}
+
+	SetRequestsPerSecond(0)
+	if l := limiterFor("rpc.example"); l.Limit() != rate.Limit(DefaultRequestsPerSecond) {
+		t.Errorf("Expected default limit, got %v", l.Limit())
+	}
+}
@@ -0,0 +1,192 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"evmbal/pkg/config"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// ClientPool caches dialed clients by RPC URL so fetches reuse connections
+// instead of dialing and closing on every call.
+type ClientPool struct {
+	mu       sync.Mutex
+	clients  map[string]*ethclient.Client
+	chainIDs map[string]*big.Int // Key: RPC URL; reported by the node on the cached connection
+}
+
+// NewClientPool creates an empty pool.
+func NewClientPool() *ClientPool {
+	return &ClientPool{clients: make(map[string]*ethclient.Client), chainIDs: make(map[string]*big.Int)}
+}
+
+// defaultPool is shared by all fetch functions in this package.
+var defaultPool = NewClientPool()
+
+// Get returns a cached client for rpcURL, dialing a new one if needed.
+// Callers must not Close the returned client.
+func (p *ClientPool) Get(rpcURL string) (*ethclient.Client, error) {
+	p.mu.Lock()
+	c, ok := p.clients[rpcURL]
+	p.mu.Unlock()
+	if ok {
+		return c, nil
+	}
+
+	// Dial without the lock, so a slow or rate-limited host does not hold up
+	// lookups of every other RPC.
+	c, err := dialClient(rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if existing, ok := p.clients[rpcURL]; ok {
+		// Another call dialed the same URL meanwhile; keep one connection.
+		c.Close()
+		return existing, nil
+	}
+	p.clients[rpcURL] = c
+	return c, nil
+}
+
+func dialClient(rpcURL string) (*ethclient.Client, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var rc *gethrpc.Client
+	var err error
+	if IsWebsocketURL(rpcURL) {
+		// Websocket calls share one connection that geth offers no hook into, so
+		// only the dial itself is rate limited (see SetRequestsPerSecond).
+		if err = limiterFor(hostOf(rpcURL)).Wait(ctx); err != nil {
+			return nil, err
+		}
+		rc, err = dialWebsocket(ctx, rpcURL)
+	} else {
+		rc, err = gethrpc.DialOptions(ctx, rpcURL, gethrpc.WithHTTPClient(limitedHTTPClient), gethrpc.WithHeaders(headersFor(rpcURL)))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(rc), nil
+}
+
+// retiredClientGrace is how long an evicted client stays open for calls that
+// got it from Get earlier. It outlasts the default request timeouts.
+var retiredClientGrace = 2 * time.Minute
+
+// retire closes an evicted client once the calls still using it have had time
+// to finish.
+func retire(c *ethclient.Client) {
+	time.AfterFunc(retiredClientGrace, c.Close)
+}
+
+// ChainID returns the chain ID reported by the node at rpcURL. It is asked once
+// per connection; a re-dial after MarkFailed asks again.
+func (p *ClientPool) ChainID(ctx context.Context, rpcURL string) (*big.Int, error) {
+	client, err := p.Get(rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	id, ok := p.chainIDs[rpcURL]
+	p.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+	if id, err = client.ChainID(ctx); err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	p.chainIDs[rpcURL] = id
+	p.mu.Unlock()
+	return id, nil
+}
+
+// MarkFailed evicts the cached client for rpcURL after a failed call so the next
+// Get re-dials. Errors returned by the node itself (reverts, invalid params) leave
+// the connection in place since it is evidently healthy.
+func (p *ClientPool) MarkFailed(rpcURL string, err error) {
+	if err == nil {
+		return
+	}
+	var rpcErr gethrpc.Error
+	if errors.As(err, &rpcErr) {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if c, ok := p.clients[rpcURL]; ok {
+		// Other goroutines may still be using it; only new calls re-dial.
+		retire(c)
+		delete(p.clients, rpcURL)
+	}
+	delete(p.chainIDs, rpcURL)
+}
+
+// Reset evicts all cached clients so the next calls dial with the current
+// proxy and headers. Calls in flight keep their client until they finish.
+func (p *ClientPool) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for url, c := range p.clients {
+		retire(c)
+		delete(p.clients, url)
+	}
+	clear(p.chainIDs)
+}
+
+// Close closes all cached clients.
+func (p *ClientPool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for url, c := range p.clients {
+		c.Close()
+		delete(p.clients, url)
+	}
+	clear(p.chainIDs)
+}
+
+var (
+	headersMu  sync.RWMutex
+	rpcHeaders = make(map[string]http.Header) // Key: RPC URL
+)
+
+// SetRPCHeaders registers the extra headers each chain sends to its RPC URLs, such
+// as an Authorization bearer token for private endpoints. Cached clients are evicted
+// so the next call dials with the new headers.
+func SetRPCHeaders(chains []config.ChainConfig) {
+	headers := make(map[string]http.Header)
+	for _, c := range chains {
+		if len(c.RPCHeaders) == 0 {
+			continue
+		}
+		h := make(http.Header)
+		for k, v := range c.RPCHeaders {
+			h.Set(k, v)
+		}
+		for _, u := range c.RPCURLs {
+			headers[u] = h
+		}
+	}
+
+	headersMu.Lock()
+	rpcHeaders = headers
+	headersMu.Unlock()
+	defaultPool.Reset()
+}
+
+// headersFor returns the headers registered for rpcURL, or nil.
+func headersFor(rpcURL string) http.Header {
+	headersMu.RLock()
+	defer headersMu.RUnlock()
+	return rpcHeaders[rpcURL]
+}
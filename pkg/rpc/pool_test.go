@@ -0,0 +1,128 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"evmbal/pkg/config"
+
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+type testRPCError struct{}
+
+func (testRPCError) Error() string  { return "execution reverted" }
+func (testRPCError) ErrorCode() int { return 3 }
+
+func TestClientPool_ReuseAndRedial(t *testing.T) {
+	pool := NewClientPool()
+	defer pool.Close()
+
+	url := "http://127.0.0.1:8545"
+	c1, err := pool.Get(url)
+	if err != nil {
+		t.Fatalf("Unexpected dial error: %v", err)
+	}
+	c2, _ := pool.Get(url)
+	if c1 != c2 {
+		t.Error("Expected cached client to be reused")
+	}
+
+	// Errors reported by the node keep the connection
+	pool.MarkFailed(url, testRPCError{})
+	if c3, _ := pool.Get(url); c3 != c1 {
+		t.Error("Expected client to survive a JSON-RPC error")
+	}
+
+	// Transport errors force a re-dial
+	pool.MarkFailed(url, errors.New("connection refused"))
+	if c4, _ := pool.Get(url); c4 == c1 {
+		t.Error("Expected a new client after a transport error")
+	}
+}
+
+func TestSetRPCHeaders(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`))
+	}))
+	defer server.Close()
+
+	SetRPCHeaders([]config.ChainConfig{{
+		Name:       "Private",
+		RPCURLs:    []string{server.URL},
+		RPCHeaders: map[string]string{"Authorization": "Bearer secret"},
+	}})
+	defer SetRPCHeaders(nil)
+
+	client, err := defaultPool.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected dial error: %v", err)
+	}
+	if _, err := client.ChainID(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("Expected Authorization header to be sent, got %q", auth)
+	}
+}
+
+type testEthService struct{}
+
+func (testEthService) ChainId() string { return "0x1" }
+
+func TestClientPool_MarkFailedKeepsClientForInFlightCalls(t *testing.T) {
+	// A websocket client, unlike an HTTP one, stops working once closed.
+	srv := gethrpc.NewServer()
+	if err := srv.RegisterName("eth", testEthService{}); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(srv.WebsocketHandler([]string{"*"}))
+	defer server.Close()
+	pool := NewClientPool()
+	defer pool.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	c1, err := pool.Get(url)
+	if err != nil {
+		t.Fatalf("Unexpected dial error: %v", err)
+	}
+	pool.MarkFailed(url, errors.New("connection reset"))
+	if _, err := c1.ChainID(context.Background()); err != nil {
+		t.Errorf("Expected the evicted client to keep working for its current users, got %v", err)
+	}
+	if c2, _ := pool.Get(url); c2 == c1 {
+		t.Error("Expected a new client after MarkFailed")
+	}
+}
+
+func TestClientPool_SlowDialDoesNotBlockOtherURLs(t *testing.T) {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer slow.Close()
+	defer close(release)
+	pool := NewClientPool()
+	defer pool.Close()
+
+	go func() { _, _ = pool.Get("ws" + strings.TrimPrefix(slow.URL, "http")) }()
+	time.Sleep(50 * time.Millisecond) // Let the websocket dial start
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = pool.Get("http://127.0.0.1:8545")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Get of another URL waited for a slow dial")
+	}
+}
@@ -0,0 +1,60 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+)
+
+var (
+	proxyMu sync.RWMutex
+	// proxyFunc picks the proxy for HTTP requests and websocket dials. The default
+	// honours HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+	proxyFunc = http.ProxyFromEnvironment
+	// baseTransport sits beneath the rate limiter for every HTTP client in this package.
+	baseTransport http.RoundTripper = http.DefaultTransport
+)
+
+// SetProxy routes all CoinGecko, explorer and RPC traffic, including websockets,
+// through proxyURL. An empty URL restores the environment defaults. Cached RPC
+// clients are evicted so they re-dial through the new proxy.
+func SetProxy(proxyURL string) error {
+	proxy := http.ProxyFromEnvironment
+	if proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("invalid proxy URL %q", proxyURL)
+		}
+		proxy = http.ProxyURL(u)
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = proxy
+
+	proxyMu.Lock()
+	proxyFunc = proxy
+	baseTransport = t
+	proxyMu.Unlock()
+
+	defaultPool.Reset()
+	return nil
+}
+
+func currentTransport() http.RoundTripper {
+	proxyMu.RLock()
+	defer proxyMu.RUnlock()
+	return baseTransport
+}
+
+// dialWebsocket dials a websocket RPC endpoint through the configured proxy,
+// sending any headers registered with SetRPCHeaders in the handshake.
+func dialWebsocket(ctx context.Context, wsURL string) (*gethrpc.Client, error) {
+	proxyMu.RLock()
+	dialer := websocket.Dialer{Proxy: proxyFunc}
+	proxyMu.RUnlock()
+	return gethrpc.DialOptions(ctx, wsURL, gethrpc.WithWebsocketDialer(dialer), gethrpc.WithHeaders(headersFor(wsURL)))
+}
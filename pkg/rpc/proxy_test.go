@@ -0,0 +1,42 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetProxy(t *testing.T) {
+	defer func() { _ = SetProxy("") }()
+
+	if err := SetProxy("not a url"); err == nil {
+		t.Error("Expected error for invalid proxy URL")
+	}
+
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A forward proxy receives the absolute target URL.
+		proxiedHost = r.URL.Host
+		_ = json.NewEncoder(w).Encode(map[string]map[string]float64{"ethereum": {"usd": 2500}})
+	}))
+	defer proxy.Close()
+
+	originalURL := CoinGeckoBaseURL
+	CoinGeckoBaseURL = "http://coingecko.test/api/v3"
+	defer func() { CoinGeckoBaseURL = originalURL }()
+
+	if err := SetProxy(proxy.URL); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pMsg, err := FetchEthPrice("ethereum")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if proxiedHost != "coingecko.test" {
+		t.Errorf("Expected request for coingecko.test through the proxy, got %q", proxiedHost)
+	}
+	if pMsg.Price != 2500 {
+		t.Errorf("Expected price 2500, got %f", pMsg.Price)
+	}
+}
@@ -0,0 +1,95 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GitHubAPIBaseURL is where release information is looked up.
+var GitHubAPIBaseURL = "https://api.github.com"
+
+// ReleaseRepo is the GitHub repository that publishes evmbal releases.
+const ReleaseRepo = "rnts08/evm-balance-watcher"
+
+// Release is the subset of a GitHub release that update checks need.
+type Release struct {
+	Tag string `json:"tag_name"`
+	URL string `json:"html_url"` // Release page with the downloads
+}
+
+// LatestRelease returns the newest published release of repo ("owner/name").
+// It is only called on request, so normal runs never contact GitHub.
+func LatestRelease(repo string) (Release, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/repos/%s/releases/latest", GitHubAPIBaseURL, repo), nil)
+	if err != nil {
+		return Release{}, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	client := &http.Client{Timeout: 10 * time.Second, Transport: currentTransport()}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Release{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return Release{}, fmt.Errorf("github returned %s", resp.Status)
+	}
+
+	var rel Release
+	if err := json.NewDecoder(resp.Body).Decode(&rel); err != nil {
+		return Release{}, fmt.Errorf("failed to decode release: %w", err)
+	}
+	if rel.Tag == "" {
+		return Release{}, fmt.Errorf("release has no tag")
+	}
+	return rel, nil
+}
+
+// IsNewerVersion reports whether latest is a higher dotted version than
+// current. A leading "v" and any pre-release suffix are ignored. ok is false if
+// either version cannot be parsed, e.g. for "dev" builds.
+func IsNewerVersion(current, latest string) (newer, ok bool) {
+	cur, okCur := parseVersion(current)
+	lat, okLat := parseVersion(latest)
+	if !okCur || !okLat {
+		return false, false
+	}
+	for i := 0; i < len(cur) || i < len(lat); i++ {
+		var c, l int
+		if i < len(cur) {
+			c = cur[i]
+		}
+		if i < len(lat) {
+			l = lat[i]
+		}
+		if c != l {
+			return l > c, true
+		}
+	}
+	return false, true
+}
+
+func parseVersion(v string) ([]int, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	if v == "" {
+		return nil, false
+	}
+	var parts []int
+	for _, p := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, false
+		}
+		parts = append(parts, n)
+	}
+	return parts, true
+}
@@ -0,0 +1,52 @@
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLatestRelease(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/releases/latest" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"tag_name":"v1.7.0","html_url":"https://github.com/owner/repo/releases/tag/v1.7.0"}`))
+	}))
+	defer server.Close()
+	orig := GitHubAPIBaseURL
+	GitHubAPIBaseURL = server.URL
+	defer func() { GitHubAPIBaseURL = orig }()
+
+	rel, err := LatestRelease("owner/repo")
+	if err != nil {
+		t.Fatalf("LatestRelease failed: %v", err)
+	}
+	if rel.Tag != "v1.7.0" || rel.URL != "https://github.com/owner/repo/releases/tag/v1.7.0" {
+		t.Errorf("Unexpected release: %+v", rel)
+	}
+	if _, err := LatestRelease("owner/missing"); err == nil {
+		t.Error("Expected an error for a missing repository")
+	}
+}
+
+func TestIsNewerVersion(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		newer, ok       bool
+	}{
+		{"1.6.2", "v1.7.0", true, true},
+		{"v1.6.2", "1.6.2", false, true},
+		{"1.6.10", "1.6.9", false, true},
+		{"1.6", "1.6.1", true, true},
+		{"1.6.2-rc1", "1.6.2", false, true},
+		{"dev", "1.7.0", false, false},
+	}
+	for _, tt := range tests {
+		newer, ok := IsNewerVersion(tt.current, tt.latest)
+		if newer != tt.newer || ok != tt.ok {
+			t.Errorf("IsNewerVersion(%q, %q) = %v, %v; want %v, %v", tt.current, tt.latest, newer, ok, tt.newer, tt.ok)
+		}
+	}
+}
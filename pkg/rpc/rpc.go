@@ -4,10 +4,19 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"math/big"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"evmbal/pkg/config"
 	"evmbal/pkg/models"
@@ -15,15 +24,46 @@ import (
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
 )
 
 var CoinGeckoBaseURL = "https://api.coingecko.com/api/v3"
-var ChainDataTimeout = 30 * time.Second
+
+// logger records RPC and API failures. It discards output until SetLogger is called.
+var logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// SetLogger sets the logger used by this package.
+func SetLogger(l *slog.Logger) {
+	logger = l
+}
+
+// CoinGeckoRetryDelay is how long to wait before retrying a 429 or 5xx response.
+var CoinGeckoRetryDelay = 2 * time.Second
+
+// UserAgent identifies this application to HTTP APIs; some block Go's default agent.
+var UserAgent = "evm-balance-watcher (+https://github.com/rnts08/evm-balance-watcher)"
+
+// coinGeckoClient is shared across price requests so connections are reused.
+var coinGeckoClient = &http.Client{Timeout: 10 * time.Second, Transport: &rateLimitedTransport{}}
+
+// Default RPC timeouts, used unless a chain sets timeout_seconds.
+const (
+	DefaultChainDataTimeout = 30 * time.Second
+	DefaultRequestTimeout   = 10 * time.Second
+	DefaultLatencyTimeout   = 5 * time.Second
+	DefaultRangeScanTimeout = 15 * time.Second // Per batch of a range scan
+)
 
 // FetchChainData performs a bulk fetch for a chain.
 func FetchChainData(chain config.ChainConfig, accounts []*models.Account) (models.ChainData, error) {
+	if len(chain.RPCURLs) == 0 {
+		err := fmt.Errorf("chain %s has no RPC URLs configured", chain.Name)
+		return models.ChainData{ChainName: chain.Name, Err: err}, err
+	}
+
 	var finalResults []models.AccountChainData
 	var failedRPCs []string
 	var lastErr error
@@ -43,10 +83,20 @@ func FetchChainData(chain config.ChainConfig, accounts []*models.Account) (model
 			break
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), ChainDataTimeout)
-		client, err := ethclient.Dial(rpcURL)
+		ctx, cancel := context.WithTimeout(context.Background(), chain.Timeout(DefaultChainDataTimeout))
+		client, err := defaultPool.Get(rpcURL)
 		if err != nil {
 			cancel()
+			logger.Warn("rpc dial failed", "chain", chain.Name, "rpc", rpcURL, "err", err)
+			failedRPCs = append(failedRPCs, rpcURL)
+			lastErr = err
+			continue
+		}
+
+		if err := verifyChainID(ctx, rpcURL, chain.ChainID); err != nil {
+			cancel()
+			defaultPool.MarkFailed(rpcURL, err)
+			logger.Warn("rpc rejected", "chain", chain.Name, "rpc", rpcURL, "err", err)
 			failedRPCs = append(failedRPCs, rpcURL)
 			lastErr = err
 			continue
@@ -63,12 +113,13 @@ func FetchChainData(chain config.ChainConfig, accounts []*models.Account) (model
 				rpcHasFailure = true
 				nextPending = append(nextPending, addr)
 				lastErr = err
+				defaultPool.MarkFailed(rpcURL, err)
+				logger.Warn("account fetch failed", "chain", chain.Name, "rpc", rpcURL, "address", addr, "err", err)
 			} else {
 				// Success
 				finalResults = append(finalResults, *res)
 			}
 		}
-		client.Close()
 		cancel()
 
 		if rpcHasFailure {
@@ -89,6 +140,23 @@ func FetchChainData(chain config.ChainConfig, accounts []*models.Account) (model
 	}, nil
 }
 
+// verifyChainID checks that the node at rpcURL serves the chain with ID want, so
+// an RPC misconfigured to point at another network, e.g. a testnet, cannot
+// pass off its balances as the chain's. A want of 0 (chain_id unset) skips it.
+func verifyChainID(ctx context.Context, rpcURL string, want int64) error {
+	if want == 0 {
+		return nil
+	}
+	id, err := defaultPool.ChainID(ctx, rpcURL)
+	if err != nil {
+		return fmt.Errorf("chain ID check: %w", err)
+	}
+	if !id.IsInt64() || id.Int64() != want {
+		return fmt.Errorf("RPC serves chain ID %s, expected %d", id, want)
+	}
+	return nil
+}
+
 // fetchAccountData fetches ETH and token balances for a single account using an open client.
 func fetchAccountData(ctx context.Context, client *ethclient.Client, chain config.ChainConfig, address string) (*models.AccountChainData, error) {
 	account := common.HexToAddress(address)
@@ -112,6 +180,10 @@ func fetchAccountData(ctx context.Context, client *ethclient.Client, chain confi
 			// Yes, fetchChainData loop checks for err and treats it as RPC failure for that account.
 			return nil, err
 		}
+		if bal == nil {
+			// No contract at the token address on this chain; leave it out rather than show 0.
+			continue
+		}
 		tokenBalances[token.Symbol] = bal
 	}
 
@@ -160,15 +232,95 @@ func fetchAccountData(ctx context.Context, client *ethclient.Client, chain confi
 }
 
 func fetchTokenBalanceInternal(ctx context.Context, client *ethclient.Client, token config.TokenConfig, account common.Address) (*big.Float, error) {
-	data := make([]byte, 4+32)
-	copy(data[0:4], []byte{0x70, 0xa0, 0x82, 0x31})
-	copy(data[4+12:], account.Bytes())
+	data, err := BalanceCallData(token, account)
+	if err != nil {
+		return nil, err
+	}
 	tokenAddr := common.HexToAddress(token.Address)
 	msg := ethereum.CallMsg{To: &tokenAddr, Data: data}
 	result, err := client.CallContract(ctx, msg, nil)
 	if err != nil {
 		return nil, err
 	}
+	return decodeTokenBalance(token, result)
+}
+
+var (
+	erc20BalanceOfSelector   = []byte{0x70, 0xa0, 0x82, 0x31} // balanceOf(address)
+	erc1155BalanceOfSelector = []byte{0x00, 0xfd, 0xd5, 0x8e} // balanceOf(address,uint256)
+)
+
+// BalanceCallData encodes the call that reads holder's balance of token
+// according to its balance_method, or for a custom position its selector and
+// call_args. It fails for an unknown method, a missing or invalid ERC-1155
+// token_id, or call_args that cannot be encoded.
+func BalanceCallData(token config.TokenConfig, holder common.Address) ([]byte, error) {
+	word := func(b []byte) []byte { return common.LeftPadBytes(b, 32) }
+	method := strings.ToLower(token.BalanceMethod)
+	if len(token.CallArgs) > 0 && !token.Custom {
+		return nil, fmt.Errorf("token %s: call_args need \"custom\": true", token.Symbol)
+	}
+	switch {
+	case token.Custom:
+		selector, err := hexutil.Decode(token.BalanceMethod)
+		if err != nil || len(selector) != 4 {
+			return nil, fmt.Errorf("token %s: custom positions need a 4-byte balance_method selector, got %q", token.Symbol, token.BalanceMethod)
+		}
+		args := token.CallArgs
+		if len(args) == 0 {
+			args = []string{config.CallArgHolder}
+		}
+		for _, arg := range args {
+			w, err := encodeCallArg(arg, holder)
+			if err != nil {
+				return nil, fmt.Errorf("token %s: %w", token.Symbol, err)
+			}
+			selector = append(selector, w...)
+		}
+		return selector, nil
+	case token.IsERC20():
+		return append(bytes.Clone(erc20BalanceOfSelector), word(holder.Bytes())...), nil
+	case method == config.BalanceMethodERC1155:
+		id, ok := new(big.Int).SetString(token.TokenID, 0)
+		if !ok || id.Sign() < 0 || id.BitLen() > 256 {
+			return nil, fmt.Errorf("token %s: invalid or missing token_id %q for erc1155", token.Symbol, token.TokenID)
+		}
+		data := append(bytes.Clone(erc1155BalanceOfSelector), word(holder.Bytes())...)
+		return append(data, word(id.Bytes())...), nil
+	}
+	selector, err := hexutil.Decode(token.BalanceMethod)
+	if err != nil || len(selector) != 4 {
+		return nil, fmt.Errorf("token %s: balance_method %q is not erc20, erc1155 or a 4-byte selector", token.Symbol, token.BalanceMethod)
+	}
+	return append(selector, word(holder.Bytes())...), nil
+}
+
+// encodeCallArg encodes one static call argument as a 32-byte word: the holder
+// placeholder, or a non-negative integer in decimal or 0x hex. An address is
+// encoded the same as the 0x number it spells.
+func encodeCallArg(arg string, holder common.Address) ([]byte, error) {
+	if arg == config.CallArgHolder {
+		return common.LeftPadBytes(holder.Bytes(), 32), nil
+	}
+	n, ok := new(big.Int).SetString(arg, 0)
+	if !ok || n.Sign() < 0 || n.BitLen() > 256 {
+		return nil, fmt.Errorf("call argument %q is not %q, an address or a uint256", arg, config.CallArgHolder)
+	}
+	return common.LeftPadBytes(n.Bytes(), 32), nil
+}
+
+// decodeTokenBalance scales a balanceOf() result by the token's decimals. An
+// empty result, as returned when there is no contract at the token address,
+// means the token is not present and yields a nil balance. Any length other
+// than one 32-byte word is an error, so a garbled response is not read as a
+// balance and the next RPC gets a chance.
+func decodeTokenBalance(token config.TokenConfig, result []byte) (*big.Float, error) {
+	if len(result) == 0 {
+		return nil, nil
+	}
+	if len(result) != 32 {
+		return nil, fmt.Errorf("balanceOf on %s (%s) returned %d bytes, expected 32", token.Symbol, token.Address, len(result))
+	}
 	balInt := new(big.Int).SetBytes(result)
 	fBal := new(big.Float).SetInt(balInt)
 	divisor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(token.Decimals)), nil))
@@ -177,15 +329,26 @@ func fetchTokenBalanceInternal(ctx context.Context, client *ethclient.Client, to
 }
 
 // FetchTransactions returns a list of transactions, failed RPCs, and potential error.
-func FetchTransactions(addressHex string, rpcURLs []string, tokenDecimals int) ([]models.Transaction, []string, error) {
+// Chains with an ExplorerAPIURL use the explorer's account history; otherwise, or when
+// the explorer fails, the latest blocks are scanned over RPC.
+func FetchTransactions(addressHex string, chain config.ChainConfig, tokenDecimals int) ([]models.Transaction, []string, error) {
+	if chain.ExplorerAPIURL != "" {
+		txs, err := FetchExplorerTransactions(chain, addressHex, 1, tokenDecimals)
+		if err == nil {
+			return txs, nil, nil
+		}
+		logger.Warn("explorer txlist failed, falling back to block scan", "chain", chain.Name, "err", err)
+	}
+
+	rpcURLs := chain.RPCURLs
 	var failed []string
 	var lastErr error
 	var txs []models.Transaction
 
 	for _, rpcURL := range rpcURLs {
 		txs = []models.Transaction{} // reset
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		client, err := ethclient.Dial(rpcURL)
+		ctx, cancel := context.WithTimeout(context.Background(), chain.Timeout(DefaultRequestTimeout))
+		client, err := defaultPool.Get(rpcURL)
 		if err != nil {
 			cancel()
 			failed = append(failed, rpcURL)
@@ -196,7 +359,7 @@ func FetchTransactions(addressHex string, rpcURLs []string, tokenDecimals int) (
 		targetAddr := common.HexToAddress(addressHex)
 		header, err := client.HeaderByNumber(ctx, nil)
 		if err != nil {
-			client.Close()
+			defaultPool.MarkFailed(rpcURL, err)
 			cancel()
 			failed = append(failed, rpcURL)
 			lastErr = err
@@ -205,7 +368,7 @@ func FetchTransactions(addressHex string, rpcURLs []string, tokenDecimals int) (
 
 		chainID, err := client.ChainID(ctx)
 		if err != nil {
-			client.Close()
+			defaultPool.MarkFailed(rpcURL, err)
 			cancel()
 			failed = append(failed, rpcURL)
 			lastErr = err
@@ -213,63 +376,39 @@ func FetchTransactions(addressHex string, rpcURLs []string, tokenDecimals int) (
 		}
 		signer := types.NewLondonSigner(chainID)
 
-		currentBlock := header.Number
-		// Scan last 10 blocks
+		// Scan last 10 blocks, requested in one batch
+		var nums []uint64
+		for n := header.Number.Uint64(); len(nums) < 10; n-- {
+			nums = append(nums, n)
+			if n == 0 {
+				break
+			}
+		}
+		blocks, err := fetchBlocksBatch(ctx, client, nums)
+		if err != nil {
+			logger.Warn("block batch failed", "rpc", rpcURL, "err", err)
+			defaultPool.MarkFailed(rpcURL, err)
+			cancel()
+			failed = append(failed, rpcURL)
+			lastErr = err
+			continue
+		}
 		var blockErr error
-		for i := 0; i < 10; i++ {
+		for _, block := range blocks {
 			if len(txs) >= 5 {
 				break
 			}
-			blockNum := new(big.Int).Sub(currentBlock, big.NewInt(int64(i)))
-			block, err := client.BlockByNumber(ctx, blockNum)
-			if err != nil {
-				blockErr = err
+			if block.err != nil {
+				blockErr = block.err
 				continue
 			}
-
-			for _, tx := range block.Transactions() {
-				if len(txs) >= 5 {
-					break
-				}
-
-				from, err := types.Sender(signer, tx)
-				if err != nil {
-					continue
-				}
-				isTo := tx.To() != nil && *tx.To() == targetAddr
-				isFrom := from == targetAddr
-
-				if isTo || isFrom {
-					val := new(big.Float).SetInt(tx.Value())
-					val = val.Quo(val, big.NewFloat(1e18))
-
-					t := models.Transaction{
-						Hash:        tx.Hash().Hex(),
-						From:        from.Hex(),
-						Value:       utils.FormatBigFloat(val, tokenDecimals),
-						BlockNumber: block.NumberU64(),
-						GasLimit:    tx.Gas(),
-						GasPrice: func() string {
-							gp := new(big.Float).SetInt(tx.GasPrice())
-							gp.Quo(gp, big.NewFloat(1e9))
-							f, _ := gp.Float64()
-							return fmt.Sprintf("%.2f Gwei", f)
-						}(),
-						Nonce: tx.Nonce(),
-					}
-					if tx.To() != nil {
-						t.To = tx.To().Hex()
-					} else {
-						t.To = "Contract"
-					}
-					txs = append(txs, t)
-				}
-			}
+			txs = matchTransactions(txs, block, signer, targetAddr, tokenDecimals, 5)
 		}
-		client.Close()
 		cancel()
 
 		if blockErr != nil && len(txs) == 0 {
+			logger.Warn("transaction scan failed", "rpc", rpcURL, "err", blockErr)
+			defaultPool.MarkFailed(rpcURL, blockErr)
 			lastErr = blockErr
 			failed = append(failed, rpcURL)
 			continue
@@ -280,33 +419,284 @@ func FetchTransactions(addressHex string, rpcURLs []string, tokenDecimals int) (
 	return nil, failed, lastErr
 }
 
+// MaxRangeScanBlocks caps how many blocks FetchTransactionsRange will scan, so a
+// typo'd start block doesn't walk millions of blocks on a public RPC.
+var MaxRangeScanBlocks uint64 = 10000
+
+// rangeChunkSize is the number of blocks requested per batch in FetchTransactionsRange.
+const rangeChunkSize = 20
+
+// FetchTransactionsRange scans blocks fromBlock..toBlock (toBlock 0 means the current
+// head) in batched chunks and returns up to limit transactions involving the address,
+// oldest first. Each batched request may take up to timeout. On error the
+// transactions found so far are returned with it.
+func FetchTransactionsRange(addressHex string, rpcURLs []string, fromBlock, toBlock uint64, limit, tokenDecimals int, timeout time.Duration) ([]models.Transaction, error) {
+	if len(rpcURLs) == 0 {
+		return nil, fmt.Errorf("no RPC URLs configured")
+	}
+
+	var lastErr error
+	idx := 0
+	// call runs fn against the current RPC, failing over to the next one on error.
+	call := func(fn func(ctx context.Context, client *ethclient.Client) error) error {
+		for ; idx < len(rpcURLs); idx++ {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			client, err := defaultPool.Get(rpcURLs[idx])
+			if err == nil {
+				err = fn(ctx, client)
+				defaultPool.MarkFailed(rpcURLs[idx], err)
+			}
+			cancel()
+			if err == nil {
+				return nil
+			}
+			logger.Warn("transaction range scan failed", "rpc", rpcURLs[idx], "err", err)
+			lastErr = err
+		}
+		return lastErr
+	}
+
+	var signer types.Signer
+	err := call(func(ctx context.Context, client *ethclient.Client) error {
+		chainID, err := client.ChainID(ctx)
+		if err != nil {
+			return err
+		}
+		signer = types.NewLondonSigner(chainID)
+		if toBlock == 0 {
+			head, err := client.BlockNumber(ctx)
+			if err != nil {
+				return err
+			}
+			toBlock = head
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if fromBlock > toBlock {
+		return nil, fmt.Errorf("start block %d is past block %d", fromBlock, toBlock)
+	}
+	if toBlock-fromBlock >= MaxRangeScanBlocks {
+		return nil, fmt.Errorf("range of %d blocks exceeds the %d block scan limit", toBlock-fromBlock+1, MaxRangeScanBlocks)
+	}
+
+	target := common.HexToAddress(addressHex)
+	var txs []models.Transaction
+	for start := fromBlock; start <= toBlock && len(txs) < limit; start += rangeChunkSize {
+		var nums []uint64
+		for n := start; n <= toBlock && len(nums) < rangeChunkSize; n++ {
+			nums = append(nums, n)
+		}
+		var blocks []batchBlock
+		err := call(func(ctx context.Context, client *ethclient.Client) error {
+			var err error
+			blocks, err = fetchBlocksBatch(ctx, client, nums)
+			if err != nil {
+				return err
+			}
+			for _, b := range blocks {
+				if b.err != nil {
+					return b.err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return txs, err
+		}
+		for _, b := range blocks {
+			txs = matchTransactions(txs, b, signer, target, tokenDecimals, limit)
+		}
+	}
+	return txs, nil
+}
+
+// matchTransactions appends the block's transactions to or from target to txs,
+// stopping once txs holds limit entries.
+func matchTransactions(txs []models.Transaction, block batchBlock, signer types.Signer, target common.Address, tokenDecimals, limit int) []models.Transaction {
+	for _, tx := range block.transactions {
+		if len(txs) >= limit {
+			break
+		}
+
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			continue
+		}
+		isTo := tx.To() != nil && *tx.To() == target
+		isFrom := from == target
+
+		if isTo || isFrom {
+			val := new(big.Float).SetInt(tx.Value())
+			val = val.Quo(val, big.NewFloat(1e18))
+
+			t := models.Transaction{
+				Hash:        tx.Hash().Hex(),
+				From:        from.Hex(),
+				Value:       utils.FormatBigFloat(val, tokenDecimals),
+				BlockNumber: block.number,
+				GasLimit:    tx.Gas(),
+				GasPrice: func() string {
+					gp := new(big.Float).SetInt(tx.GasPrice())
+					gp.Quo(gp, big.NewFloat(1e9))
+					f, _ := gp.Float64()
+					return fmt.Sprintf("%.2f Gwei", f)
+				}(),
+				Nonce: tx.Nonce(),
+			}
+			if tx.To() != nil {
+				t.To = tx.To().Hex()
+			} else {
+				t.To = "Contract"
+			}
+			txs = append(txs, t)
+		}
+	}
+	return txs
+}
+
+// batchBlock is a block decoded from a batched eth_getBlockByNumber response.
+// err is set when that block alone could not be fetched.
+type batchBlock struct {
+	number       uint64
+	transactions []*types.Transaction
+	err          error
+}
+
+// fetchBlocksBatch requests the full blocks nums in a single JSON-RPC batch and
+// returns them in the same order. An error is returned only when the batch as a
+// whole fails.
+func fetchBlocksBatch(ctx context.Context, client *ethclient.Client, nums []uint64) ([]batchBlock, error) {
+	type rpcBlock struct {
+		Number       hexutil.Uint64    `json:"number"`
+		Transactions []json.RawMessage `json:"transactions"`
+	}
+
+	results := make([]*rpcBlock, len(nums))
+	batch := make([]gethrpc.BatchElem, len(nums))
+	for i, n := range nums {
+		batch[i] = gethrpc.BatchElem{
+			Method: "eth_getBlockByNumber",
+			Args:   []interface{}{hexutil.EncodeUint64(n), true},
+			Result: &results[i],
+		}
+	}
+	if err := client.Client().BatchCallContext(ctx, batch); err != nil {
+		return nil, err
+	}
+
+	blocks := make([]batchBlock, 0, len(batch))
+	for i, elem := range batch {
+		if elem.Error != nil {
+			blocks = append(blocks, batchBlock{err: elem.Error})
+			continue
+		}
+		if results[i] == nil {
+			blocks = append(blocks, batchBlock{err: ethereum.NotFound})
+			continue
+		}
+		b := batchBlock{number: uint64(results[i].Number)}
+		for _, raw := range results[i].Transactions {
+			tx := new(types.Transaction)
+			// Chain-specific tx types (e.g. L2 deposits) don't decode; skip them like unsigned txs.
+			if err := tx.UnmarshalJSON(raw); err != nil {
+				continue
+			}
+			b.transactions = append(b.transactions, tx)
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks, nil
+}
+
+// coinGeckoGet issues a GET with our User-Agent, retrying once after
+// CoinGeckoRetryDelay on transport errors, 429 or 5xx responses.
+func coinGeckoGet(url string) (*http.Response, error) {
+	const attempts = 2
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("User-Agent", UserAgent)
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := coinGeckoClient.Do(req)
+		retryable := err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		if !retryable || attempt == attempts {
+			return resp, err
+		}
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		time.Sleep(CoinGeckoRetryDelay)
+	}
+}
+
+// RateLimitError is returned when CoinGecko responds with 429 Too Many Requests.
+type RateLimitError struct {
+	RetryAfter time.Duration // Zero when the server did not send Retry-After
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("coingecko rate limit exceeded, retry after %s", e.RetryAfter)
+	}
+	return "coingecko rate limit exceeded"
+}
+
 // FetchEthPrice fetches the current Ethereum price in USD from CoinGecko.
 func FetchEthPrice(coinID string) (models.PriceData, error) {
 	if coinID == "" {
 		return models.PriceData{CoinID: coinID, Price: 0}, nil
 	}
-	client := &http.Client{Timeout: 10 * time.Second}
-	url := fmt.Sprintf("%s/simple/price?ids=%s&vs_currencies=usd", CoinGeckoBaseURL, coinID)
-	resp, err := client.Get(url)
+	url := fmt.Sprintf("%s/simple/price?ids=%s&vs_currencies=usd&include_24hr_change=true", CoinGeckoBaseURL, coinID)
+	resp, err := coinGeckoGet(url)
 	if err != nil {
+		logger.Warn("price fetch failed", "coin", coinID, "err", err)
 		return models.PriceData{CoinID: coinID, Err: err}, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		rlErr := &RateLimitError{}
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			rlErr.RetryAfter = time.Duration(secs) * time.Second
+		}
+		logger.Warn("price fetch rate limited", "coin", coinID, "retry_after", rlErr.RetryAfter)
+		return models.PriceData{CoinID: coinID, Err: rlErr}, rlErr
+	case resp.StatusCode != http.StatusOK:
+		err := fmt.Errorf("coingecko returned %s", resp.Status)
+		logger.Warn("price fetch failed", "coin", coinID, "err", err)
+		return models.PriceData{CoinID: coinID, Err: err}, err
+	}
+
 	var result map[string]map[string]float64
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return models.PriceData{CoinID: coinID, Err: err}, err
 	}
-	return models.PriceData{CoinID: coinID, Price: result[coinID]["usd"]}, nil
+	quote, ok := result[coinID]
+	if !ok {
+		err := fmt.Errorf("no price returned for %s", coinID)
+		return models.PriceData{CoinID: coinID, Err: err}, err
+	}
+	return models.PriceData{
+		CoinID:    coinID,
+		Price:     quote["usd"],
+		Change24h: quote["usd_24h_change"],
+	}, nil
 }
 
-// FetchGasPrice fetches the current gas price.
-func FetchGasPrice(rpcURLs []string) (models.GasPriceData, error) {
+// FetchGasPrice fetches the current gas price, giving each RPC timeout to answer.
+func FetchGasPrice(rpcURLs []string, timeout time.Duration) (models.GasPriceData, error) {
 	var failed []string
 	var lastErr error
 	for _, rpcURL := range rpcURLs {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		client, err := ethclient.Dial(rpcURL)
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		client, err := defaultPool.Get(rpcURL)
 		if err != nil {
 			failed = append(failed, rpcURL)
 			cancel()
@@ -314,9 +704,10 @@ func FetchGasPrice(rpcURLs []string) (models.GasPriceData, error) {
 			continue
 		}
 		price, err := client.SuggestGasPrice(ctx)
-		client.Close()
 		cancel()
 		if err != nil {
+			logger.Warn("gas price fetch failed", "rpc", rpcURL, "err", err)
+			defaultPool.MarkFailed(rpcURL, err)
 			failed = append(failed, rpcURL)
 			lastErr = err
 			continue
@@ -326,74 +717,531 @@ func FetchGasPrice(rpcURLs []string) (models.GasPriceData, error) {
 	return models.GasPriceData{Err: lastErr, FailedRPCs: failed}, lastErr
 }
 
+// DefaultTokenDecimals is assumed when a token's decimals() call reverts.
+const DefaultTokenDecimals = 18
+
+// maxNameSymbolLength bounds how much of name() is used when standing in for a symbol.
+const maxNameSymbolLength = 12
+
+// ERC-20 metadata selectors: symbol(), name() and decimals().
+var (
+	symbolSelector   = []byte{0x95, 0xd8, 0x9b, 0x41}
+	nameSelector     = []byte{0x06, 0xfd, 0xde, 0x03}
+	decimalsSelector = []byte{0x31, 0x3c, 0xe5, 0x67}
+)
+
 // FetchTokenMetadata fetches the symbol and decimals for a token address.
-func FetchTokenMetadata(rpcURLs []string, tokenAddress string) (models.TokenMetadata, error) {
+// If symbol() is missing it falls back to a truncated name(), and if decimals()
+// reverts it assumes DefaultTokenDecimals. In both cases the result is marked
+// Partial instead of failing outright. Each of rpcURLs is tried in turn; the
+// error lists why every one of them failed.
+func FetchTokenMetadata(rpcURLs []string, tokenAddress string, timeout time.Duration) (models.TokenMetadata, error) {
 	targetAddr := common.HexToAddress(tokenAddress)
-	// symbol() selector: 0x95d89b41
-	symbolData := []byte{0x95, 0xd8, 0x9b, 0x41}
-	// decimals() selector: 0x313ce567
-	decimalsData := []byte{0x31, 0x3c, 0xe5, 0x67}
 
+	var causes []string
+	for _, rpcURL := range rpcURLs {
+		meta, err := fetchTokenMetadataFrom(rpcURL, targetAddr, timeout)
+		if err == nil {
+			meta.RPCURL = rpcURL
+			return meta, nil
+		}
+		logger.Warn("token metadata fetch failed", "rpc", rpcURL, "token", tokenAddress, "err", err)
+		causes = append(causes, fmt.Sprintf("%s: %v", hostOf(rpcURL), err))
+	}
+	if len(causes) == 0 {
+		causes = append(causes, "no RPC URLs configured")
+	}
+	err := fmt.Errorf("failed to fetch metadata: %s", strings.Join(causes, "; "))
+	return models.TokenMetadata{Err: err}, err
+}
+
+// fetchTokenMetadataFrom calls symbol(), name() and decimals() through one RPC.
+// If they come back empty, e.g. from a node serving a stale view of a proxy's
+// implementation, they are repeated once pinned to the node's latest block
+// number before giving up on the RPC.
+func fetchTokenMetadataFrom(rpcURL string, targetAddr common.Address, timeout time.Duration) (models.TokenMetadata, error) {
+	client, err := defaultPool.Get(rpcURL)
+	if err != nil {
+		return models.TokenMetadata{}, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var block *big.Int // nil calls at "latest"
+	for {
+		// Failed calls leave their result nil, which buildTokenMetadata treats as missing.
+		resSymbol, errSymbol := client.CallContract(ctx, ethereum.CallMsg{To: &targetAddr, Data: symbolSelector}, block)
+		resName, _ := client.CallContract(ctx, ethereum.CallMsg{To: &targetAddr, Data: nameSelector}, block)
+		resDecimals, errDecimals := client.CallContract(ctx, ethereum.CallMsg{To: &targetAddr, Data: decimalsSelector}, block)
+		if meta, ok := buildTokenMetadata(targetAddr, resSymbol, resName, resDecimals); ok {
+			return meta, nil
+		}
+
+		err = errDecimals
+		if err == nil {
+			err = errSymbol
+		}
+		var rpcErr gethrpc.Error
+		if err != nil && !errors.As(err, &rpcErr) {
+			// The RPC itself failed; another attempt here would too.
+			defaultPool.MarkFailed(rpcURL, err)
+			return models.TokenMetadata{}, err
+		}
+		if err == nil {
+			err = fmt.Errorf("symbol() and decimals() returned no data")
+		}
+		if block != nil {
+			return models.TokenMetadata{}, fmt.Errorf("%w (also at block %s)", err, block)
+		}
+		head, headErr := client.BlockNumber(ctx)
+		if headErr != nil {
+			defaultPool.MarkFailed(rpcURL, headErr)
+			return models.TokenMetadata{}, err
+		}
+		block = new(big.Int).SetUint64(head)
+	}
+}
+
+// FetchNativeBalance returns the address's native balance in wei from the first
+// of rpcURLs that answers.
+func FetchNativeBalance(rpcURLs []string, address string, timeout time.Duration) (*big.Int, error) {
+	err := fmt.Errorf("no RPC URLs")
 	for _, rpcURL := range rpcURLs {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		client, err := ethclient.Dial(rpcURL)
+		var client *ethclient.Client
+		client, err = defaultPool.Get(rpcURL)
 		if err != nil {
-			cancel()
 			continue
 		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		var balance *big.Int
+		balance, err = client.BalanceAt(ctx, common.HexToAddress(address), nil)
+		cancel()
+		defaultPool.MarkFailed(rpcURL, err)
+		if err == nil {
+			return balance, nil
+		}
+	}
+	return nil, err
+}
+
+// IsContract reports whether code is deployed at address, i.e. it is a contract
+// such as a multisig rather than a wallet. An EOA with an EIP-7702 delegation
+// also has code, but still has a key and is not counted.
+func IsContract(rpcURL, address string, timeout time.Duration) (bool, error) {
+	client, err := defaultPool.Get(rpcURL)
+	if err != nil {
+		return false, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	code, err := client.CodeAt(ctx, common.HexToAddress(address), nil)
+	defaultPool.MarkFailed(rpcURL, err)
+	if err != nil {
+		return false, err
+	}
+	return len(code) > 0 && !isDelegationDesignator(code), nil
+}
+
+// isDelegationDesignator reports whether code is an EIP-7702 delegation
+// (0xef0100 followed by the delegate's address).
+func isDelegationDesignator(code []byte) bool {
+	return len(code) == 23 && bytes.HasPrefix(code, []byte{0xef, 0x01, 0x00})
+}
+
+// IsWebsocketURL reports whether an RPC URL uses the ws:// or wss:// scheme.
+func IsWebsocketURL(rpcURL string) bool {
+	return strings.HasPrefix(rpcURL, "ws://") || strings.HasPrefix(rpcURL, "wss://")
+}
+
+// SubscribeNewHeads opens an eth_subscribe("newHeads") subscription on a websocket
+// endpoint. The returned channel receives each new block header and is closed when
+// the subscription fails or ctx is cancelled. It dials its own connection rather
+// than using the client pool, since the subscription owns it for its lifetime.
+func SubscribeNewHeads(ctx context.Context, wsURL string) (<-chan *types.Header, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	rc, err := dialWebsocket(dialCtx, wsURL)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+	client := ethclient.NewClient(rc)
+
+	heads := make(chan *types.Header)
+	sub, err := client.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
 
-		var symbol string
-		var decimals int
-
-		// Fetch Symbol
-		msgSymbol := ethereum.CallMsg{To: &targetAddr, Data: symbolData}
-		resSymbol, err := client.CallContract(ctx, msgSymbol, nil)
-		if err == nil && len(resSymbol) > 0 {
-			if len(resSymbol) == 32 {
-				// bytes32
-				symbol = string(bytes.TrimRight(resSymbol, "\x00"))
-			} else if len(resSymbol) >= 64 {
-				// string
-				length := new(big.Int).SetBytes(resSymbol[32:64]).Int64()
-				if length > 0 && 64+int(length) <= len(resSymbol) {
-					symbol = string(resSymbol[64 : 64+length])
+	out := make(chan *types.Header)
+	go func() {
+		defer close(out)
+		defer client.Close()
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case h := <-heads:
+				select {
+				case out <- h:
+				case <-ctx.Done():
+					return
 				}
+			case <-sub.Err():
+				return
+			case <-ctx.Done():
+				return
 			}
 		}
+	}()
+	return out, nil
+}
 
-		// Fetch Decimals
-		msgDecimals := ethereum.CallMsg{To: &targetAddr, Data: decimalsData}
-		resDecimals, err := client.CallContract(ctx, msgDecimals, nil)
-		client.Close()
-		cancel()
+// tokenList is the subset of the tokenlist.org (Uniswap token list) schema we use.
+type tokenList struct {
+	Tokens []struct {
+		ChainID    int64  `json:"chainId"`
+		Address    string `json:"address"`
+		Symbol     string `json:"symbol"`
+		Decimals   int    `json:"decimals"`
+		Extensions struct {
+			CoinGeckoID string `json:"coingeckoId"`
+		} `json:"extensions"`
+	} `json:"tokens"`
+}
+
+// MaxTokenListBytes caps the size of a downloaded token list. The largest public
+// lists are a few megabytes.
+var MaxTokenListBytes int64 = 32 << 20
+
+// FetchTokenList downloads a standard token list and returns the entries for chainID.
+func FetchTokenList(url string, chainID int64) ([]config.TokenConfig, error) {
+	if chainID == 0 {
+		return nil, fmt.Errorf("chain has no chain_id configured")
+	}
+	client := &http.Client{Timeout: 15 * time.Second, Transport: &rateLimitedTransport{}}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status fetching token list: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxTokenListBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > MaxTokenListBytes {
+		return nil, fmt.Errorf("token list is larger than %d bytes", MaxTokenListBytes)
+	}
+	var list tokenList
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("invalid token list: %w", err)
+	}
+
+	var tokens []config.TokenConfig
+	for _, t := range list.Tokens {
+		if t.ChainID != chainID || !common.IsHexAddress(t.Address) {
+			continue
+		}
+		tokens = append(tokens, config.TokenConfig{
+			Symbol:      t.Symbol,
+			Address:     t.Address,
+			Decimals:    t.Decimals,
+			CoinGeckoID: t.Extensions.CoinGeckoID,
+		})
+	}
+	return tokens, nil
+}
+
+// MaxBatchCalls caps the calls sent in one JSON-RPC batch. Public providers
+// commonly reject or throttle batches of more than 10 to 100 calls.
+var MaxBatchCalls = 99
+
+// FetchTokenMetadataBatch fetches symbol, name and decimals for many tokens at once.
+// The calls are sent as JSON-RPC batches of at most MaxBatchCalls, so importing a
+// long token list costs a few round-trips instead of three per token. Each batch
+// goes to the first of rpcURLs that answers it within timeout. The result slice is
+// aligned with tokenAddresses; tokens that could not be resolved have Err set.
+func FetchTokenMetadataBatch(rpcURLs []string, tokenAddresses []string, timeout time.Duration) ([]models.TokenMetadata, error) {
+	if len(tokenAddresses) == 0 {
+		return nil, nil
+	}
+	perBatch := max(MaxBatchCalls/len(tokenMetadataSelectors), 1)
+	metas := make([]models.TokenMetadata, 0, len(tokenAddresses))
+	for start := 0; start < len(tokenAddresses); start += perBatch {
+		chunk := tokenAddresses[start:min(start+perBatch, len(tokenAddresses))]
+		var chunkMetas []models.TokenMetadata
+		err := fmt.Errorf("no RPC URLs configured")
+		for _, rpcURL := range rpcURLs {
+			if chunkMetas, err = fetchTokenMetadataChunk(rpcURL, chunk, timeout); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch token metadata batch: %w", err)
+		}
+		metas = append(metas, chunkMetas...)
+	}
+	return metas, nil
+}
+
+// tokenMetadataSelectors are the calls FetchTokenMetadataBatch makes per token.
+var tokenMetadataSelectors = [][]byte{symbolSelector, nameSelector, decimalsSelector}
+
+// fetchTokenMetadataChunk resolves tokenAddresses in a single batch through rpcURL.
+func fetchTokenMetadataChunk(rpcURL string, tokenAddresses []string, timeout time.Duration) ([]models.TokenMetadata, error) {
+	selectors := tokenMetadataSelectors
+	client, err := defaultPool.Get(rpcURL)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]hexutil.Bytes, len(tokenAddresses)*len(selectors))
+	batch := make([]gethrpc.BatchElem, 0, len(results))
+	for i, addr := range tokenAddresses {
+		for j, sel := range selectors {
+			batch = append(batch, gethrpc.BatchElem{
+				Method: "eth_call",
+				Args: []interface{}{
+					map[string]interface{}{"to": common.HexToAddress(addr), "input": hexutil.Bytes(sel)},
+					"latest",
+				},
+				Result: &results[i*len(selectors)+j],
+			})
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	err = client.Client().BatchCallContext(ctx, batch)
+	cancel()
+	if err != nil {
+		defaultPool.MarkFailed(rpcURL, err)
+		return nil, err
+	}
 
-		if err == nil && len(resDecimals) > 0 {
-			decimals = int(new(big.Int).SetBytes(resDecimals).Int64())
-			return models.TokenMetadata{Symbol: symbol, Decimals: decimals}, nil
+	metas := make([]models.TokenMetadata, len(tokenAddresses))
+	for i, addr := range tokenAddresses {
+		res := func(j int) []byte {
+			if batch[i*len(selectors)+j].Error != nil {
+				return nil
+			}
+			return results[i*len(selectors)+j]
+		}
+		meta, ok := buildTokenMetadata(common.HexToAddress(addr), res(0), res(1), res(2))
+		if !ok {
+			meta = models.TokenMetadata{Err: fmt.Errorf("failed to fetch metadata for %s", addr)}
 		}
+		metas[i] = meta
+	}
+	return metas, nil
+}
+
+// buildTokenMetadata assembles metadata from raw symbol(), name() and decimals() results.
+// A nil or empty result means the call failed. It reports false when neither a symbol
+// nor decimals could be determined.
+func buildTokenMetadata(targetAddr common.Address, resSymbol, resName, resDecimals []byte) (models.TokenMetadata, bool) {
+	var meta models.TokenMetadata
+
+	rawSymbol := len(resSymbol) > 0
+	if rawSymbol {
+		meta.Symbol = decodeStringResult(resSymbol)
+	}
+
+	// Name is used as the symbol when symbol() is missing or undecodable
+	if len(resName) > 0 {
+		meta.Name = decodeStringResult(resName)
+	}
+	if meta.Symbol == "" && meta.Name != "" {
+		meta.Symbol = utils.TruncateString(meta.Name, maxNameSymbolLength)
+		meta.Partial = true
+	}
+	if meta.Symbol == "" && rawSymbol {
+		// Undecodable symbol: fall back to something recognisable for this token.
+		meta.Symbol = targetAddr.Hex()[:8]
+		meta.Partial = true
+	}
+
+	decimalsOK := len(resDecimals) > 0
+	if decimalsOK {
+		meta.Decimals = int(new(big.Int).SetBytes(resDecimals).Int64())
+	} else {
+		meta.Decimals = DefaultTokenDecimals
+		meta.Partial = true
+		meta.DecimalsDefaulted = true
+	}
+
+	return meta, decimalsOK || meta.Symbol != ""
+}
+
+// decodeStringResult decodes the return value of symbol()/name(), which is either an
+// ABI-encoded dynamic string or, for older tokens such as MKR, a bytes32.
+// It returns an empty string if the data doesn't decode to printable UTF-8.
+func decodeStringResult(res []byte) string {
+	if len(res) == 32 {
+		return cleanSymbol(res)
+	}
+	if len(res) < 64 {
+		return ""
+	}
+	// Offset and length come from the contract, so they are compared against
+	// what is left of res rather than added to, which could overflow.
+	offset := new(big.Int).SetBytes(res[:32])
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(res)-32) {
+		return ""
+	}
+	off := int(offset.Uint64())
+	length := new(big.Int).SetBytes(res[off : off+32])
+	if !length.IsUint64() || length.Uint64() > uint64(len(res)-off-32) {
+		return ""
 	}
-	return models.TokenMetadata{Err: fmt.Errorf("failed to fetch metadata")}, fmt.Errorf("failed to fetch metadata")
+	return cleanSymbol(res[off+32 : off+32+int(length.Uint64())])
 }
 
-// FetchRPCLatency pings an RPC URL to measure latency.
-func FetchRPCLatency(rpcURL string) (models.RPCLatencyData, error) {
+// cleanSymbol strips padding and non-printable characters, rejecting invalid UTF-8.
+func cleanSymbol(b []byte) string {
+	b = bytes.TrimRight(b, "\x00")
+	if !utf8.Valid(b) {
+		return ""
+	}
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		if !unicode.IsPrint(r) {
+			return -1
+		}
+		return r
+	}, string(b)))
+}
+
+// FetchRPCLatency pings an RPC URL to measure latency and report its current head.
+func FetchRPCLatency(rpcURL string, timeout time.Duration) (models.RPCLatencyData, error) {
 	// Actually the logic in main.go returned rpcLatencyMsg
 	// Here we can return just duration and error
 	start := time.Now()
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	client, err := ethclient.Dial(rpcURL)
+	client, err := defaultPool.Get(rpcURL)
 	if err != nil {
 		return models.RPCLatencyData{RPCURL: rpcURL, Err: err}, err
 	}
-	defer client.Close()
 
-	_, err = client.HeaderByNumber(ctx, nil)
+	header, err := client.HeaderByNumber(ctx, nil)
 	if err != nil {
+		defaultPool.MarkFailed(rpcURL, err)
 		return models.RPCLatencyData{RPCURL: rpcURL, Err: err}, err
 	}
-	return models.RPCLatencyData{RPCURL: rpcURL, Latency: time.Since(start)}, nil
+	return models.RPCLatencyData{
+		RPCURL:      rpcURL,
+		Latency:     time.Since(start),
+		BlockNumber: header.Number.Uint64(),
+		BlockTime:   time.Unix(int64(header.Time), 0),
+	}, nil
+}
+
+// probeChainID connects to rpcURL and asks for its chain ID, the check every RPC
+// test starts with. Like the rest of a test, it leaves the RPC's pool state alone.
+func probeChainID(ctx context.Context, rpcURL string) (*ethclient.Client, *big.Int, error) {
+	client, err := defaultPool.Get(rpcURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	id, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	return client, id, nil
+}
+
+// FetchChainID returns the chain ID reported by rpcURL. It is the per-RPC check of -t.
+func FetchChainID(rpcURL string, timeout time.Duration) (*big.Int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	_, id, err := probeChainID(ctx, rpcURL)
+	return id, err
+}
+
+// ProbeRPC runs the checks of -t against a single RPC, plus its head and sync
+// status. A failed eth_syncing only sets SyncErr, as not every provider serves it.
+func ProbeRPC(rpcURL string, timeout time.Duration) models.RPCProbeData {
+	res := models.RPCProbeData{RPCURL: rpcURL}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, id, err := probeChainID(ctx, rpcURL)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	res.ChainID = id.Int64()
+
+	start := time.Now()
+	block, err := client.BlockNumber(ctx)
+	if err != nil {
+		res.Err = fmt.Errorf("failed to get block number: %w", err)
+		return res
+	}
+	res.Latency = time.Since(start)
+	res.BlockNumber = block
+
+	progress, err := client.SyncProgress(ctx)
+	switch {
+	case err != nil:
+		res.SyncErr = err
+	case progress != nil:
+		res.Syncing = true
+		res.CurrentBlock = progress.CurrentBlock
+		res.HighestBlock = progress.HighestBlock
+	}
+	return res
 }
 
 // Helpers
+
+// CoinSuggestion is one coin returned by a CoinGecko search.
+type CoinSuggestion struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Symbol        string `json:"symbol"`
+	MarketCapRank int    `json:"market_cap_rank"` // Zero when unranked
+}
+
+var (
+	coinSearchMu    sync.Mutex
+	coinSearchCache = make(map[string][]CoinSuggestion) // Key: lowercase query
+)
+
+// SearchCoinGecko returns the coins matching query, best match first, using
+// CoinGecko's /search endpoint. Results are cached for the rest of the session.
+func SearchCoinGecko(query string) ([]CoinSuggestion, error) {
+	key := strings.ToLower(strings.TrimSpace(query))
+	if key == "" {
+		return nil, nil
+	}
+	coinSearchMu.Lock()
+	cached, ok := coinSearchCache[key]
+	coinSearchMu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	resp, err := coinGeckoGet(fmt.Sprintf("%s/search?query=%s", CoinGeckoBaseURL, url.QueryEscape(key)))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("coingecko returned %s", resp.Status)
+	}
+
+	var result struct {
+		Coins []CoinSuggestion `json:"coins"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	coinSearchMu.Lock()
+	coinSearchCache[key] = result.Coins
+	coinSearchMu.Unlock()
+	return result.Coins, nil
+}
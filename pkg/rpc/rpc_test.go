@@ -1,12 +1,17 @@
 package rpc
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"io"
+	"math"
 	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"evmbal/pkg/config"
 	"evmbal/pkg/models"
@@ -101,6 +106,168 @@ func TestFetchChainData_Integration(t *testing.T) {
 	}
 }
 
+func TestFetchChainData_RejectsWrongChainID(t *testing.T) {
+	node := func(chainID, balance string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req struct {
+				ID     int    `json:"id"`
+				Method string `json:"method"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			result := balance
+			if req.Method == "eth_chainId" {
+				result = chainID
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+		}))
+	}
+	testnet := node("0xaa36a7", "0x4563918244F40000") // Sepolia, 5 ETH
+	defer testnet.Close()
+	mainnet := node("0x1", "0x22B1C8C1227A0000") // 2.5 ETH
+	defer mainnet.Close()
+
+	chain := config.ChainConfig{Name: "Ethereum", ChainID: 1, RPCURLs: []string{testnet.URL, mainnet.URL}}
+	accounts := []*models.Account{{Address: "0xAb5801a7D398351b8bE11C439e05C5B3259aeC9B"}}
+
+	data, err := FetchChainData(chain, accounts)
+	if err != nil || data.Err != nil {
+		t.Fatalf("FetchChainData error: %v / %v", err, data.Err)
+	}
+	if len(data.FailedRPCs) != 1 || data.FailedRPCs[0] != testnet.URL {
+		t.Errorf("Expected the testnet RPC to be marked failed, got %v", data.FailedRPCs)
+	}
+	if len(data.Results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(data.Results))
+	}
+	if got, _ := data.Results[0].Balance.Float64(); got != 2.5 {
+		t.Errorf("Expected the mainnet balance 2.5, got %v", got)
+	}
+
+	chain.RPCURLs = []string{testnet.URL}
+	if data, _ := FetchChainData(chain, accounts); data.Err == nil || !strings.Contains(data.Err.Error(), "chain ID 11155111") {
+		t.Errorf("Expected a chain ID mismatch error, got %v", data.Err)
+	}
+}
+
+func TestDecodeTokenBalance(t *testing.T) {
+	token := config.TokenConfig{Symbol: "USDC", Address: "0x1234567890123456789012345678901234567890", Decimals: 6}
+	tests := []struct {
+		name    string
+		result  []byte
+		want    string // "" for no balance
+		wantErr bool
+	}{
+		{"word", common.LeftPadBytes(big.NewInt(1_500_000).Bytes(), 32), "1.5", false},
+		{"empty", nil, "", false},
+		{"short", []byte{0x01, 0x02}, "", true},
+		{"long", make([]byte, 64), "", true},
+	}
+	for _, tt := range tests {
+		bal, err := decodeTokenBalance(token, tt.result)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		got := ""
+		if bal != nil {
+			got = bal.Text('f', -1)
+		}
+		if got != tt.want {
+			t.Errorf("%s: balance = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBalanceCallData(t *testing.T) {
+	holder := common.HexToAddress("0xAb5801a7D398351b8bE11C439e05C5B3259aeC9B")
+	word := func(b []byte) string { return common.Bytes2Hex(common.LeftPadBytes(b, 32)) }
+	tests := []struct {
+		name    string
+		token   config.TokenConfig
+		want    string
+		wantErr bool
+	}{
+		{"default", config.TokenConfig{}, "70a08231" + word(holder.Bytes()), false},
+		{"erc20", config.TokenConfig{BalanceMethod: "ERC20"}, "70a08231" + word(holder.Bytes()), false},
+		{"erc1155", config.TokenConfig{BalanceMethod: "erc1155", TokenID: "0x2a"}, "00fdd58e" + word(holder.Bytes()) + word([]byte{42}), false},
+		{"custom", config.TokenConfig{BalanceMethod: "0xf8b2cb4f"}, "f8b2cb4f" + word(holder.Bytes()), false},
+		{"erc1155 without id", config.TokenConfig{BalanceMethod: "erc1155"}, "", true},
+		{"negative id", config.TokenConfig{BalanceMethod: "erc1155", TokenID: "-1"}, "", true},
+		{"unknown", config.TokenConfig{BalanceMethod: "sharesOf"}, "", true},
+		{"long selector", config.TokenConfig{BalanceMethod: "0x70a0823100"}, "", true},
+		{"custom default args", config.TokenConfig{Custom: true, BalanceMethod: "0x008cc262"}, "008cc262" + word(holder.Bytes()), false},
+		{"custom args", config.TokenConfig{Custom: true, BalanceMethod: "0x93f1a40b", CallArgs: []string{"3", "holder"}}, "93f1a40b" + word([]byte{3}) + word(holder.Bytes()), false},
+		{"custom without selector", config.TokenConfig{Custom: true, BalanceMethod: "erc20"}, "", true},
+		{"custom bad arg", config.TokenConfig{Custom: true, BalanceMethod: "0x93f1a40b", CallArgs: []string{"me"}}, "", true},
+		{"args without custom", config.TokenConfig{CallArgs: []string{"holder"}}, "", true},
+	}
+	for _, tt := range tests {
+		data, err := BalanceCallData(tt.token, holder)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got := common.Bytes2Hex(data); got != tt.want {
+			t.Errorf("%s: data = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFetchChainData_TokenResults(t *testing.T) {
+	node := func(callResult string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req struct {
+				ID     int    `json:"id"`
+				Method string `json:"method"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			result := "0x0"
+			if req.Method == "eth_call" {
+				result = callResult
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+		}))
+	}
+	garbled := node("0x1234")
+	defer garbled.Close()
+	noContract := node("0x")
+	defer noContract.Close()
+
+	chain := config.ChainConfig{
+		Name:    "MockChain",
+		RPCURLs: []string{garbled.URL, noContract.URL},
+		Tokens:  []config.TokenConfig{{Symbol: "TEST", Address: "0x1234567890123456789012345678901234567890", Decimals: 6}},
+	}
+	data, err := FetchChainData(chain, []*models.Account{{Address: "0xAb5801a7D398351b8bE11C439e05C5B3259aeC9B"}})
+	if err != nil || data.Err != nil {
+		t.Fatalf("FetchChainData error: %v / %v", err, data.Err)
+	}
+	if len(data.FailedRPCs) != 1 || data.FailedRPCs[0] != garbled.URL {
+		t.Errorf("Expected the garbled RPC to fail over, failed RPCs %v", data.FailedRPCs)
+	}
+	if len(data.Results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(data.Results))
+	}
+	if bal, ok := data.Results[0].TokenBalances["TEST"]; ok {
+		t.Errorf("Expected no balance for a token without a contract, got %v", bal)
+	}
+}
+
+func TestFetchChainData_NoRPCs(t *testing.T) {
+	chain := config.ChainConfig{Name: "Empty"}
+	accounts := []*models.Account{{Address: "0xAb5801a7D398351b8bE11C439e05C5B3259aeC9B"}}
+
+	data, err := FetchChainData(chain, accounts)
+	if err == nil {
+		t.Fatal("Expected error for chain without RPC URLs, got nil")
+	}
+	if data.Err == nil {
+		t.Error("Expected ChainData.Err to be set")
+	}
+	if len(data.Results) != 0 {
+		t.Errorf("Expected no results, got %d", len(data.Results))
+	}
+}
+
 func TestFetchGasPrice_Integration(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		resp := map[string]interface{}{
@@ -112,7 +279,7 @@ func TestFetchGasPrice_Integration(t *testing.T) {
 	}))
 	defer server.Close()
 
-	gasMsg, err := FetchGasPrice([]string{server.URL})
+	gasMsg, err := FetchGasPrice([]string{server.URL}, DefaultRequestTimeout)
 	if err != nil {
 		t.Fatalf("FetchGasPrice error: %v", err)
 	}
@@ -126,7 +293,7 @@ func TestFetchGasPrice_Integration(t *testing.T) {
 func TestFetchEthPrice_Integration(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		response := map[string]map[string]float64{
-			"ethereum": {"usd": 2500.50},
+			"ethereum": {"usd": 2500.50, "usd_24h_change": -1.25},
 		}
 		_ = json.NewEncoder(w).Encode(response)
 	}))
@@ -143,6 +310,73 @@ func TestFetchEthPrice_Integration(t *testing.T) {
 	if pMsg.Price != 2500.50 {
 		t.Errorf("Expected price 2500.50, got %f", pMsg.Price)
 	}
+	if pMsg.Change24h != -1.25 {
+		t.Errorf("Expected 24h change -1.25, got %f", pMsg.Change24h)
+	}
+}
+
+func TestFetchEthPrice_RateLimited(t *testing.T) {
+	originalDelay := CoinGeckoRetryDelay
+	CoinGeckoRetryDelay = time.Millisecond
+	defer func() { CoinGeckoRetryDelay = originalDelay }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"status":{"error_code":429,"error_message":"You've exceeded the Rate Limit"}}`))
+	}))
+	defer server.Close()
+
+	originalURL := CoinGeckoBaseURL
+	CoinGeckoBaseURL = server.URL
+	defer func() { CoinGeckoBaseURL = originalURL }()
+
+	pMsg, err := FetchEthPrice("ethereum")
+	var rlErr *RateLimitError
+	if !errors.As(err, &rlErr) {
+		t.Fatalf("Expected RateLimitError, got %v", err)
+	}
+	if rlErr.RetryAfter != 30*time.Second {
+		t.Errorf("Expected RetryAfter 30s, got %s", rlErr.RetryAfter)
+	}
+	if pMsg.Price != 0 || pMsg.Err == nil {
+		t.Errorf("Expected no price and an error in the result, got %+v", pMsg)
+	}
+}
+
+func TestFetchEthPrice_RetriesWithUserAgent(t *testing.T) {
+	originalDelay := CoinGeckoRetryDelay
+	CoinGeckoRetryDelay = time.Millisecond
+	defer func() { CoinGeckoRetryDelay = originalDelay }()
+
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("User-Agent") != UserAgent {
+			t.Errorf("Unexpected User-Agent %q", r.Header.Get("User-Agent"))
+		}
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]map[string]float64{"ethereum": {"usd": 2500}})
+	}))
+	defer server.Close()
+
+	originalURL := CoinGeckoBaseURL
+	CoinGeckoBaseURL = server.URL
+	defer func() { CoinGeckoBaseURL = originalURL }()
+
+	pMsg, err := FetchEthPrice("ethereum")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 requests, got %d", calls)
+	}
+	if pMsg.Price != 2500 {
+		t.Errorf("Expected price 2500, got %f", pMsg.Price)
+	}
 }
 
 func TestFetchTransactions_Integration(t *testing.T) {
@@ -171,17 +405,13 @@ func TestFetchTransactions_Integration(t *testing.T) {
 	sigV, sigR, sigS := signedTx.RawSignatureValues()
 	txHash := signedTx.Hash().Hex()
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		var req struct {
-			ID     int           `json:"id"`
-			Method string        `json:"method"`
-			Params []interface{} `json:"params"`
-		}
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "bad request", http.StatusBadRequest)
-			return
-		}
-
+	type rpcReq struct {
+		ID     int           `json:"id"`
+		Method string        `json:"method"`
+		Params []interface{} `json:"params"`
+	}
+	var blockBatches, singleBlockCalls int
+	respond := func(req rpcReq) map[string]interface{} {
 		var result interface{}
 
 		switch req.Method {
@@ -238,16 +468,43 @@ func TestFetchTransactions_Integration(t *testing.T) {
 			result = "0x0"
 		}
 
-		resp := map[string]interface{}{
+		return map[string]interface{}{
 			"jsonrpc": "2.0",
 			"id":      req.ID,
 			"result":  result,
 		}
-		_ = json.NewEncoder(w).Encode(resp)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+			var reqs []rpcReq
+			if err := json.Unmarshal(body, &reqs); err != nil {
+				http.Error(w, "bad request", http.StatusBadRequest)
+				return
+			}
+			blockBatches++
+			var resps []map[string]interface{}
+			for _, req := range reqs {
+				resps = append(resps, respond(req))
+			}
+			_ = json.NewEncoder(w).Encode(resps)
+			return
+		}
+
+		var req rpcReq
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if req.Method == "eth_getBlockByNumber" && req.Params[1] == true {
+			singleBlockCalls++
+		}
+		_ = json.NewEncoder(w).Encode(respond(req))
 	}))
 	defer server.Close()
 
-	txs, _, err := FetchTransactions(targetAddress, []string{server.URL}, 4)
+	txs, _, err := FetchTransactions(targetAddress, config.ChainConfig{RPCURLs: []string{server.URL}}, 4)
 	if err != nil {
 		t.Fatalf("FetchTransactions returned error: %v", err)
 	}
@@ -255,6 +512,9 @@ func TestFetchTransactions_Integration(t *testing.T) {
 	if len(txs) != 1 {
 		t.Fatalf("Expected 1 transaction, got %d", len(txs))
 	}
+	if blockBatches != 1 || singleBlockCalls != 0 {
+		t.Errorf("Expected blocks in a single batch, got %d batches and %d single calls", blockBatches, singleBlockCalls)
+	}
 
 	tx := txs[0]
 	if tx.Hash != txHash {
@@ -270,3 +530,561 @@ func TestFetchTransactions_Integration(t *testing.T) {
 		t.Errorf("Expected value '1.0000', got '%s'", tx.Value)
 	}
 }
+
+func TestDecodeStringResult(t *testing.T) {
+	word := func(b []byte) []byte {
+		out := make([]byte, 32)
+		copy(out, b)
+		return out
+	}
+	uint256 := func(n int64) []byte {
+		return common.LeftPadBytes(big.NewInt(n).Bytes(), 32)
+	}
+	concat := func(parts ...[]byte) []byte {
+		var out []byte
+		for _, p := range parts {
+			out = append(out, p...)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name     string
+		input    []byte
+		expected string
+	}{
+		{"MKR bytes32", word([]byte("MKR")), "MKR"},
+		{"Standard string", concat(uint256(32), uint256(4), word([]byte("USDC"))), "USDC"},
+		{"Non-standard offset", concat(uint256(64), uint256(0), uint256(3), word([]byte("DAI"))), "DAI"},
+		{"Invalid UTF-8 bytes32", word([]byte{0xff, 0xfe, 0xfd}), ""},
+		{"Control characters stripped", word([]byte("W\x01ETH")), "WETH"},
+		{"Length out of range", concat(uint256(32), uint256(100), word([]byte("BAD"))), ""},
+		{"Too short", []byte{0x01, 0x02}, ""},
+		{"Offset near MaxInt64", concat(uint256(math.MaxInt64-16), uint256(3), word([]byte("BAD"))), ""},
+		{"Length near MaxInt64", concat(uint256(32), uint256(math.MaxInt64-32), word([]byte("BAD"))), ""},
+	}
+
+	for _, tt := range tests {
+		if got := decodeStringResult(tt.input); got != tt.expected {
+			t.Errorf("%s: decodeStringResult() = %q; want %q", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestFetchTokenMetadata_Fallbacks(t *testing.T) {
+	// ABI-encoded "Wrapped Ether Token"
+	name := "Wrapped Ether Token"
+	nameResult := "0x" + common.Bytes2Hex(common.LeftPadBytes(big.NewInt(32).Bytes(), 32)) +
+		common.Bytes2Hex(common.LeftPadBytes(big.NewInt(int64(len(name))).Bytes(), 32)) +
+		common.Bytes2Hex(common.RightPadBytes([]byte(name), 32))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int           `json:"id"`
+			Method string        `json:"method"`
+			Params []interface{} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		callArg, _ := req.Params[0].(map[string]interface{})
+		input, _ := callArg["input"].(string)
+		switch input {
+		case "0x06fdde03": // name()
+			resp["result"] = nameResult
+		default: // symbol() and decimals() revert
+			resp["error"] = map[string]interface{}{"code": 3, "message": "execution reverted"}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	meta, err := FetchTokenMetadata([]string{server.URL}, "0x1234567890123456789012345678901234567890", DefaultRequestTimeout)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !meta.Partial {
+		t.Error("Expected partial metadata")
+	}
+	if meta.Name != name {
+		t.Errorf("Expected name %q, got %q", name, meta.Name)
+	}
+	if meta.Symbol != "Wrapped E..." {
+		t.Errorf("Expected truncated name as symbol, got %q", meta.Symbol)
+	}
+	if meta.Decimals != DefaultTokenDecimals || !meta.DecimalsDefaulted {
+		t.Errorf("Expected default decimals %d to be flagged, got %d (defaulted %v)", DefaultTokenDecimals, meta.Decimals, meta.DecimalsDefaulted)
+	}
+}
+
+func TestFetchTokenMetadataBatch(t *testing.T) {
+	good := "0x1111111111111111111111111111111111111111"
+	bad := "0x2222222222222222222222222222222222222222"
+	symbol := "USDC"
+	symbolResult := "0x" + common.Bytes2Hex(common.LeftPadBytes(big.NewInt(32).Bytes(), 32)) +
+		common.Bytes2Hex(common.LeftPadBytes(big.NewInt(int64(len(symbol))).Bytes(), 32)) +
+		common.Bytes2Hex(common.RightPadBytes([]byte(symbol), 32))
+	decimalsResult := "0x" + common.Bytes2Hex(common.LeftPadBytes(big.NewInt(6).Bytes(), 32))
+
+	var batches, maxCalls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []struct {
+			ID     int           `json:"id"`
+			Method string        `json:"method"`
+			Params []interface{} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+			http.Error(w, "expected batch request", http.StatusBadRequest)
+			return
+		}
+		batches++
+		maxCalls = max(maxCalls, len(reqs))
+		var resps []map[string]interface{}
+		for _, req := range reqs {
+			resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+			callArg, _ := req.Params[0].(map[string]interface{})
+			to, _ := callArg["to"].(string)
+			input, _ := callArg["input"].(string)
+			switch {
+			case strings.EqualFold(to, good) && input == "0x95d89b41":
+				resp["result"] = symbolResult
+			case strings.EqualFold(to, good) && input == "0x313ce567":
+				resp["result"] = decimalsResult
+			default:
+				resp["error"] = map[string]interface{}{"code": 3, "message": "execution reverted"}
+			}
+			resps = append(resps, resp)
+		}
+		_ = json.NewEncoder(w).Encode(resps)
+	}))
+	defer server.Close()
+
+	metas, err := FetchTokenMetadataBatch([]string{server.URL}, []string{good, bad}, DefaultRequestTimeout)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if batches != 1 {
+		t.Errorf("Expected a single batch request, got %d", batches)
+	}
+	if len(metas) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(metas))
+	}
+	if metas[0].Err != nil || metas[0].Symbol != symbol || metas[0].Decimals != 6 || metas[0].Partial {
+		t.Errorf("Unexpected metadata for good token: %+v", metas[0])
+	}
+	if metas[1].Err == nil {
+		t.Error("Expected error for unresolvable token")
+	}
+
+	// More tokens than fit in one batch are split, and results stay aligned.
+	defer func(n int) { MaxBatchCalls = n }(MaxBatchCalls)
+	MaxBatchCalls = 6
+	batches, maxCalls = 0, 0
+	addrs := []string{bad, bad, bad, bad, good}
+	metas, err = FetchTokenMetadataBatch([]string{server.URL}, addrs, DefaultRequestTimeout)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if batches != 3 || maxCalls > MaxBatchCalls {
+		t.Errorf("Expected 3 batches of at most %d calls, got %d (largest %d)", MaxBatchCalls, batches, maxCalls)
+	}
+	if len(metas) != len(addrs) || metas[4].Symbol != symbol || metas[3].Err == nil {
+		t.Errorf("Unexpected results: %+v", metas)
+	}
+}
+
+func TestFetchTokenList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"name": "Test List",
+			"tokens": [
+				{"chainId": 1, "address": "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", "symbol": "USDC", "decimals": 6, "extensions": {"coingeckoId": "usd-coin"}},
+				{"chainId": 10, "address": "0x0b2C639c533813f4Aa9D7837CAf62653d097Ff85", "symbol": "USDC", "decimals": 6},
+				{"chainId": 1, "address": "not-an-address", "symbol": "BAD", "decimals": 18}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	tokens, err := FetchTokenList(server.URL, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(tokens) != 1 {
+		t.Fatalf("Expected 1 token for chain 1, got %d", len(tokens))
+	}
+	if tokens[0].Symbol != "USDC" || tokens[0].Decimals != 6 || tokens[0].CoinGeckoID != "usd-coin" {
+		t.Errorf("Unexpected token: %+v", tokens[0])
+	}
+
+	if _, err := FetchTokenList(server.URL, 0); err == nil {
+		t.Error("Expected error for chain without chain_id")
+	}
+
+	defer func(n int64) { MaxTokenListBytes = n }(MaxTokenListBytes)
+	MaxTokenListBytes = 64
+	if _, err := FetchTokenList(server.URL, 1); err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Errorf("Expected an oversized list to be rejected, got %v", err)
+	}
+}
+
+func TestFetchTransactionsRange(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	target := common.HexToAddress("0xAb5801a7D398351b8bE11C439e05C5B3259aeC9B")
+	signedTx, err := types.SignTx(types.NewTransaction(1, target, big.NewInt(1e18), 21000, big.NewInt(1e9), nil), types.NewLondonSigner(big.NewInt(1)), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txJSON, _ := signedTx.MarshalJSON()
+
+	type rpcReq struct {
+		ID     int           `json:"id"`
+		Method string        `json:"method"`
+		Params []interface{} `json:"params"`
+	}
+	var requested []string
+	respond := func(req rpcReq) map[string]interface{} {
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "eth_chainId":
+			resp["result"] = "0x1"
+		case "eth_blockNumber":
+			resp["result"] = "0x30"
+		case "eth_getBlockByNumber":
+			num, _ := req.Params[0].(string)
+			requested = append(requested, num)
+			txs := []json.RawMessage{}
+			if num == "0x25" {
+				txs = append(txs, txJSON)
+			}
+			resp["result"] = map[string]interface{}{"number": num, "transactions": txs}
+		}
+		return resp
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+			var reqs []rpcReq
+			_ = json.Unmarshal(body, &reqs)
+			var resps []map[string]interface{}
+			for _, req := range reqs {
+				resps = append(resps, respond(req))
+			}
+			_ = json.NewEncoder(w).Encode(resps)
+			return
+		}
+		var req rpcReq
+		_ = json.Unmarshal(body, &req)
+		_ = json.NewEncoder(w).Encode(respond(req))
+	}))
+	defer server.Close()
+
+	txs, err := FetchTransactionsRange(target.Hex(), []string{server.URL}, 0x10, 0, 10, 4, DefaultRangeScanTimeout)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(txs) != 1 || txs[0].Hash != signedTx.Hash().Hex() || txs[0].BlockNumber != 0x25 {
+		t.Errorf("Unexpected transactions: %+v", txs)
+	}
+	if len(requested) != 0x30-0x10+1 {
+		t.Errorf("Expected blocks 0x10..0x30 to be scanned, got %d", len(requested))
+	}
+
+	original := MaxRangeScanBlocks
+	MaxRangeScanBlocks = 16
+	defer func() { MaxRangeScanBlocks = original }()
+	if _, err := FetchTransactionsRange(target.Hex(), []string{server.URL}, 0x10, 0, 10, 4, DefaultRangeScanTimeout); err == nil {
+		t.Error("Expected error when the range exceeds MaxRangeScanBlocks")
+	}
+}
+
+func TestSearchCoinGecko_Cached(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/search" || r.URL.Query().Get("query") != "usdc search" {
+			t.Errorf("Unexpected request: %s", r.URL)
+		}
+		_, _ = w.Write([]byte(`{"coins":[
+			{"id":"usd-coin","name":"USDC","symbol":"USDC","market_cap_rank":7},
+			{"id":"bridged-usdc","name":"Bridged USDC","symbol":"USDC"}
+		]}`))
+	}))
+	defer server.Close()
+
+	originalURL := CoinGeckoBaseURL
+	CoinGeckoBaseURL = server.URL
+	defer func() { CoinGeckoBaseURL = originalURL }()
+
+	for i := 0; i < 2; i++ {
+		coins, err := SearchCoinGecko("USDC search")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(coins) != 2 || coins[0].ID != "usd-coin" || coins[0].MarketCapRank != 7 {
+			t.Fatalf("Unexpected results: %+v", coins)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected the second search to be cached, got %d requests", requests)
+	}
+}
+
+func TestFetchNativeBalance_FallsBackToNextRPC(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int    `json:"id"`
+			Method string `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		if req.Method == "eth_getBalance" {
+			resp["result"] = "0xde0b6b3a7640000" // 1 ETH
+		} else {
+			resp["error"] = map[string]interface{}{"code": -32601, "message": "method not found"}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	wei, err := FetchNativeBalance([]string{down.URL, server.URL}, "0x1234567890123456789012345678901234567890", DefaultRequestTimeout)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if wei.String() != "1000000000000000000" {
+		t.Errorf("Expected 1 ETH in wei, got %s", wei)
+	}
+	if _, err := FetchNativeBalance([]string{down.URL}, "0x1234567890123456789012345678901234567890", DefaultRequestTimeout); err == nil {
+		t.Error("Expected an error when every RPC fails")
+	}
+}
+
+func TestFetchChainlinkPrice(t *testing.T) {
+	word := func(v int64) string { return common.Bytes2Hex(common.LeftPadBytes(big.NewInt(v).Bytes(), 32)) }
+	updatedAt := time.Now().Add(-time.Hour).Unix()
+	// ETH/USD at 1850.50 with 8 decimals.
+	round := "0x" + word(7) + word(185050000000) + word(updatedAt) + word(updatedAt) + word(7)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int           `json:"id"`
+			Method string        `json:"method"`
+			Params []interface{} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		callArg, _ := req.Params[0].(map[string]interface{})
+		input, _ := callArg["input"].(string)
+		switch input {
+		case "0x313ce567": // decimals()
+			resp["result"] = "0x" + word(8)
+		case "0xfeaf968c": // latestRoundData()
+			resp["result"] = round
+		default:
+			resp["error"] = map[string]interface{}{"code": 3, "message": "execution reverted"}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	price, err := FetchChainlinkPrice(server.URL, "0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419", DefaultRequestTimeout)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if price != 1850.5 {
+		t.Errorf("Expected 1850.5, got %v", price)
+	}
+
+	if _, err := FetchChainlinkPrice(server.URL, "not-an-address", DefaultRequestTimeout); err == nil {
+		t.Error("Expected an error for an invalid feed address")
+	}
+}
+
+func TestDecodeLatestRoundData_Rejects(t *testing.T) {
+	word := func(v int64) []byte { return common.LeftPadBytes(big.NewInt(v).Bytes(), 32) }
+	now := time.Now()
+	decimals := word(8)
+	build := func(answer []byte, updatedAt int64) []byte {
+		res := append(word(1), answer...)
+		res = append(res, word(updatedAt)...)
+		res = append(res, word(updatedAt)...)
+		return append(res, word(1)...)
+	}
+	negative := common.LeftPadBytes(nil, 32)
+	for i := range negative {
+		negative[i] = 0xff
+	}
+
+	if _, err := decodeLatestRoundData(build(word(0), now.Unix()), decimals, now); err == nil {
+		t.Error("Expected a zero answer to be rejected")
+	}
+	if _, err := decodeLatestRoundData(build(negative, now.Unix()), decimals, now); err == nil {
+		t.Error("Expected a negative answer to be rejected")
+	}
+	if _, err := decodeLatestRoundData(build(word(100), now.Add(-48*time.Hour).Unix()), decimals, now); err == nil {
+		t.Error("Expected a stale answer to be rejected")
+	}
+	if _, err := decodeLatestRoundData(word(1), decimals, now); err == nil {
+		t.Error("Expected a short result to be rejected")
+	}
+}
+
+func TestFetchTokenMetadata_RetriesAtLatestBlockAndNextRPC(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	word := func(v int64) string { return common.Bytes2Hex(common.LeftPadBytes(big.NewInt(v).Bytes(), 32)) }
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int           `json:"id"`
+			Method string        `json:"method"`
+			Params []interface{} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "eth_blockNumber":
+			resp["result"] = "0x10"
+		case "eth_call":
+			callArg, _ := req.Params[0].(map[string]interface{})
+			input, _ := callArg["input"].(string)
+			switch {
+			case req.Params[1] == "latest":
+				resp["result"] = "0x" // Stale view of the proxy: no data
+			case input == "0x95d89b41": // symbol()
+				resp["result"] = "0x" + word(32) + word(3) + common.Bytes2Hex(common.RightPadBytes([]byte("PRX"), 32))
+			case input == "0x313ce567": // decimals()
+				resp["result"] = "0x" + word(6)
+			default:
+				resp["error"] = map[string]interface{}{"code": 3, "message": "execution reverted"}
+			}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	meta, err := FetchTokenMetadata([]string{down.URL, server.URL}, "0x1234567890123456789012345678901234567890", DefaultRequestTimeout)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if meta.Symbol != "PRX" || meta.Decimals != 6 {
+		t.Errorf("Expected PRX with 6 decimals, got %s with %d", meta.Symbol, meta.Decimals)
+	}
+	if meta.RPCURL != server.URL {
+		t.Errorf("Expected the answering RPC %s, got %s", server.URL, meta.RPCURL)
+	}
+
+	_, err = FetchTokenMetadata([]string{down.URL}, "0x1234567890123456789012345678901234567890", DefaultRequestTimeout)
+	if err == nil || !strings.Contains(err.Error(), hostOf(down.URL)) {
+		t.Errorf("Expected the error to name the failing RPC, got %v", err)
+	}
+}
+
+func TestIsContract(t *testing.T) {
+	codes := map[string]string{
+		"0x1111111111111111111111111111111111111111": "0x6080604052",
+		"0x2222222222222222222222222222222222222222": "0x",
+		"0x3333333333333333333333333333333333333333": "0xef0100" + strings.Repeat("ab", 20), // EIP-7702 delegation
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int           `json:"id"`
+			Method string        `json:"method"`
+			Params []interface{} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		addr, _ := req.Params[0].(string)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": codes[strings.ToLower(addr)]})
+	}))
+	defer server.Close()
+
+	for addr, want := range map[string]bool{
+		"0x1111111111111111111111111111111111111111": true,
+		"0x2222222222222222222222222222222222222222": false,
+		"0x3333333333333333333333333333333333333333": false,
+	} {
+		got, err := IsContract(server.URL, addr, DefaultRequestTimeout)
+		if err != nil {
+			t.Fatalf("IsContract(%s) failed: %v", addr, err)
+		}
+		if got != want {
+			t.Errorf("IsContract(%s) = %v; want %v", addr, got, want)
+		}
+	}
+}
+
+func TestProbeRPC(t *testing.T) {
+	syncing := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int    `json:"id"`
+			Method string `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "eth_chainId":
+			resp["result"] = "0x1"
+		case "eth_blockNumber":
+			resp["result"] = "0x1000"
+		case "eth_syncing":
+			resp["result"] = false
+			if syncing {
+				resp["result"] = map[string]interface{}{"startingBlock": "0x0", "currentBlock": "0x1000", "highestBlock": "0x2000"}
+			}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	res := ProbeRPC(server.URL, DefaultRequestTimeout)
+	if res.Err != nil || res.SyncErr != nil {
+		t.Fatalf("Unexpected errors: %v, %v", res.Err, res.SyncErr)
+	}
+	if res.ChainID != 1 || res.BlockNumber != 0x1000 || res.Syncing {
+		t.Errorf("Unexpected probe result: %+v", res)
+	}
+
+	syncing = true
+	res = ProbeRPC(server.URL, DefaultRequestTimeout)
+	if !res.Syncing || res.CurrentBlock != 0x1000 || res.HighestBlock != 0x2000 {
+		t.Errorf("Expected sync progress, got %+v", res)
+	}
+
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	if res := ProbeRPC(down.URL, DefaultRequestTimeout); res.Err == nil {
+		t.Error("Expected an error from an unavailable RPC")
+	}
+	defaultPool.mu.Lock()
+	_, cached := defaultPool.clients[down.URL]
+	defaultPool.mu.Unlock()
+	if !cached {
+		t.Error("A failed probe must not evict the RPC's client from the pool")
+	}
+	if _, err := FetchChainID(down.URL, DefaultRequestTimeout); err == nil {
+		t.Error("Expected FetchChainID to fail like the probe")
+	}
+	if id, err := FetchChainID(server.URL, DefaultRequestTimeout); err != nil || id.Int64() != 1 {
+		t.Errorf("FetchChainID = %v, %v; want 1", id, err)
+	}
+}
@@ -1,47 +1,204 @@
 package server
 
 import (
+	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
+	"time"
 
+	"evmbal/pkg/config"
+	"evmbal/pkg/models"
+	"evmbal/pkg/portfolio"
+	"evmbal/pkg/utils"
 	"evmbal/pkg/watcher"
 
 	"github.com/gorilla/websocket"
 )
 
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool { return true },
+// Websocket client tuning.
+const (
+	// clientSendBuffer is how many messages may queue for a client before new ones are dropped.
+	clientSendBuffer = 64
+	// clientWriteTimeout bounds a single write so a stalled client's writer gives up.
+	clientWriteTimeout = 10 * time.Second
+)
+
+// client is a websocket connection with its own send queue, drained by writePump so
+// broadcasts never block on the network.
+type client struct {
+	conn   *websocket.Conn
+	send   chan interface{}
+	events map[watcher.EventType]bool // Event types to forward; nil forwards all. Guarded by Server.mu
+}
+
+// subscribeMessage lets a client change its event filter after connecting, e.g.
+// {"type":"subscribe","events":["chain_data_updated"]}. An empty list forwards all events.
+type subscribeMessage struct {
+	Type   string   `json:"type"`
+	Events []string `json:"events"`
+}
+
+// parseEventFilter turns event type names into a filter set, or nil for no filter.
+func parseEventFilter(names []string) map[watcher.EventType]bool {
+	var filter map[watcher.EventType]bool
+	for _, n := range names {
+		n = strings.TrimSpace(n)
+		if n == "" {
+			continue
+		}
+		if filter == nil {
+			filter = make(map[watcher.EventType]bool)
+		}
+		filter[watcher.EventType(n)] = true
+	}
+	return filter
+}
+
+// writePump writes queued messages until send is closed or a write fails.
+func (c *client) writePump() {
+	for msg := range c.send {
+		_ = c.conn.SetWriteDeadline(time.Now().Add(clientWriteTimeout))
+		if err := c.conn.WriteJSON(msg); err != nil {
+			// Closing the connection ends the read loop in handleWS, which unregisters us.
+			_ = c.conn.Close()
+			return
+		}
+	}
 }
 
 type Server struct {
-	watcher *watcher.Watcher
-	clients map[*websocket.Conn]bool
-	mu      sync.Mutex
-	mux     *http.ServeMux
+	watcher    *watcher.Watcher
+	clients    map[*client]bool
+	mu         sync.Mutex
+	mux        *http.ServeMux
+	upgrader   websocket.Upgrader
+	httpServer *http.Server
+	sub        watcher.Subscriber
+
+	username       string
+	password       string
+	allowedOrigins []string
 }
 
 func NewServer(w *watcher.Watcher) *Server {
 	s := &Server{
 		watcher: w,
-		clients: make(map[*websocket.Conn]bool),
+		clients: make(map[*client]bool),
 		mux:     http.NewServeMux(),
 	}
+	s.upgrader = websocket.Upgrader{CheckOrigin: s.checkOrigin}
 	s.routes()
 	return s
 }
 
+// SetBasicAuth requires HTTP basic auth on the API and websocket endpoints.
+// An empty username disables auth.
+func (s *Server) SetBasicAuth(username, password string) {
+	s.username = username
+	s.password = password
+}
+
+// SetAllowedOrigins sets the browser origins (e.g. "https://dash.example") allowed
+// to open a websocket. "*" allows any origin. With no origins configured only
+// same-origin pages may connect.
+func (s *Server) SetAllowedOrigins(origins []string) {
+	s.allowedOrigins = origins
+}
+
 func (s *Server) routes() {
-	s.mux.HandleFunc("/api/status", s.handleStatus)
-	s.mux.HandleFunc("/ws", s.handleWS)
+	s.mux.HandleFunc("/api/status", s.requireAuth(s.handleStatus))
+	s.mux.HandleFunc("GET /api/accounts/{address}", s.requireAuth(s.handleAccount))
+	s.mux.HandleFunc("/ws", s.requireAuth(s.handleWS))
+	s.mux.HandleFunc("/healthz", s.handleHealthz)
 }
 
+// requireAuth rejects requests without valid basic auth credentials when auth is configured.
+func (s *Server) requireAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.username != "" {
+			user, pass, ok := r.BasicAuth()
+			userOK := subtle.ConstantTimeCompare([]byte(user), []byte(s.username)) == 1
+			passOK := subtle.ConstantTimeCompare([]byte(pass), []byte(s.password)) == 1
+			if !ok || !userOK || !passOK {
+				w.Header().Set("WWW-Authenticate", `Basic realm="evmbal"`)
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+// checkOrigin allows non-browser clients (no Origin header), same-origin pages and
+// origins on the allowlist.
+func (s *Server) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range s.allowedOrigins {
+		if allowed == "*" || strings.EqualFold(strings.TrimRight(allowed, "/"), origin) {
+			return true
+		}
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// Start serves the API until Shutdown is called, which makes it return nil.
 func (s *Server) Start(port int) error {
-	go s.listenToWatcher()
+	s.mu.Lock()
+	s.sub = s.watcher.Subscribe()
+	s.httpServer = &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: s.mux}
+	srv, sub := s.httpServer, s.sub
+	s.mu.Unlock()
+	go s.listenToWatcher(sub)
 
 	fmt.Printf("API Server listening on :%d\n", port)
-	return http.ListenAndServe(fmt.Sprintf(":%d", port), s.mux)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown stops accepting connections, waits for in-flight requests until ctx is
+// done, and closes open websockets.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	srv, sub := s.httpServer, s.sub
+	s.sub = nil
+	// handleWS unregisters each client once its read loop sees the closed connection.
+	for c := range s.clients {
+		_ = c.conn.Close()
+	}
+	s.mu.Unlock()
+
+	if sub != nil {
+		s.watcher.Unsubscribe(sub)
+	}
+	if srv == nil {
+		return nil
+	}
+	return srv.Shutdown(ctx)
+}
+
+// handleHealthz is an unauthenticated liveness probe. It reports how long ago the
+// watcher last fetched chain data successfully.
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	data := map[string]interface{}{"status": "ok"}
+	if last := s.watcher.LastSuccessfulFetch(); !last.IsZero() {
+		data["last_fetch"] = last.UTC().Format(time.RFC3339)
+		data["seconds_since_last_fetch"] = int(time.Since(last).Seconds())
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(data)
 }
 
 func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
@@ -52,57 +209,161 @@ func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(data)
 }
 
+// tokenSummary is one token balance in an accountSummary.
+type tokenSummary struct {
+	Symbol   string  `json:"symbol"`
+	Balance  string  `json:"balance"`
+	ValueUSD float64 `json:"value_usd"`
+}
+
+// chainSummary is an account's holdings on one chain.
+type chainSummary struct {
+	Name        string         `json:"name"`
+	Symbol      string         `json:"symbol"`
+	Balance     string         `json:"balance,omitempty"`
+	ValueUSD    float64        `json:"value_usd"`
+	Tokens      []tokenSummary `json:"tokens,omitempty"`
+	SubtotalUSD float64        `json:"subtotal_usd"`
+	Error       string         `json:"error,omitempty"`
+}
+
+// accountSummary is the /api/accounts/{address} response.
+type accountSummary struct {
+	Address      string               `json:"address"`
+	Name         string               `json:"name"`
+	Chains       []chainSummary       `json:"chains"`
+	TotalUSD     float64              `json:"total_usd"`
+	Transactions []models.Transaction `json:"transactions"`
+}
+
+// usdFloat converts a portfolio value to float64, treating nil (unpriced) as
+// zero. Values beyond float64 are capped so the response still encodes.
+func usdFloat(v *big.Float) float64 {
+	f, _ := utils.Float64(v)
+	return f
+}
+
+// summarizeAccount lists an account's balances per chain with their USD values.
+func summarizeAccount(acc *models.Account, chains []config.ChainConfig, prices map[string]float64) accountSummary {
+	sum := accountSummary{
+		Address:      acc.Address,
+		Name:         acc.Name,
+		Chains:       []chainSummary{},
+		Transactions: acc.Transactions,
+	}
+	if sum.Transactions == nil {
+		sum.Transactions = []models.Transaction{}
+	}
+	total := portfolio.NewTotal()
+	values := portfolio.ChainValues(acc, chains, prices)
+	for i, chain := range chains {
+		cv := values[i]
+		cs := chainSummary{Name: chain.Name, Symbol: chain.Symbol, SubtotalUSD: usdFloat(cv.Total)}
+		if err := acc.Errors[chain.Name]; err != nil {
+			cs.Error = err.Error()
+		}
+		if bal, ok := acc.Balances[chain.Name]; ok && bal != nil {
+			cs.Balance = bal.Text('f', -1)
+			cs.ValueUSD = usdFloat(cv.Native)
+		}
+		tokens := acc.TokenBalances[chain.Name]
+		for _, t := range chain.Tokens {
+			bal, ok := tokens[t.Symbol]
+			if !ok || bal == nil {
+				continue
+			}
+			cs.Tokens = append(cs.Tokens, tokenSummary{Symbol: t.Symbol, Balance: bal.Text('f', -1), ValueUSD: usdFloat(cv.Tokens[t.Symbol])})
+		}
+		if cs.Balance == "" && len(cs.Tokens) == 0 && cs.Error == "" {
+			continue
+		}
+		total.Add(total, cv.Total)
+		sum.Chains = append(sum.Chains, cs)
+	}
+	sum.TotalUSD = usdFloat(total)
+	return sum
+}
+
+// handleAccount returns one account's balances, USD values and recent transactions.
+func (s *Server) handleAccount(w http.ResponseWriter, r *http.Request) {
+	address := r.PathValue("address")
+	for _, acc := range s.watcher.GetAccounts() {
+		if strings.EqualFold(acc.Address, address) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(summarizeAccount(acc, s.watcher.GetChains(), s.watcher.GetPrices()))
+			return
+		}
+	}
+	http.Error(w, "account not found", http.StatusNotFound)
+}
+
 func (s *Server) handleWS(w http.ResponseWriter, r *http.Request) {
-	conn, err := upgrader.Upgrade(w, r, nil)
+	conn, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		return
 	}
 	defer func() { _ = conn.Close() }()
 
-	s.mu.Lock()
-	s.clients[conn] = true
-	s.mu.Unlock()
-
-	defer func() {
-		s.mu.Lock()
-		delete(s.clients, conn)
-		s.mu.Unlock()
-	}()
+	c := &client{conn: conn, send: make(chan interface{}, clientSendBuffer)}
+	if events := r.URL.Query().Get("events"); events != "" {
+		c.events = parseEventFilter(strings.Split(events, ","))
+	}
 
-	// Send initial state
-	initialData := map[string]interface{}{
+	// Queue initial state before registering so it is always the first message
+	c.send <- map[string]interface{}{
 		"type": "initial",
 		"data": map[string]interface{}{
 			"accounts": s.watcher.GetAccounts(),
 			"prices":   s.watcher.GetPrices(),
 		},
 	}
-	_ = conn.WriteJSON(initialData)
+	go c.writePump()
+
+	s.mu.Lock()
+	s.clients[c] = true
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, c)
+		close(c.send)
+		s.mu.Unlock()
+	}()
 
 	for {
-		if _, _, err := conn.ReadMessage(); err != nil {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
 			break
 		}
+		var msg subscribeMessage
+		if json.Unmarshal(data, &msg) == nil && msg.Type == "subscribe" {
+			s.mu.Lock()
+			c.events = parseEventFilter(msg.Events)
+			s.mu.Unlock()
+		}
 	}
 }
 
-func (s *Server) listenToWatcher() {
-	sub := s.watcher.Subscribe()
-	defer s.watcher.Unsubscribe(sub)
-
+// listenToWatcher forwards events until Shutdown unsubscribes, closing sub.
+func (s *Server) listenToWatcher(sub watcher.Subscriber) {
 	for event := range sub {
 		s.broadcast(event)
 	}
 }
 
+// broadcast queues event for every client subscribed to its type without blocking.
+// A client whose queue is full misses the event, like a slow watcher subscriber.
 func (s *Server) broadcast(event watcher.Event) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	for client := range s.clients {
-		if err := client.WriteJSON(event); err != nil {
-			_ = client.Close()
-			delete(s.clients, client)
+	for c := range s.clients {
+		if c.events != nil && !c.events[event.Type] {
+			continue
+		}
+		select {
+		case c.send <- event:
+		default:
 		}
 	}
 }
@@ -1,13 +1,18 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"evmbal/pkg/config"
+	"evmbal/pkg/models"
 	"evmbal/pkg/watcher"
 
 	"github.com/gorilla/websocket"
@@ -50,3 +55,240 @@ func TestHandleWS(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "initial", msg["type"])
 }
+
+func TestHandleStatus_BasicAuth(t *testing.T) {
+	w := watcher.NewWatcher(nil, nil, config.GlobalConfig{}, "")
+	s := NewServer(w)
+	s.SetBasicAuth("admin", "secret")
+
+	req, _ := http.NewRequest("GET", "/api/status", nil)
+	rr := httptest.NewRecorder()
+	s.mux.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	req, _ = http.NewRequest("GET", "/api/status", nil)
+	req.SetBasicAuth("admin", "wrong")
+	rr = httptest.NewRecorder()
+	s.mux.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	req, _ = http.NewRequest("GET", "/api/status", nil)
+	req.SetBasicAuth("admin", "secret")
+	rr = httptest.NewRecorder()
+	s.mux.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestCheckOrigin(t *testing.T) {
+	s := NewServer(watcher.NewWatcher(nil, nil, config.GlobalConfig{}, ""))
+	req := func(origin string) *http.Request {
+		r, _ := http.NewRequest("GET", "http://localhost:8080/ws", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		return r
+	}
+
+	assert.True(t, s.checkOrigin(req("")), "non-browser clients send no origin")
+	assert.True(t, s.checkOrigin(req("http://localhost:8080")), "same origin")
+	assert.False(t, s.checkOrigin(req("https://evil.example")))
+
+	s.SetAllowedOrigins([]string{"https://dash.example/"})
+	assert.True(t, s.checkOrigin(req("https://dash.example")))
+	assert.False(t, s.checkOrigin(req("https://evil.example")))
+}
+
+func TestHandleHealthz(t *testing.T) {
+	w := watcher.NewWatcher(nil, nil, config.GlobalConfig{}, "")
+	s := NewServer(w)
+	s.SetBasicAuth("admin", "secret")
+
+	req, _ := http.NewRequest("GET", "/healthz", nil)
+	rr := httptest.NewRecorder()
+	s.mux.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code, "liveness probe must not require auth")
+	var resp map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, "ok", resp["status"])
+	assert.NotContains(t, resp, "last_fetch", "no fetch has succeeded yet")
+}
+
+func TestShutdown(t *testing.T) {
+	w := watcher.NewWatcher(nil, nil, config.GlobalConfig{}, "")
+	s := NewServer(w)
+
+	done := make(chan error, 1)
+	go func() { done <- s.Start(0) }()
+	assert.Eventually(t, func() bool { return w.SubscriberCount() == 1 }, time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, s.Shutdown(context.Background()))
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Start did not return after Shutdown")
+	}
+	assert.Equal(t, 0, w.SubscriberCount())
+}
+
+func TestBroadcast_SlowClientDoesNotBlock(t *testing.T) {
+	w := watcher.NewWatcher(nil, nil, config.GlobalConfig{}, "")
+	s := NewServer(w)
+	server := httptest.NewServer(s.mux)
+	defer server.Close()
+	u := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+
+	// Never reads after connecting
+	slow, _, err := websocket.DefaultDialer.Dial(u, nil)
+	assert.NoError(t, err)
+	defer func() { _ = slow.Close() }()
+
+	fast, _, err := websocket.DefaultDialer.Dial(u, nil)
+	assert.NoError(t, err)
+	defer func() { _ = fast.Close() }()
+
+	received := make(chan string, 1000)
+	go func() {
+		for {
+			var msg map[string]interface{}
+			if err := fast.ReadJSON(&msg); err != nil {
+				return
+			}
+			if typ, ok := msg["Type"].(string); ok {
+				received <- typ
+			}
+		}
+	}()
+
+	assert.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.clients) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	// Enough data to fill the slow client's socket buffers several times over
+	payload := strings.Repeat("x", 64*1024)
+	start := time.Now()
+	for i := 0; i < 200; i++ {
+		s.broadcast(watcher.Event{Type: watcher.EventChainDataUpdated, Data: payload})
+	}
+	assert.Less(t, time.Since(start), time.Second, "broadcast must not block on a slow client")
+
+	assert.Eventually(t, func() bool {
+		s.broadcast(watcher.Event{Type: watcher.EventGasPriceUpdated})
+		for {
+			select {
+			case typ := <-received:
+				if typ == string(watcher.EventGasPriceUpdated) {
+					return true
+				}
+			default:
+				return false
+			}
+		}
+	}, 5*time.Second, 50*time.Millisecond, "fast client should keep receiving events")
+}
+
+func TestBroadcast_EventFilter(t *testing.T) {
+	w := watcher.NewWatcher(nil, nil, config.GlobalConfig{}, "")
+	s := NewServer(w)
+	server := httptest.NewServer(s.mux)
+	defer server.Close()
+	u := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+
+	ws, _, err := websocket.DefaultDialer.Dial(u+"?events=chain_data_updated", nil)
+	assert.NoError(t, err)
+	defer func() { _ = ws.Close() }()
+
+	var msg map[string]interface{}
+	assert.NoError(t, ws.ReadJSON(&msg))
+	assert.Equal(t, "initial", msg["type"])
+
+	assert.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.clients) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	s.broadcast(watcher.Event{Type: watcher.EventGasPriceUpdated})
+	s.broadcast(watcher.Event{Type: watcher.EventChainDataUpdated})
+	assert.NoError(t, ws.ReadJSON(&msg))
+	assert.Equal(t, string(watcher.EventChainDataUpdated), msg["Type"], "filtered events are skipped")
+
+	// Switch the filter with a subscribe message
+	assert.NoError(t, ws.WriteJSON(map[string]interface{}{"type": "subscribe", "events": []string{"gas_price_updated"}}))
+	assert.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for c := range s.clients {
+			return c.events[watcher.EventGasPriceUpdated]
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+
+	s.broadcast(watcher.Event{Type: watcher.EventChainDataUpdated})
+	s.broadcast(watcher.Event{Type: watcher.EventGasPriceUpdated})
+	assert.NoError(t, ws.ReadJSON(&msg))
+	assert.Equal(t, string(watcher.EventGasPriceUpdated), msg["Type"])
+}
+
+func TestHandleAccount(t *testing.T) {
+	addr := "0x1111111111111111111111111111111111111111"
+	w := watcher.NewWatcher([]config.AddressConfig{{Address: addr, Name: "Main"}}, nil, config.GlobalConfig{}, "")
+	s := NewServer(w)
+
+	req, _ := http.NewRequest("GET", "/api/accounts/"+strings.ToUpper(addr[2:]), nil)
+	rr := httptest.NewRecorder()
+	s.mux.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	req, _ = http.NewRequest("GET", "/api/accounts/0x"+strings.ToUpper(addr[2:]), nil)
+	rr = httptest.NewRecorder()
+	s.mux.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp accountSummary
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, addr, resp.Address)
+	assert.Equal(t, "Main", resp.Name)
+	assert.Empty(t, resp.Chains)
+}
+
+func TestSummarizeAccount(t *testing.T) {
+	acc := &models.Account{
+		Address:  "0x1",
+		Balances: map[string]*big.Float{"Ethereum": big.NewFloat(2), "Base": big.NewFloat(1)},
+		TokenBalances: map[string]map[string]*big.Float{
+			"Ethereum": {"USDC": big.NewFloat(100), "FOO": big.NewFloat(5)},
+		},
+		Errors:       map[string]error{"Polygon": errors.New("rpc down")},
+		Transactions: []models.Transaction{{Hash: "0xabc"}},
+	}
+	chains := []config.ChainConfig{
+		{Name: "Ethereum", Symbol: "ETH", CoinGeckoID: "ethereum", Tokens: []config.TokenConfig{
+			{Symbol: "USDC", CoinGeckoID: "usd-coin"},
+			{Symbol: "FOO"}, // No price
+		}},
+		{Name: "Base", Symbol: "ETH", CoinGeckoID: "ethereum"},
+		{Name: "Polygon", Symbol: "POL", CoinGeckoID: "polygon-ecosystem-token"},
+		{Name: "Empty", Symbol: "X"},
+	}
+	prices := map[string]float64{"ethereum": 1000, "usd-coin": 1}
+
+	sum := summarizeAccount(acc, chains, prices)
+	assert.Len(t, sum.Chains, 3, "chains without balances or errors are omitted")
+
+	eth := sum.Chains[0]
+	assert.Equal(t, "2", eth.Balance)
+	assert.Equal(t, 2000.0, eth.ValueUSD)
+	assert.Len(t, eth.Tokens, 2)
+	assert.Equal(t, 100.0, eth.Tokens[0].ValueUSD)
+	assert.Equal(t, 0.0, eth.Tokens[1].ValueUSD)
+	assert.Equal(t, 2100.0, eth.SubtotalUSD)
+
+	assert.Equal(t, 1000.0, sum.Chains[1].SubtotalUSD)
+	assert.Equal(t, "rpc down", sum.Chains[2].Error)
+	assert.Equal(t, 3100.0, sum.TotalUSD)
+	assert.Len(t, sum.Transactions, 1)
+}
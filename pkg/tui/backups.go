@@ -0,0 +1,247 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"evmbal/pkg/config"
+	"evmbal/pkg/models"
+	"evmbal/pkg/utils"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxBackupListRows is how many backups the restore list shows at once.
+const maxBackupListRows = 10
+
+// updateRestoringBackup handles the backup list opened with B. Enter asks for
+// confirmation before the selected backup is restored.
+func (m model) updateRestoringBackup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.confirmingRestore {
+		return m.updateConfirmingRestore(msg)
+	}
+	switch msg.String() {
+	case "q", "esc":
+		m.restoringBackup = false
+	case "up", "k":
+		if m.backupListIdx > 0 {
+			m.backupListIdx--
+		}
+	case "down", "j":
+		if m.backupListIdx < len(m.backups)-1 {
+			m.backupListIdx++
+		}
+	case "enter":
+		if len(m.backups) > 0 {
+			m.restoreDiff, m.restoreDiffErr = diffBackup(m.configPath, m.backups[m.backupListIdx].Path)
+			m.confirmingRestore = true
+		}
+	case "d":
+		if len(m.backups) == 0 {
+			return m, nil
+		}
+		path := m.backups[m.backupListIdx].Path
+		if err := config.DeleteBackup(m.configPath, path); err != nil {
+			m.statusMessage = fmt.Sprintf("Delete failed: %v", err)
+		} else {
+			m.statusMessage = fmt.Sprintf("Deleted %s", filepath.Base(path))
+		}
+		if err := m.loadBackupList(); err != nil {
+			m.statusMessage = fmt.Sprintf("Failed to list backups: %v", err)
+		}
+		if m.backupListIdx >= len(m.backups) && m.backupListIdx > 0 {
+			m.backupListIdx = len(m.backups) - 1
+		}
+		return m, clearStatusAfter(2 * time.Second)
+	}
+	return m, nil
+}
+
+func (m model) updateConfirmingRestore(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y", "enter":
+		m.confirmingRestore = false
+		m.restoringBackup = false
+		if err := config.RestoreBackup(m.configPath, m.backups[m.backupListIdx].Path); err != nil {
+			m.statusMessage = fmt.Sprintf("Restore failed: %v", err)
+		} else if err := m.reloadConfig(); err != nil {
+			m.statusMessage = fmt.Sprintf("Backup restored but could not be loaded: %v", err)
+		} else {
+			m.statusMessage = "Backup restored"
+		}
+		return m, clearStatusAfter(3 * time.Second)
+	case "n", "N", "q", "esc":
+		m.confirmingRestore = false
+	}
+	return m, nil
+}
+
+// diffBackup compares the config at configPath with the backup that would
+// replace it.
+func diffBackup(configPath, backupPath string) (config.ConfigDiff, error) {
+	curAddrs, curChains, _, _, err := config.LoadConfigFromFile(configPath)
+	if err != nil {
+		return config.ConfigDiff{}, fmt.Errorf("current config: %w", err)
+	}
+	bakAddrs, bakChains, _, _, err := config.LoadConfigFromFile(backupPath)
+	if err != nil {
+		return config.ConfigDiff{}, fmt.Errorf("backup: %w", err)
+	}
+	return config.DiffConfigs(curAddrs, curChains, bakAddrs, bakChains), nil
+}
+
+// viewRestoreDiff summarises what restoring the selected backup would change.
+func (m model) viewRestoreDiff() string {
+	if m.restoreDiffErr != nil {
+		return errStyle.Render(fmt.Sprintf("Could not compare configs: %v", m.restoreDiffErr))
+	}
+	d := m.restoreDiff
+	if d.IsEmpty() {
+		return subtleStyle.Render("Same addresses, chains and tokens as the current config.")
+	}
+	row := func(label string, added, removed int) string {
+		line := fmt.Sprintf("%-10s %s  %s", label, infoStyle.Render(fmt.Sprintf("+%d", added)), errStyle.Render(fmt.Sprintf("-%d", removed)))
+		if added == 0 && removed == 0 {
+			line = subtleStyle.Render(fmt.Sprintf("%-10s no change", label))
+		}
+		return line
+	}
+	lines := []string{
+		row("Addresses", d.AddressesAdded, d.AddressesRemoved),
+		row("Chains", d.ChainsAdded, d.ChainsRemoved),
+		row("Tokens", d.TokensAdded, d.TokensRemoved),
+	}
+	if d.AddressesRemoved > 0 {
+		lines = append(lines, "", warnStyle.Render(fmt.Sprintf("%d address(es) in the current config will be lost.", d.AddressesRemoved)))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// loadBackupList refreshes the backup list, newest first.
+func (m *model) loadBackupList() error {
+	backups, err := config.ListBackups(m.configPath)
+	if err != nil {
+		return err
+	}
+	m.backups = backups
+	return nil
+}
+
+// reloadConfig applies the config file on disk to the model and watcher, e.g.
+// after a backup was restored. Fetched data is kept for addresses that remain.
+func (m *model) reloadConfig() error {
+	addrs, chains, activeChainIdx, globalCfg, err := config.LoadConfigFromFile(m.configPath)
+	if err != nil {
+		return err
+	}
+	if len(chains) == 0 {
+		return fmt.Errorf("config has no chains")
+	}
+
+	var accounts []*models.Account
+	for _, a := range addrs {
+		acc := accountFromConfig(a)
+		if acc == nil {
+			continue
+		}
+		if i := m.findAccount(acc.Address); i >= 0 {
+			prev := m.accounts[i]
+			prev.Name = acc.Name
+			prev.Note = acc.Note
+			prev.Baselines = acc.Baselines
+			prev.ExcludeFromTotal = acc.ExcludeFromTotal
+			acc = prev
+		}
+		accounts = append(accounts, acc)
+	}
+	for _, prev := range m.accounts {
+		found := false
+		for _, acc := range accounts {
+			if strings.EqualFold(acc.Address, prev.Address) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			m.watcher.RemoveAccount(prev.Address)
+		}
+	}
+	for _, acc := range accounts {
+		m.watcher.AddAccount(config.AddressConfig{Address: acc.Address, Name: acc.Name})
+	}
+
+	m.accounts = accounts
+	if m.activeIdx >= len(m.accounts) {
+		m.activeIdx = 0
+	}
+	m.chains = chains
+	m.activeChainIdx = activeChainIdx
+	m.config = globalCfg
+	_ = utils.SetNumberFormat(globalCfg.NumberFormat)
+	m.watcher.SetChains(chains)
+	m.watcher.Refresh()
+	return nil
+}
+
+func (m model) viewBackupList() string {
+	if m.confirmingRestore {
+		b := m.backups[m.backupListIdx]
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center,
+			boxStyle.Render(lipgloss.JoinVertical(lipgloss.Center,
+				titleStyle.Render("Confirm Restore"),
+				"\n",
+				fmt.Sprintf("Restore the backup from %s?", b.Timestamp.Format("2006-01-02 15:04:05")),
+				"Current configuration will be overwritten.",
+				"\n",
+				m.viewRestoreDiff(),
+				"\n",
+				subtleStyle.Render("(y) Yes • (n) No"),
+			)))
+	}
+
+	header := titleStyle.Render("Restore Backup")
+
+	// Keep the cursor within a window of maxBackupListRows
+	start := 0
+	if m.backupListIdx >= maxBackupListRows {
+		start = m.backupListIdx - maxBackupListRows + 1
+	}
+	end := start + maxBackupListRows
+	if end > len(m.backups) {
+		end = len(m.backups)
+	}
+
+	var rows []string
+	for i := start; i < end; i++ {
+		b := m.backups[i]
+		cursor := "  "
+		if i == m.backupListIdx {
+			cursor = "> "
+		}
+		rows = append(rows, fmt.Sprintf("%s%s  %8s", cursor, b.Timestamp.Format("2006-01-02 15:04:05"), formatSize(b.Size)))
+	}
+	if len(m.backups) > maxBackupListRows {
+		rows = append(rows, subtleStyle.Render(fmt.Sprintf("%d-%d of %d", start+1, end, len(m.backups))))
+	}
+	body := strings.Join(rows, "\n")
+	if len(rows) == 0 {
+		body = subtleStyle.Render("No backups found.")
+	}
+	content := boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, header, "\n", body))
+	footer := subtleStyle.Render("↑/↓: select • enter: restore • d: delete • q/esc: back")
+	if m.statusMessage != "" {
+		footer = lipgloss.JoinVertical(lipgloss.Center, infoStyle.Render(m.statusMessage), footer)
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, lipgloss.JoinVertical(lipgloss.Center, content, "\n", footer))
+}
+
+// formatSize renders a byte count for the backup list.
+func formatSize(n int64) string {
+	if n < 1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+	return fmt.Sprintf("%.1f KB", float64(n)/1024)
+}
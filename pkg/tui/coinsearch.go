@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"evmbal/pkg/rpc"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// coinSearchDelay is how long typing must pause before CoinGecko is searched.
+	coinSearchDelay = 400 * time.Millisecond
+	// maxCoinSuggestions is how many search results are offered.
+	maxCoinSuggestions = 5
+)
+
+// coinSearchTickMsg fires coinSearchDelay after a keystroke in a CoinGecko ID field.
+type coinSearchTickMsg struct{ query string }
+
+// coinSearchMsg carries CoinGecko search results for query.
+type coinSearchMsg struct {
+	query string
+	coins []rpc.CoinSuggestion
+	err   error
+}
+
+func searchCoinGeckoCmd(query string) tea.Cmd {
+	return func() tea.Msg {
+		coins, err := rpc.SearchCoinGecko(query)
+		return coinSearchMsg{query: query, coins: coins, err: err}
+	}
+}
+
+// coinGeckoInput returns the CoinGecko ID input of the add-chain or add-token
+// form if it has focus, or nil.
+func (m *model) coinGeckoInput() *textinput.Model {
+	switch {
+	case m.addingChain && m.chainInputs[2].Focused():
+		return &m.chainInputs[2]
+	case m.addingToken && m.tokenInputs[3].Focused():
+		return &m.tokenInputs[3]
+	}
+	return nil
+}
+
+// updateCoinSuggestions handles ctrl+n/ctrl+p, which fill the focused CoinGecko
+// ID input with the next or previous suggestion. It reports whether the key was used.
+func (m *model) updateCoinSuggestions(msg tea.KeyMsg) bool {
+	in := m.coinGeckoInput()
+	if in == nil || len(m.coinSuggestions) == 0 {
+		return false
+	}
+	switch msg.String() {
+	case "ctrl+n":
+		m.coinSuggestionIdx = (m.coinSuggestionIdx + 1) % len(m.coinSuggestions)
+	case "ctrl+p":
+		m.coinSuggestionIdx--
+		if m.coinSuggestionIdx < 0 {
+			m.coinSuggestionIdx = len(m.coinSuggestions) - 1
+		}
+	default:
+		return false
+	}
+	in.SetValue(m.coinSuggestions[m.coinSuggestionIdx].ID)
+	in.CursorEnd()
+	return true
+}
+
+// scheduleCoinSearch clears stale suggestions and, if a CoinGecko ID input has
+// focus, waits for typing to pause before searching for its value.
+func (m *model) scheduleCoinSearch() tea.Cmd {
+	in := m.coinGeckoInput()
+	if in == nil {
+		m.coinSuggestions = nil
+		return nil
+	}
+	query := strings.TrimSpace(in.Value())
+	if query == m.coinSuggestionsQuery {
+		return nil
+	}
+	m.coinSuggestions = nil
+	m.coinSuggestionsQuery = ""
+	if query == "" {
+		return nil
+	}
+	return tea.Tick(coinSearchDelay, func(time.Time) tea.Msg {
+		return coinSearchTickMsg{query: query}
+	})
+}
+
+// applyCoinSearch stores results if they still match what is typed.
+func (m *model) applyCoinSearch(msg coinSearchMsg) {
+	in := m.coinGeckoInput()
+	if in == nil || strings.TrimSpace(in.Value()) != msg.query || msg.err != nil {
+		return
+	}
+	m.coinSuggestions = msg.coins
+	if len(m.coinSuggestions) > maxCoinSuggestions {
+		m.coinSuggestions = m.coinSuggestions[:maxCoinSuggestions]
+	}
+	m.coinSuggestionsQuery = msg.query
+	m.coinSuggestionIdx = -1
+}
+
+// viewCoinSuggestions lists the suggestions below the focused CoinGecko ID input.
+func (m model) viewCoinSuggestions() string {
+	if m.coinGeckoInput() == nil || len(m.coinSuggestions) == 0 {
+		return ""
+	}
+	rows := []string{subtleStyle.Render("CoinGecko matches (ctrl+n/ctrl+p to pick):")}
+	for i, c := range m.coinSuggestions {
+		cursor := "  "
+		if i == m.coinSuggestionIdx {
+			cursor = "> "
+		}
+		rank := ""
+		if c.MarketCapRank > 0 {
+			rank = fmt.Sprintf(" #%d", c.MarketCapRank)
+		}
+		rows = append(rows, fmt.Sprintf("%s%-24s %s (%s)%s", cursor, c.ID, c.Name, strings.ToUpper(c.Symbol), rank))
+	}
+	return "\n" + strings.Join(rows, "\n")
+}
@@ -0,0 +1,168 @@
+package tui
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"evmbal/pkg/portfolio"
+	"evmbal/pkg/utils"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// portfolioSampleInterval is how often the portfolio total is recorded for
+	// the history graph and the dashboard sparkline.
+	portfolioSampleInterval = 30 * time.Second
+	// maxPortfolioHistory keeps 24 hours of samples.
+	maxPortfolioHistory = 2880
+)
+
+// bigGlyphs are three-row block figures used for the dashboard total.
+var bigGlyphs = map[rune][3]string{
+	'0': {"█▀█", "█ █", "▀▀▀"},
+	'1': {"▀█ ", " █ ", "▀▀▀"},
+	'2': {"▀▀█", "█▀▀", "▀▀▀"},
+	'3': {"▀▀█", " ▀█", "▀▀▀"},
+	'4': {"█ █", "▀▀█", "  ▀"},
+	'5': {"█▀▀", "▀▀█", "▀▀▀"},
+	'6': {"█▀▀", "█▀█", "▀▀▀"},
+	'7': {"▀▀█", "  █", "  ▀"},
+	'8': {"█▀█", "█▀█", "▀▀▀"},
+	'9': {"█▀█", "▀▀█", "▀▀▀"},
+	'.': {" ", " ", "▀"},
+	',': {" ", " ", "▞"},
+	'-': {"   ", "▀▀▀", "   "},
+	' ': {" ", " ", " "},
+	'$': {"▄█▀", "▀█▄", "▀▀ "},
+}
+
+// bigText renders s in bigGlyphs. Characters without a glyph, e.g. other
+// currency symbols, are drawn at normal size on the middle row.
+func bigText(s string) string {
+	var rows [3][]string
+	for _, r := range s {
+		g, ok := bigGlyphs[r]
+		if !ok {
+			pad := strings.Repeat(" ", lipgloss.Width(string(r)))
+			g = [3]string{pad, string(r), pad}
+		}
+		for i := range rows {
+			rows[i] = append(rows[i], g[i])
+		}
+	}
+	return strings.Join(rows[0], " ") + "\n" + strings.Join(rows[1], " ") + "\n" + strings.Join(rows[2], " ")
+}
+
+// sparkline draws the last width values as a row of block characters.
+func sparkline(values []float64, width int) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+	chars := []rune("▁▂▃▄▅▆▇█")
+	var sb strings.Builder
+	for _, v := range values {
+		idx := len(chars) / 2
+		if hi > lo {
+			idx = int((v - lo) / (hi - lo) * float64(len(chars)-1))
+		}
+		sb.WriteRune(chars[idx])
+	}
+	return sb.String()
+}
+
+// recordPortfolioValue samples the portfolio total at most once per
+// portfolioSampleInterval.
+func (m *model) recordPortfolioValue() {
+	if time.Since(m.portfolioSampledAt) < portfolioSampleInterval {
+		return
+	}
+	m.portfolioSampledAt = time.Now()
+	total, ok := utils.Float64(m.calculateTotalPortfolioValue())
+	if !ok {
+		// The graphs work in float64; a total beyond its range is left out
+		// rather than drawn as +Inf.
+		return
+	}
+	m.portfolioHistory = append(m.portfolioHistory, total)
+	if len(m.portfolioHistory) > maxPortfolioHistory {
+		m.portfolioHistory = m.portfolioHistory[len(m.portfolioHistory)-maxPortfolioHistory:]
+	}
+}
+
+// updateDashboard handles keys in -dashboard mode, which is meant to run
+// unattended: only quitting, refreshing and Privacy Mode are available.
+func (m model) updateDashboard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc", "ctrl+c":
+		return m, tea.Quit
+	case "r":
+		m.watcher.Refresh()
+	case "P":
+		m.privacyMode = !m.privacyMode
+	}
+	return m, nil
+}
+
+// viewDashboard shows the grand total in large figures with its recent
+// history, and the total and gas price of every enabled chain.
+func (m model) viewDashboard() string {
+	total := m.fiat(m.displayValue(portfolio.Total(m.accounts, m.chains, m.prices), m.config.FiatDecimals))
+	figure := bigText(total)
+	if m.width > 0 && lipgloss.Width(figure) > m.width-4 {
+		figure = total
+	}
+	figure = infoStyle.Bold(true).Render(figure)
+
+	spark := subtleStyle.Render("collecting history...")
+	if len(m.portfolioHistory) > 1 {
+		spark = infoStyle.Render(sparkline(m.portfolioHistory, max(lipgloss.Width(figure), 30)))
+	}
+
+	var rows []string
+	for i, ct := range portfolio.ChainTotals(m.accounts, m.chains, m.prices) {
+		chain := m.chains[i]
+		if !chain.IsEnabled() {
+			continue
+		}
+		gas := subtleStyle.Render("gas n/a")
+		if p := m.gasPrices[chain.Name]; p != nil {
+			gwei, _ := new(big.Float).Quo(new(big.Float).SetInt(p), big.NewFloat(1e9)).Float64()
+			gas = gasLevelStyle(chain, gwei).Render(fmt.Sprintf("%8.2f Gwei", gwei))
+		}
+		value := m.fiat(m.displayValue(ct.Total, m.config.FiatDecimals))
+		rows = append(rows, fmt.Sprintf("%-16s %20s   %s", chain.Name, value, gas))
+	}
+
+	updated := "waiting for data"
+	if !m.lastUpdate.IsZero() {
+		updated = "updated " + m.lastUpdate.Format("15:04:05")
+	}
+	if m.privacyMode {
+		updated += " • 🔒"
+	}
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center,
+		lipgloss.JoinVertical(lipgloss.Center,
+			subtleStyle.Render("Total Portfolio Value"),
+			"",
+			figure,
+			"",
+			spark,
+			"\n",
+			strings.Join(rows, "\n"),
+			"\n",
+			subtleStyle.Render(updated),
+		))
+}
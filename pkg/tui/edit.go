@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// updateEditingAddress handles the editor opened with M, which changes the
+// active account's name and note.
+func (m model) updateEditingAddress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.editingAddress = false
+		m.resetEditInputs()
+		return m, nil
+	case "tab", "shift+tab", "up", "down":
+		m.focusEditInput(1 - m.editFocusIdx)
+		return m, nil
+	case "enter":
+		if m.editFocusIdx == 0 {
+			m.focusEditInput(1)
+			return m, nil
+		}
+		acc := m.accounts[m.activeIdx]
+		acc.Name = strings.TrimSpace(m.editAddressInput.Value())
+		acc.Note = strings.TrimSpace(m.editNoteInput.Value())
+		if err := m.saveConfig(); err != nil {
+			m.statusMessage = fmt.Sprintf("Failed to save config: %v", err)
+		} else {
+			m.statusMessage = "Address updated"
+		}
+		m.editingAddress = false
+		m.resetEditInputs()
+		if m.showDetail {
+			m.updateDetailViewport()
+		}
+		return m, clearStatusAfter(2 * time.Second)
+	}
+
+	var cmd tea.Cmd
+	if m.editFocusIdx == 0 {
+		m.editAddressInput, cmd = m.editAddressInput.Update(msg)
+	} else {
+		m.editNoteInput, cmd = m.editNoteInput.Update(msg)
+	}
+	return m, cmd
+}
+
+// startEditingAddress opens the editor prefilled with the active account's
+// name and note. Notes stay hidden in Privacy Mode, so it refuses there.
+func (m *model) startEditingAddress() tea.Cmd {
+	if len(m.accounts) == 0 {
+		return nil
+	}
+	if m.privacyMode {
+		m.statusMessage = "Notes are hidden in Privacy Mode"
+		return clearStatusAfter(2 * time.Second)
+	}
+	acc := m.accounts[m.activeIdx]
+	m.editAddressInput.SetValue(acc.Name)
+	m.editNoteInput.SetValue(acc.Note)
+	m.editingAddress = true
+	m.focusEditInput(1)
+	return nil
+}
+
+func (m *model) focusEditInput(idx int) {
+	m.editFocusIdx = idx
+	if idx == 0 {
+		m.editAddressInput.Focus()
+		m.editNoteInput.Blur()
+	} else {
+		m.editAddressInput.Blur()
+		m.editNoteInput.Focus()
+	}
+}
+
+func (m *model) resetEditInputs() {
+	m.editAddressInput.Reset()
+	m.editNoteInput.Reset()
+	m.editAddressInput.Blur()
+	m.editNoteInput.Blur()
+	m.editFocusIdx = 0
+}
+
+func (m model) viewEditAddress() string {
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left,
+			titleStyle.Render("Edit Address"),
+			"\n",
+			fmt.Sprintf("Address: %s", m.accounts[m.activeIdx].Address),
+			"\n",
+			fmt.Sprintf("%-5s %s", "Name", m.editAddressInput.View()),
+			fmt.Sprintf("%-5s %s", "Note", m.editNoteInput.View()),
+			"\n",
+			subtleStyle.Render("Tab to switch fields • Enter to save • Esc to cancel"),
+		)),
+	)
+}
@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"evmbal/pkg/config"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// updateExportingConfig handles the export form opened with X. Filling in the
+// passphrase encrypts the export and gives it the .enc extension; it has to be
+// entered twice so a typo can't produce a file nobody can decrypt.
+func (m model) updateExportingConfig(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.exportingConfig = false
+		m.resetExportInputs()
+		return m, nil
+	case "tab", "down":
+		m.focusExportInput((m.exportFocusIdx + 1) % 3)
+		return m, nil
+	case "shift+tab", "up":
+		m.focusExportInput((m.exportFocusIdx + 2) % 3)
+		return m, nil
+	case "enter":
+		path := strings.TrimSpace(m.exportInput.Value())
+		passphrase := m.exportPassInput.Value()
+		if path == "" {
+			m.statusMessage = "Enter a file path"
+			return m, clearStatusAfter(2 * time.Second)
+		}
+		if passphrase != m.exportConfirmInput.Value() {
+			m.statusMessage = "Passphrases do not match"
+			m.exportConfirmInput.Reset()
+			m.focusExportInput(2)
+			return m, clearStatusAfter(2 * time.Second)
+		}
+		if passphrase != "" && !strings.HasSuffix(path, config.EncryptedExtension) {
+			path += config.EncryptedExtension
+		}
+		if err := config.ExportConfig(m.addressConfigs(), m.chains, m.activeChainIdx, m.config, path, passphrase); err != nil {
+			m.statusMessage = fmt.Sprintf("Export failed: %v", err)
+		} else if passphrase != "" {
+			m.statusMessage = fmt.Sprintf("Encrypted config exported to %s", path)
+		} else {
+			m.statusMessage = fmt.Sprintf("Config exported to %s", path)
+		}
+		m.exportingConfig = false
+		m.resetExportInputs()
+		return m, clearStatusAfter(3 * time.Second)
+	}
+
+	var cmd tea.Cmd
+	input := m.exportInputs()[m.exportFocusIdx]
+	*input, cmd = input.Update(msg)
+	return m, cmd
+}
+
+// exportInputs returns the path, passphrase and confirmation inputs in focus
+// order.
+func (m *model) exportInputs() []*textinput.Model {
+	return []*textinput.Model{&m.exportInput, &m.exportPassInput, &m.exportConfirmInput}
+}
+
+func (m *model) focusExportInput(idx int) {
+	m.exportFocusIdx = idx
+	for i, input := range m.exportInputs() {
+		if i == idx {
+			input.Focus()
+		} else {
+			input.Blur()
+		}
+	}
+}
+
+func (m *model) resetExportInputs() {
+	for _, input := range m.exportInputs() {
+		input.Reset()
+	}
+	m.focusExportInput(0)
+	m.exportInput.Blur()
+}
+
+func (m model) viewExportConfig() string {
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left,
+			titleStyle.Render("Export Configuration"),
+			"\n",
+			"Enter file path:",
+			m.exportInput.View(),
+			"\n",
+			"Passphrase (optional, encrypts the export):",
+			m.exportPassInput.View(),
+			"Confirm passphrase:",
+			m.exportConfirmInput.View(),
+			"\n",
+			subtleStyle.Render("Tab to switch fields • Enter to save • Esc to cancel"),
+		)),
+	)
+}
@@ -3,96 +3,94 @@ package tui
 import (
 	"fmt"
 	"math/big"
+	"slices"
 	"strings"
+	"time"
 
+	"evmbal/pkg/config"
 	"evmbal/pkg/models"
+	"evmbal/pkg/portfolio"
+	"evmbal/pkg/utils"
 	"evmbal/pkg/watcher"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
-func (m model) calculateTotalPortfolioValue() float64 {
-	total := new(big.Float)
+// calculateTotalPortfolioValue sums the USD value of every account that is not
+// excluded from the total.
+func (m model) calculateTotalPortfolioValue() *big.Float {
+	return portfolio.Total(m.accounts, m.chains, m.prices)
+}
+
+// excludedMarker prefixes accounts left out of the portfolio total.
+const excludedMarker = "⊘ "
+
+// contractMarker prefixes accounts with code deployed, e.g. treasuries and multisigs.
+const contractMarker = "▣ "
+
+// errorMarker flags accounts whose last fetch failed on some chain.
+const errorMarker = "⚠ "
+
+// erroredChains returns the chains, in config order, whose last fetch failed
+// for acc.
+func (m model) erroredChains(acc *models.Account) []string {
+	var names []string
+	for _, c := range m.chains {
+		if acc.Errors[c.Name] != nil {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+func (m model) excludedAccountCount() int {
+	n := 0
 	for _, acc := range m.accounts {
-		for _, chain := range m.chains {
-			if bal, ok := acc.Balances[chain.Name]; ok {
-				if price, ok := m.prices[chain.CoinGeckoID]; ok {
-					val := new(big.Float).Mul(bal, big.NewFloat(price))
-					total.Add(total, val)
-				}
-			}
-			if tokens, ok := acc.TokenBalances[chain.Name]; ok {
-				for _, t := range chain.Tokens {
-					if bal, ok := tokens[t.Symbol]; ok {
-						if price, ok := m.prices[t.CoinGeckoID]; ok {
-							val := new(big.Float).Mul(bal, big.NewFloat(price))
-							total.Add(total, val)
-						}
-					}
-				}
-			}
+		if acc.ExcludeFromTotal {
+			n++
 		}
 	}
-	f, _ := total.Float64()
-	return f
+	return n
 }
 
 func (m *model) updateDetailViewport() {
-	activeAcc := m.accounts[m.activeIdx]
+	if len(m.accounts) == 0 {
+		m.viewport.SetContent("")
+		return
+	}
 	var sections []string
+	var itemRows []string
+	chainTotal := portfolio.NewTotal()
+	assets := m.detailAssets(m.accounts[m.activeIdx])
 
-	for _, chain := range m.chains {
-		// Only show chains with balances or tokens
-		hasContent := false
-		chainTotal := new(big.Float)
-		var itemRows []string
-
-		// Native Balance
-		if bal, ok := activeAcc.Balances[chain.Name]; ok {
-			val := new(big.Float)
-			price := m.prices[chain.CoinGeckoID]
-			if price > 0 {
-				val = new(big.Float).Mul(bal, big.NewFloat(price))
-			}
-			chainTotal.Add(chainTotal, val)
+	for i, a := range assets {
+		val := new(big.Float)
+		price := m.prices[a.priceID]
+		if price > 0 {
+			val = portfolio.NewTotal().Mul(a.balance, big.NewFloat(price))
+		}
+		chainTotal.Add(chainTotal, val)
 
-			valStr := ""
-			if price > 0 {
-				valStr = fmt.Sprintf("($%s)", m.displayValue(val, m.config.FiatDecimals))
-			}
-			itemRows = append(itemRows, fmt.Sprintf("  %-8s %12s %s", chain.Symbol, m.displayValue(bal, m.config.TokenDecimals), valStr))
-			hasContent = true
-		}
-
-		// Token Balances
-		if tokens, ok := activeAcc.TokenBalances[chain.Name]; ok {
-			for _, t := range chain.Tokens {
-				if bal, ok := tokens[t.Symbol]; ok && bal.Sign() > 0 {
-					val := new(big.Float)
-					price := m.prices[t.CoinGeckoID]
-					if price > 0 {
-						val = new(big.Float).Mul(bal, big.NewFloat(price))
-					}
-					chainTotal.Add(chainTotal, val)
-
-					valStr := ""
-					if price > 0 {
-						valStr = fmt.Sprintf("($%s)", m.displayValue(val, m.config.FiatDecimals))
-					}
-					itemRows = append(itemRows, fmt.Sprintf("  %-8s %12s %s", t.Symbol, m.displayValue(bal, m.config.TokenDecimals), valStr))
-					hasContent = true
-				}
-			}
+		marker := "  "
+		if i == m.detailAssetIdx {
+			marker = "> "
 		}
+		row := fmt.Sprintf("%s%-8s %12s", marker, a.symbol, m.displayValue(a.balance, a.decimals))
+		if price > 0 {
+			row = m.valueRow(row, val)
+		}
+		itemRows = append(itemRows, row)
 
-		if hasContent {
-			chainHeader := fmt.Sprintf("%s (Total: $%s)", chain.Name, m.displayValue(chainTotal, m.config.FiatDecimals))
-			section := lipgloss.JoinVertical(lipgloss.Left,
+		// Only chains with balances or tokens get a section.
+		if i == len(assets)-1 || assets[i+1].chain.Name != a.chain.Name {
+			chainHeader := fmt.Sprintf("%s (Total: %s)", a.chain.Name, m.fiat(m.displayValue(chainTotal, m.config.FiatDecimals)))
+			sections = append(sections, lipgloss.JoinVertical(lipgloss.Left,
 				subtleStyle.Render(chainHeader),
 				strings.Join(itemRows, "\n"),
-			)
-			sections = append(sections, section)
+			))
+			itemRows = nil
+			chainTotal = portfolio.NewTotal()
 		}
 	}
 
@@ -100,50 +98,477 @@ func (m *model) updateDetailViewport() {
 	if len(sections) == 0 {
 		content = "No balances found."
 	}
+	if failed := m.erroredChains(m.accounts[m.activeIdx]); len(failed) > 0 {
+		errRows := []string{errStyle.Render(errorMarker + "Fetch errors")}
+		for _, name := range failed {
+			errRows = append(errRows, fmt.Sprintf("  %s: %v", name, m.accounts[m.activeIdx].Errors[name]))
+		}
+		content = lipgloss.JoinVertical(lipgloss.Left, strings.Join(errRows, "\n"), "", content)
+	}
 	m.viewport.SetContent(content)
 }
 
-func (m model) calculateAccountTotal(acc *models.Account) *big.Float {
-	total := new(big.Float)
-	for _, chain := range m.chains {
-		if bal, ok := acc.Balances[chain.Name]; ok {
-			if price, ok := m.prices[chain.CoinGeckoID]; ok {
-				val := new(big.Float).Mul(bal, big.NewFloat(price))
-				total.Add(total, val)
-			}
+// portfolioSummaryText renders the portfolio as tab-separated rows for pasting
+// into a spreadsheet: each account's value per chain and in total, then the
+// grand total. Amounts are never abbreviated.
+func (m model) portfolioSummaryText() string {
+	fiat := func(f *big.Float) string {
+		return m.fiat(utils.FormatBigFloat(f, m.config.FiatDecimals))
+	}
+	var sb strings.Builder
+	sb.WriteString("Account\tAddress\tChain\tValue\n")
+	for _, acc := range m.accounts {
+		name := acc.Name
+		if acc.ExcludeFromTotal {
+			name = strings.TrimSpace(name + " (excluded from total)")
 		}
-		if tokens, ok := acc.TokenBalances[chain.Name]; ok {
-			for _, t := range chain.Tokens {
-				if bal, ok := tokens[t.Symbol]; ok {
-					if price, ok := m.prices[t.CoinGeckoID]; ok {
-						val := new(big.Float).Mul(bal, big.NewFloat(price))
-						total.Add(total, val)
-					}
-				}
+		accTotal := portfolio.NewTotal()
+		for _, cv := range portfolio.ChainValues(acc, m.chains, m.prices) {
+			if cv.Total.Sign() == 0 {
+				continue
 			}
+			accTotal.Add(accTotal, cv.Total)
+			fmt.Fprintf(&sb, "%s\t%s\t%s\t%s\n", name, acc.Address, cv.Chain, fiat(cv.Total))
+		}
+		fmt.Fprintf(&sb, "%s\t%s\tTotal\t%s\n", name, acc.Address, fiat(accTotal))
+	}
+	fmt.Fprintf(&sb, "Total Portfolio Value\t\t\t%s\n", fiat(portfolio.Total(m.accounts, m.chains, m.prices)))
+	return sb.String()
+}
+
+// valueRow appends the fiat value to a detail row and colors it by value tier.
+func (m model) valueRow(row string, val *big.Float) string {
+	return m.valueStyle(val).Render(fmt.Sprintf("%s (%s)", row, m.fiat(m.displayValue(val, m.config.FiatDecimals))))
+}
+
+func (m model) calculateAccountTotal(acc *models.Account) *big.Float {
+	return portfolio.AccountTotal(acc, m.chains, m.prices)
+}
+
+// chainTransactions returns the account's transactions on the active chain.
+func (m model) chainTransactions(acc *models.Account) []models.Transaction {
+	if len(m.chains) == 0 {
+		return nil
+	}
+	chain := m.chains[m.activeChainIdx].Name
+	var txs []models.Transaction
+	for _, tx := range acc.Transactions {
+		if tx.Chain == chain {
+			txs = append(txs, tx)
 		}
 	}
-	return total
+	return txs
 }
 
 func (m model) getFilteredTransactions(acc *models.Account) []models.Transaction {
 	if m.txFilter == "all" || m.txFilter == "" {
-		return acc.Transactions
+		return m.chainTransactions(acc)
 	}
 	var filtered []models.Transaction
-	for _, tx := range acc.Transactions {
+	for _, tx := range m.chainTransactions(acc) {
 		isFrom := strings.EqualFold(tx.From, acc.Address)
 		if m.txFilter == "in" && !isFrom {
 			filtered = append(filtered, tx)
 		} else if m.txFilter == "out" && isFrom {
 			filtered = append(filtered, tx)
+		} else if m.txFilter == "token" && tx.TokenSymbol != "" {
+			filtered = append(filtered, tx)
 		}
 	}
 	return filtered
 }
 
+// applyChainData copies a chain fetch result into the model's own accounts. The
+// payload is shared with other subscribers and the watcher, so balances are copied
+// rather than stored by reference. It returns a command when a baseline warning is shown.
+func (m *model) applyChainData(data models.ChainData) tea.Cmd {
+	var cmd tea.Cmd
+	if data.Err != nil {
+		// Accounts with a successful result below clear this again.
+		for _, acc := range m.accounts {
+			if acc.Errors == nil {
+				acc.Errors = make(map[string]error)
+			}
+			acc.Errors[data.ChainName] = data.Err
+		}
+	}
+	for _, res := range data.Results {
+		idx := m.findAccount(res.Address)
+		if idx < 0 {
+			continue
+		}
+		acc := m.accounts[idx]
+		if acc.Balances == nil {
+			acc.Balances = make(map[string]*big.Float)
+		}
+		if acc.TokenBalances == nil {
+			acc.TokenBalances = make(map[string]map[string]*big.Float)
+		}
+		if acc.Balances24h == nil {
+			acc.Balances24h = make(map[string]*big.Float)
+		}
+		if acc.Errors == nil {
+			acc.Errors = make(map[string]error)
+		}
+		if acc.Fetched == nil {
+			acc.Fetched = make(map[string]bool)
+		}
+		if baseline, ok := acc.Baselines[data.ChainName]; ok && res.Balance != nil && res.Balance.Cmp(baseline) != 0 {
+			if prev := acc.Balances[data.ChainName]; prev == nil || prev.Cmp(res.Balance) != 0 {
+				label := acc.Name
+				if label == "" {
+					label = acc.Address
+				}
+				m.statusMessage = fmt.Sprintf("⚠ %s balance on %s differs from baseline", label, data.ChainName)
+				cmd = clearStatusAfter(5 * time.Second)
+			}
+		}
+		acc.Balances[data.ChainName] = copyBigFloat(res.Balance)
+		acc.Balances24h[data.ChainName] = copyBigFloat(res.Balance24h)
+		if acc.TokenBalances[data.ChainName] == nil {
+			acc.TokenBalances[data.ChainName] = make(map[string]*big.Float)
+		}
+		for sym, bal := range res.TokenBalances {
+			acc.TokenBalances[data.ChainName][sym] = copyBigFloat(bal)
+		}
+		delete(acc.Errors, data.ChainName)
+		acc.Fetched[data.ChainName] = true
+		if res.IsContract {
+			acc.IsContract = true
+		}
+		m.recordAssetHistory(data.ChainName, res)
+	}
+	return cmd
+}
+
+// applyWatcherEvent folds a watcher event into the model and returns any
+// commands it schedules.
+func (m *model) applyWatcherEvent(msg watcher.Event) []tea.Cmd {
+	var cmds []tea.Cmd
+	m.lastEventAt[msg.Type] = time.Now()
+
+	switch msg.Type {
+	case watcher.EventPriceUpdated:
+		if data, ok := msg.Data.(models.PriceData); ok {
+			m.prices[data.CoinID] = data.Price
+			m.priceTrends[data.CoinID] = data.Trend
+			m.priceChanges24h[data.CoinID] = data.Change24h
+			if data.UpdatedAt.After(m.pricesUpdatedAt) {
+				m.pricesUpdatedAt = data.UpdatedAt
+			}
+		}
+	case watcher.EventChainDataUpdated:
+		if data, ok := msg.Data.(models.ChainData); ok {
+			m.chainLoading[data.ChainName] = false
+			m.chainLastUpdate[data.ChainName] = time.Now()
+			m.loading = m.anyChainLoading()
+			if cmd := m.applyChainData(data); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+	case watcher.EventGasPriceUpdated:
+		if data, ok := msg.Data.(models.GasPriceData); ok && data.Price != nil {
+			if prev := m.gasPrices[data.ChainName]; prev != nil {
+				m.gasTrends[data.ChainName] = data.Price.Cmp(prev)
+			}
+			m.gasPrices[data.ChainName] = data.Price
+			gwei := new(big.Float).Quo(new(big.Float).SetInt(data.Price), big.NewFloat(1e9))
+			val, _ := gwei.Float64()
+			history := append(m.gasPriceHistory[data.ChainName], models.GasPricePoint{Timestamp: time.Now(), Value: val})
+			if len(history) > 2880 {
+				history = history[len(history)-2880:]
+			}
+			m.gasPriceHistory[data.ChainName] = history
+		}
+	case watcher.EventGasAlert:
+		if data, ok := msg.Data.(models.GasAlert); ok {
+			m.statusMessage = fmt.Sprintf("⛽ Gas on %s is %.2f Gwei (below %.2f)", data.ChainName, data.GasGwei, data.ThresholdGwei)
+			cmds = append(cmds, clearStatusAfter(10*time.Second))
+		}
+	case watcher.EventFetchStarted, watcher.EventFetchCompleted:
+		if data, ok := msg.Data.(watcher.FetchProgress); ok && m.refreshProgress != nil {
+			if cmd := m.recordFetchProgress(msg.Type, data); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+	case watcher.EventRPCHealthUpdated:
+		if data, ok := msg.Data.(models.RPCLatencyData); ok {
+			m.recordRPCLatency(data)
+		}
+	case watcher.EventTransactionsUpdated:
+		if data, ok := msg.Data.(map[string]interface{}); ok {
+			addr, _ := data["address"].(string)
+			txs, _ := data["txs"].([]models.Transaction)
+			for _, acc := range m.accounts {
+				if acc.Address == addr {
+					// Merge so transactions from a range scan survive the next poll.
+					acc.Transactions = mergeTransactions(acc.Transactions, txs)
+					break
+				}
+			}
+		}
+	}
+
+	m.lastUpdate = time.Now()
+	if msg.Type == watcher.EventChainDataUpdated || msg.Type == watcher.EventPriceUpdated {
+		m.recordPortfolioValue()
+	}
+	if m.showDetail {
+		m.updateDetailViewport()
+	}
+	return cmds
+}
+
+// refreshDoneMsg hides the manual refresh progress line.
+type refreshDoneMsg struct{}
+
+// recordFetchProgress tracks a chain's progress during a manual refresh. Once every
+// chain has finished it returns a command that hides the progress line shortly after.
+func (m *model) recordFetchProgress(t watcher.EventType, p watcher.FetchProgress) tea.Cmd {
+	if _, ok := m.refreshProgress[p.ChainName]; !ok {
+		return nil
+	}
+	switch {
+	case t == watcher.EventFetchStarted:
+		m.refreshProgress[p.ChainName] = 0
+		return nil
+	case p.Err != nil:
+		m.refreshProgress[p.ChainName] = -1
+	default:
+		m.refreshProgress[p.ChainName] = 1
+	}
+	for _, c := range m.chains {
+		if state, ok := m.refreshProgress[c.Name]; ok && state == 0 {
+			return nil
+		}
+	}
+	return tea.Tick(3*time.Second, func(time.Time) tea.Msg { return refreshDoneMsg{} })
+}
+
+// refreshProgressView renders the manual refresh progress, e.g.
+// "Ethereum ✓ • Optimism … • Base ✗", or "" when no refresh is shown.
+func (m model) refreshProgressView() string {
+	if m.refreshProgress == nil {
+		return ""
+	}
+	var parts []string
+	for _, c := range m.chains {
+		state, ok := m.refreshProgress[c.Name]
+		if !ok {
+			continue
+		}
+		switch state {
+		case 1:
+			parts = append(parts, c.Name+" "+infoStyle.Render("✓"))
+		case -1:
+			parts = append(parts, c.Name+" "+errStyle.Render("✗"))
+		default:
+			parts = append(parts, c.Name+" "+subtleStyle.Render("…"))
+		}
+	}
+	return strings.Join(parts, subtleStyle.Render(" • "))
+}
+
+// copyBigFloat returns an independent copy of f, or nil.
+func copyBigFloat(f *big.Float) *big.Float {
+	if f == nil {
+		return nil
+	}
+	return new(big.Float).Copy(f)
+}
+
+// findAccount returns the index of the account with the given address, or -1.
+func (m model) findAccount(address string) int {
+	for i, acc := range m.accounts {
+		if strings.EqualFold(acc.Address, address) {
+			return i
+		}
+	}
+	return -1
+}
+
+// addressConfigs rebuilds the persisted address list from the model's accounts.
+func (m model) addressConfigs() []config.AddressConfig {
+	addrs := make([]config.AddressConfig, 0, len(m.accounts))
+	for _, acc := range m.accounts {
+		a := config.AddressConfig{Address: acc.Address, Name: acc.Name, Note: acc.Note, ExcludeFromTotal: acc.ExcludeFromTotal}
+		if len(acc.Baselines) > 0 {
+			a.BaselineBalances = make(map[string]string, len(acc.Baselines))
+			for chainName, bal := range acc.Baselines {
+				a.BaselineBalances[chainName] = bal.Text('g', -1)
+			}
+		}
+		addrs = append(addrs, a)
+	}
+	return addrs
+}
+
+// saveConfig persists the current accounts, chains and global settings.
+func (m model) saveConfig() error {
+	err := config.SaveConfig(m.addressConfigs(), m.chains, m.activeChainIdx, m.config, m.configPath)
+	if err != nil {
+		m.watcher.Logger().Error("config save failed", "path", m.configPath, "err", err)
+	} else {
+		m.watcher.Logger().Info("config saved", "path", m.configPath)
+	}
+	return err
+}
+
+// topBarChain returns the chain whose price and gas the top bar shows: the primary
+// chain if one is configured and still exists, otherwise the active chain.
+func (m model) topBarChain() config.ChainConfig {
+	if m.config.PrimaryChain != "" {
+		for _, c := range m.chains {
+			if c.Name == m.config.PrimaryChain {
+				return c
+			}
+		}
+	}
+	return m.chains[m.activeChainIdx]
+}
+
+// anyChainLoading reports whether a fetch is still in flight for any chain.
+func (m model) anyChainLoading() bool {
+	for _, loading := range m.chainLoading {
+		if loading {
+			return true
+		}
+	}
+	return false
+}
+
+// Typical gas usage for cost estimates.
+const (
+	transferGasUnits = 21000
+	swapGasUnits     = 150000
+)
+
+// gasCostUSD estimates the USD cost of spending gasUnits at gasPrice (wei), or 0 if unknown.
+func gasCostUSD(gasPrice *big.Int, gasUnits int64, nativePrice float64) float64 {
+	if gasPrice == nil || nativePrice <= 0 {
+		return 0
+	}
+	wei := new(big.Float).SetInt(new(big.Int).Mul(gasPrice, big.NewInt(gasUnits)))
+	native := new(big.Float).Quo(wei, big.NewFloat(1e18))
+	cost, _ := new(big.Float).Mul(native, big.NewFloat(nativePrice)).Float64()
+	return cost
+}
+
 func listenForWatcher(sub watcher.Subscriber) tea.Cmd {
 	return func() tea.Msg {
 		return <-sub
 	}
 }
+
+// recordRPCLatency stores a latency/health result for the network status view.
+func (m *model) recordRPCLatency(data models.RPCLatencyData) {
+	if m.rpcLatencyHistory == nil {
+		m.rpcLatencyHistory = make(map[string][]time.Duration)
+	}
+	if m.rpcLagging == nil {
+		m.rpcLagging = make(map[string]models.RPCLatencyData)
+	}
+	val := data.Latency
+	if data.Err != nil {
+		m.rpcLatencies[data.RPCURL] = -1
+		val = -1
+	} else {
+		m.rpcLatencies[data.RPCURL] = data.Latency
+	}
+	if data.Lagging {
+		m.rpcLagging[data.RPCURL] = data
+	} else {
+		delete(m.rpcLagging, data.RPCURL)
+	}
+	hist := m.rpcLatencyHistory[data.RPCURL]
+	hist = append(hist, val)
+	if n := m.latencyHistoryPoints(); len(hist) > n {
+		hist = hist[len(hist)-n:]
+	}
+	m.rpcLatencyHistory[data.RPCURL] = hist
+}
+
+// defaultLatencyHistoryPoints is the latency history kept per RPC when
+// latency_history_points is unset.
+const defaultLatencyHistoryPoints = 15
+
+// latencyHistoryPoints is how many latency samples are kept per RPC.
+func (m model) latencyHistoryPoints() int {
+	if m.config.LatencyHistoryPoints > 0 {
+		return m.config.LatencyHistoryPoints
+	}
+	return defaultLatencyHistoryPoints
+}
+
+// latencyStats returns the mean and 95th percentile (nearest rank) of the
+// successful checks in an RPC's latency history. ok is false if none succeeded.
+func latencyStats(history []time.Duration) (avg, p95 time.Duration, ok bool) {
+	var samples []time.Duration
+	var sum time.Duration
+	for _, v := range history {
+		if v != -1 {
+			samples = append(samples, v)
+			sum += v
+		}
+	}
+	if len(samples) == 0 {
+		return 0, 0, false
+	}
+	slices.Sort(samples)
+	rank := (95*len(samples) + 99) / 100
+	return sum / time.Duration(len(samples)), samples[rank-1], true
+}
+
+// autoCycle advances the view one step according to auto_cycle_mode: to the
+// next account, the next enabled chain, or through every account on a chain
+// before moving to the next chain ("both").
+func (m *model) autoCycle() tea.Cmd {
+	switch m.config.AutoCycleMode {
+	case "chains":
+		return m.nextEnabledChain()
+	case "both":
+		if len(m.accounts) > 1 {
+			m.activeIdx = (m.activeIdx + 1) % len(m.accounts)
+			if m.activeIdx != 0 {
+				return nil
+			}
+		}
+		return m.nextEnabledChain()
+	default:
+		if len(m.accounts) > 1 {
+			m.activeIdx = (m.activeIdx + 1) % len(m.accounts)
+		}
+	}
+	return nil
+}
+
+// nextEnabledChain makes the next enabled chain active, refreshing if it has
+// no data yet. The selected account is kept, showing as loading until the
+// chain's data arrives, unless reset_account_on_chain_switch is set.
+func (m *model) nextEnabledChain() tea.Cmd {
+	for i := 1; i < len(m.chains); i++ {
+		idx := (m.activeChainIdx + i) % len(m.chains)
+		if !m.chains[idx].IsEnabled() {
+			continue
+		}
+		m.activeChainIdx = idx
+		if m.config.ResetAccountOnChainSwitch {
+			m.activeIdx = 0
+		}
+		if m.showDetail {
+			m.updateDetailViewport()
+		}
+		name := m.chains[idx].Name
+		if !m.chainLastUpdate[name].IsZero() || m.chainLoading[name] {
+			return nil
+		}
+		m.chainLoading[name] = true
+		m.watcher.Refresh()
+		if m.loading {
+			return nil
+		}
+		m.loading = true
+		return m.spinner.Tick
+	}
+	return nil
+}
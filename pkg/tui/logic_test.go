@@ -3,10 +3,12 @@ package tui
 import (
 	"math/big"
 	"testing"
+	"time"
 
 	"evmbal/pkg/config"
 	"evmbal/pkg/models"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -26,7 +28,7 @@ func TestCalculateTotalPortfolioValue(t *testing.T) {
 		},
 	}
 
-	val := m.calculateTotalPortfolioValue()
+	val, _ := m.calculateTotalPortfolioValue().Float64()
 	assert.Equal(t, 3000.0, val)
 }
 
@@ -34,14 +36,15 @@ func TestGetFilteredTransactions(t *testing.T) {
 	acc := &models.Account{
 		Address: "0x123",
 		Transactions: []models.Transaction{
-			{From: "0x123", To: "0xabc", Value: "1.0"},
-			{From: "0xdef", To: "0x123", Value: "2.0"},
+			{From: "0x123", To: "0xabc", Value: "1.0", Chain: "Eth"},
+			{From: "0xdef", To: "0x123", Value: "2.0", Chain: "Eth"},
+			{From: "0x123", To: "0xabc", Value: "3.0", Chain: "Base"},
 		},
 	}
 
-	m := model{txFilter: "all"}
+	m := model{txFilter: "all", chains: []config.ChainConfig{{Name: "Eth"}, {Name: "Base"}}}
 	txs := m.getFilteredTransactions(acc)
-	assert.Equal(t, 2, len(txs))
+	assert.Equal(t, 2, len(txs), "only the active chain's transactions are listed")
 
 	m.txFilter = "out"
 	txs = m.getFilteredTransactions(acc)
@@ -53,3 +56,199 @@ func TestGetFilteredTransactions(t *testing.T) {
 	assert.Equal(t, 1, len(txs))
 	assert.Equal(t, "0xdef", txs[0].From)
 }
+
+func TestGasCostUSD(t *testing.T) {
+	// 20 Gwei * 21000 gas = 0.00042 ETH; at $2000 that's $0.84
+	gasPrice := big.NewInt(20_000_000_000)
+	assert.InDelta(t, 0.84, gasCostUSD(gasPrice, transferGasUnits, 2000), 1e-9)
+	assert.Equal(t, 0.0, gasCostUSD(gasPrice, transferGasUnits, 0))
+	assert.Equal(t, 0.0, gasCostUSD(nil, transferGasUnits, 2000))
+}
+
+func TestCalculateTotalPortfolioValue_SharedCoinGeckoID(t *testing.T) {
+	m := model{
+		chains: []config.ChainConfig{
+			{Name: "Ethereum", CoinGeckoID: "ethereum", Symbol: "ETH"},
+			{Name: "Optimism", CoinGeckoID: "ethereum", Symbol: "ETH"},
+		},
+		prices: map[string]float64{
+			"ethereum": 2000.0,
+		},
+		accounts: []*models.Account{
+			{
+				Address: "0x123",
+				Balances: map[string]*big.Float{
+					"Ethereum": big.NewFloat(1.0),
+					"Optimism": big.NewFloat(0.5),
+				},
+			},
+		},
+	}
+
+	portfolioTotal, _ := m.calculateTotalPortfolioValue().Float64()
+	assert.Equal(t, 3000.0, portfolioTotal)
+	total, _ := m.calculateAccountTotal(m.accounts[0]).Float64()
+	assert.Equal(t, 3000.0, total)
+}
+
+func TestCalculateAccountTotal_PriceAlias(t *testing.T) {
+	m := model{
+		chains: []config.ChainConfig{
+			{
+				Name: "Ethereum", CoinGeckoID: "ethereum", Symbol: "ETH",
+				Tokens: []config.TokenConfig{
+					{Symbol: "WETH", CoinGeckoID: "weth", PriceAlias: "ethereum"},
+				},
+			},
+		},
+		prices: map[string]float64{
+			"ethereum": 2000.0,
+		},
+	}
+	acc := &models.Account{
+		Address: "0x123",
+		TokenBalances: map[string]map[string]*big.Float{
+			"Ethereum": {"WETH": big.NewFloat(2.0)},
+		},
+	}
+
+	total, _ := m.calculateAccountTotal(acc).Float64()
+	assert.Equal(t, 4000.0, total)
+}
+
+func TestTopBarChain(t *testing.T) {
+	m := model{
+		chains: []config.ChainConfig{
+			{Name: "Ethereum", Symbol: "ETH"},
+			{Name: "Polygon", Symbol: "POL"},
+		},
+		activeChainIdx: 1,
+	}
+	assert.Equal(t, "Polygon", m.topBarChain().Name, "follows the active chain by default")
+
+	m.config.PrimaryChain = "Ethereum"
+	assert.Equal(t, "Ethereum", m.topBarChain().Name)
+
+	m.config.PrimaryChain = "Removed"
+	assert.Equal(t, "Polygon", m.topBarChain().Name, "falls back when the primary chain no longer exists")
+}
+
+func TestFormatFiat(t *testing.T) {
+	assert.Equal(t, "$1,234.56", formatFiat("1,234.56", config.GlobalConfig{}))
+	assert.Equal(t, "€1.234,56", formatFiat("1.234,56", config.GlobalConfig{FiatSymbol: "€"}))
+	assert.Equal(t, "1.234,56 kr", formatFiat("1.234,56", config.GlobalConfig{FiatSymbol: "kr", FiatSymbolSuffix: true}))
+	assert.Equal(t, "< $0.01", formatFiat("< 0.01", config.GlobalConfig{}), "the tiny-value marker stays in front")
+}
+
+func TestMainViewTxCount(t *testing.T) {
+	m := model{}
+	assert.Equal(t, 3, m.mainViewTxCount(), "defaults to 3")
+	m.config.MainViewTxCount = 10
+	assert.Equal(t, 10, m.mainViewTxCount())
+	m.height = mainViewReservedLines + 4
+	assert.Equal(t, 4, m.mainViewTxCount(), "clamped to the terminal height")
+	m.height = 10
+	assert.Equal(t, 1, m.mainViewTxCount())
+}
+
+func TestValueStyle(t *testing.T) {
+	m := model{}
+	assert.Equal(t, lipgloss.NewStyle(), m.valueStyle(big.NewFloat(50000)), "tiers are off by default")
+
+	m.config = config.GlobalConfig{HighValueUSD: 10000, DustValueUSD: 1}
+	assert.Equal(t, highValueStyle, m.valueStyle(big.NewFloat(10000)))
+	assert.Equal(t, subtleStyle, m.valueStyle(big.NewFloat(0.5)))
+	assert.Equal(t, lipgloss.NewStyle(), m.valueStyle(big.NewFloat(500)))
+	assert.Equal(t, subtleStyle, m.valueStyle(nil), "unpriced counts as zero")
+}
+
+func TestPortfolioSummaryText(t *testing.T) {
+	m := model{
+		chains: []config.ChainConfig{
+			{Name: "Eth", CoinGeckoID: "ethereum"},
+			{Name: "Base", CoinGeckoID: "ethereum"},
+		},
+		prices: map[string]float64{"ethereum": 2000},
+		accounts: []*models.Account{
+			{Address: "0x1", Name: "Cold", Balances: map[string]*big.Float{"Eth": big.NewFloat(1), "Base": big.NewFloat(0.5)}},
+			{Address: "0x2", Balances: map[string]*big.Float{"Eth": big.NewFloat(10)}, ExcludeFromTotal: true},
+		},
+		config: config.GlobalConfig{FiatDecimals: 2, CompactNumbers: true},
+	}
+
+	want := "Account\tAddress\tChain\tValue\n" +
+		"Cold\t0x1\tEth\t$2,000.00\n" +
+		"Cold\t0x1\tBase\t$1,000.00\n" +
+		"Cold\t0x1\tTotal\t$3,000.00\n" +
+		"(excluded from total)\t0x2\tEth\t$20,000.00\n" +
+		"(excluded from total)\t0x2\tTotal\t$20,000.00\n" +
+		"Total Portfolio Value\t\t\t$3,000.00\n"
+	assert.Equal(t, want, m.portfolioSummaryText())
+}
+
+func TestAutoCycle_Modes(t *testing.T) {
+	enabled, disabled := true, false
+	newModel := func(mode string) model {
+		m := model{
+			chains: []config.ChainConfig{
+				{Name: "Eth"}, {Name: "Off", Enabled: &disabled}, {Name: "Base", Enabled: &enabled},
+			},
+			accounts:        []*models.Account{{Address: "0x1"}, {Address: "0x2"}},
+			chainLastUpdate: map[string]time.Time{"Eth": time.Now(), "Base": time.Now()},
+			config:          config.GlobalConfig{AutoCycleMode: mode},
+		}
+		return m
+	}
+
+	m := newModel("")
+	m.autoCycle()
+	assert.Equal(t, 1, m.activeIdx)
+	assert.Equal(t, 0, m.activeChainIdx, "accounts only by default")
+
+	m = newModel("chains")
+	m.autoCycle()
+	assert.Equal(t, 0, m.activeIdx)
+	assert.Equal(t, 2, m.activeChainIdx, "disabled chains are skipped")
+	m.autoCycle()
+	assert.Equal(t, 0, m.activeChainIdx)
+
+	m = newModel("both")
+	var steps [][2]int
+	for i := 0; i < 4; i++ {
+		m.autoCycle()
+		steps = append(steps, [2]int{m.activeIdx, m.activeChainIdx})
+	}
+	assert.Equal(t, [][2]int{{1, 0}, {0, 2}, {1, 2}, {0, 0}}, steps, "every account on a chain, then the next chain")
+}
+
+func TestRecordRPCLatency_HistoryPoints(t *testing.T) {
+	m := model{rpcLatencies: make(map[string]time.Duration)}
+	for i := 0; i < 20; i++ {
+		m.recordRPCLatency(models.RPCLatencyData{RPCURL: "rpc", Latency: time.Duration(i) * time.Millisecond})
+	}
+	assert.Len(t, m.rpcLatencyHistory["rpc"], 15)
+	assert.Equal(t, 19*time.Millisecond, m.rpcLatencyHistory["rpc"][14])
+
+	m.config = config.GlobalConfig{LatencyHistoryPoints: 40}
+	for i := 0; i < 40; i++ {
+		m.recordRPCLatency(models.RPCLatencyData{RPCURL: "rpc", Latency: time.Millisecond})
+	}
+	assert.Len(t, m.rpcLatencyHistory["rpc"], 40)
+
+	assert.Equal(t, 10, lipgloss.Width(m.renderLatencySparkline(m.rpcLatencyHistory["rpc"], 10)))
+}
+
+func TestLatencyStats(t *testing.T) {
+	var history []time.Duration
+	for i := 1; i <= 20; i++ {
+		history = append(history, time.Duration(i)*10*time.Millisecond)
+	}
+	history = append(history, -1)
+	avg, p95, ok := latencyStats(history)
+	assert.True(t, ok)
+	assert.Equal(t, 105*time.Millisecond, avg)
+	assert.Equal(t, 190*time.Millisecond, p95, "failed checks are not samples")
+
+	_, _, ok = latencyStats([]time.Duration{-1, -1})
+	assert.False(t, ok)
+}
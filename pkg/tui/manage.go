@@ -0,0 +1,572 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"evmbal/pkg/config"
+	"evmbal/pkg/models"
+	"evmbal/pkg/rpc"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// tokenImportMsg carries the result of a bulk token metadata fetch.
+type tokenImportMsg struct {
+	chainIdx  int
+	addresses []string
+	metas     []models.TokenMetadata
+	skipped   int
+	err       error
+}
+
+// tokenListMsg carries tokens downloaded from a token list URL, pending review.
+type tokenListMsg struct {
+	chainIdx int
+	tokens   []config.TokenConfig
+	err      error
+}
+
+// maxTokenListImport caps how many tokens a single token list import may add.
+const maxTokenListImport = 50
+
+func fetchTokenListCmd(chainIdx int, url string, chainID int64) tea.Cmd {
+	return func() tea.Msg {
+		tokens, err := rpc.FetchTokenList(url, chainID)
+		return tokenListMsg{chainIdx: chainIdx, tokens: tokens, err: err}
+	}
+}
+
+func fetchTokenMetadataCmd(rpcURLs []string, timeout time.Duration, address string) tea.Cmd {
+	return func() tea.Msg {
+		meta, _ := rpc.FetchTokenMetadata(rpcURLs, address, timeout)
+		return meta
+	}
+}
+
+func fetchTokenMetadataBatchCmd(chainIdx int, rpcURLs []string, timeout time.Duration, addresses []string, skipped int) tea.Cmd {
+	return func() tea.Msg {
+		metas, err := rpc.FetchTokenMetadataBatch(rpcURLs, addresses, timeout)
+		return tokenImportMsg{chainIdx: chainIdx, addresses: addresses, metas: metas, skipped: skipped, err: err}
+	}
+}
+
+// parseTokenAddresses accepts either a JSON array of addresses or a comma/whitespace
+// separated list. It returns the valid addresses and the number of invalid entries.
+func parseTokenAddresses(input string) ([]string, int) {
+	input = strings.TrimSpace(input)
+	var entries []string
+	if strings.HasPrefix(input, "[") {
+		if err := json.Unmarshal([]byte(input), &entries); err != nil {
+			return nil, 1
+		}
+	} else {
+		entries = strings.FieldsFunc(input, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\n' || r == '\t'
+		})
+	}
+
+	var valid []string
+	invalid := 0
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if !common.IsHexAddress(e) {
+			invalid++
+			continue
+		}
+		valid = append(valid, e)
+	}
+	return valid, invalid
+}
+
+// chainHasToken reports whether a token address is already configured on the chain.
+func chainHasToken(chain config.ChainConfig, address string) bool {
+	for _, t := range chain.Tokens {
+		if strings.EqualFold(t.Address, address) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyChainChanges persists the chain list and hands it to the watcher.
+func (m *model) applyChainChanges() error {
+	m.watcher.SetChains(m.chains)
+	return m.saveConfig()
+}
+
+func clearStatusAfter(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return clearStatusMsg{}
+	})
+}
+
+func (m model) updateManagingChains(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc":
+		m.managingChains = false
+	case "up", "k":
+		if m.chainListIdx > 0 {
+			m.chainListIdx--
+		}
+	case "down", "j":
+		if m.chainListIdx < len(m.chains)-1 {
+			m.chainListIdx++
+		}
+	case "a":
+		m.addingChain = true
+		focusInputs(m.chainInputs, 0)
+		return m, textinput.Blink
+	case "t":
+		m.managingTokens = true
+		m.selectedChainForTokens = m.chainListIdx
+		m.tokenListIdx = 0
+	case "r":
+		m.managingRPCs = true
+		m.rpcListIdx = 0
+	case " ":
+		m.chains = append([]config.ChainConfig(nil), m.chains...)
+		chain := &m.chains[m.chainListIdx]
+		state := "Enabled"
+		if chain.IsEnabled() {
+			disabled := false
+			chain.Enabled = &disabled
+			state = "Disabled"
+		} else {
+			// Leave Enabled unset so the saved config stays minimal.
+			chain.Enabled = nil
+		}
+		m.chainLoading[chain.Name] = false
+		if err := m.applyChainChanges(); err != nil {
+			m.statusMessage = fmt.Sprintf("Failed to save config: %v", err)
+		} else {
+			m.statusMessage = fmt.Sprintf("%s chain %s", state, chain.Name)
+		}
+		return m, clearStatusAfter(2 * time.Second)
+	case "d":
+		if len(m.chains) <= 1 {
+			m.statusMessage = "Cannot delete the last chain"
+			return m, clearStatusAfter(2 * time.Second)
+		}
+		removed := m.chains[m.chainListIdx]
+		chains := append([]config.ChainConfig(nil), m.chains[:m.chainListIdx]...)
+		m.chains = append(chains, m.chains[m.chainListIdx+1:]...)
+		if m.activeChainIdx > m.chainListIdx || m.activeChainIdx >= len(m.chains) {
+			m.activeChainIdx--
+		}
+		if m.chainListIdx >= len(m.chains) {
+			m.chainListIdx = len(m.chains) - 1
+		}
+		if err := m.applyChainChanges(); err != nil {
+			m.statusMessage = fmt.Sprintf("Failed to save config: %v", err)
+		} else {
+			m.statusMessage = fmt.Sprintf("Removed chain %s", removed.Name)
+		}
+		return m, clearStatusAfter(2 * time.Second)
+	}
+	return m, nil
+}
+
+func (m model) updateAddingChain(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.updateCoinSuggestions(msg) {
+		return m, nil
+	}
+	focusIdx := focusedInput(m.chainInputs)
+	switch msg.String() {
+	case "esc":
+		m.addingChain = false
+		resetInputs(m.chainInputs)
+		return m, nil
+	case "tab", "down":
+		focusInputs(m.chainInputs, focusIdx+1)
+		return m, nil
+	case "shift+tab", "up":
+		focusInputs(m.chainInputs, focusIdx-1)
+		return m, nil
+	case "enter":
+		if focusIdx < len(m.chainInputs)-1 {
+			focusInputs(m.chainInputs, focusIdx+1)
+			return m, nil
+		}
+		name := strings.TrimSpace(m.chainInputs[0].Value())
+		var rpcURLs []string
+		for _, u := range strings.Split(m.chainInputs[3].Value(), ",") {
+			if u = strings.TrimSpace(u); u != "" {
+				rpcURLs = append(rpcURLs, u)
+			}
+		}
+		switch {
+		case name == "":
+			m.statusMessage = "Chain name is required"
+		case len(rpcURLs) == 0:
+			m.statusMessage = "At least one RPC URL is required"
+		default:
+			for _, c := range m.chains {
+				if strings.EqualFold(c.Name, name) {
+					m.statusMessage = fmt.Sprintf("Chain %s already exists", name)
+					return m, clearStatusAfter(2 * time.Second)
+				}
+			}
+			m.chains = append(m.chains, config.ChainConfig{
+				Name:        name,
+				Symbol:      strings.TrimSpace(m.chainInputs[1].Value()),
+				CoinGeckoID: strings.TrimSpace(m.chainInputs[2].Value()),
+				RPCURLs:     rpcURLs,
+				ExplorerURL: strings.TrimSpace(m.chainInputs[4].Value()),
+			})
+			m.chainLoading[name] = true
+			if err := m.applyChainChanges(); err != nil {
+				m.statusMessage = fmt.Sprintf("Failed to save config: %v", err)
+			} else {
+				m.statusMessage = fmt.Sprintf("Added chain %s", name)
+			}
+			m.addingChain = false
+			resetInputs(m.chainInputs)
+		}
+		return m, clearStatusAfter(2 * time.Second)
+	}
+	var cmd tea.Cmd
+	m.chainInputs[focusIdx], cmd = m.chainInputs[focusIdx].Update(msg)
+	return m, tea.Batch(cmd, m.scheduleCoinSearch())
+}
+
+func (m model) updateManagingTokens(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	chain := m.chains[m.selectedChainForTokens]
+	switch msg.String() {
+	case "q", "esc":
+		m.managingTokens = false
+	case "up", "k":
+		if m.tokenListIdx > 0 {
+			m.tokenListIdx--
+		}
+	case "down", "j":
+		if m.tokenListIdx < len(chain.Tokens)-1 {
+			m.tokenListIdx++
+		}
+	case "a":
+		m.addingToken = true
+		focusInputs(m.tokenInputs, 0)
+		return m, textinput.Blink
+	case "i":
+		m.importingTokens = true
+		m.tokenImportInput.Reset()
+		m.tokenImportInput.Focus()
+		return m, textinput.Blink
+	case "d":
+		if len(chain.Tokens) == 0 {
+			return m, nil
+		}
+		removed := chain.Tokens[m.tokenListIdx]
+		tokens := append([]config.TokenConfig(nil), chain.Tokens[:m.tokenListIdx]...)
+		m.chains[m.selectedChainForTokens].Tokens = append(tokens, chain.Tokens[m.tokenListIdx+1:]...)
+		if m.tokenListIdx >= len(m.chains[m.selectedChainForTokens].Tokens) && m.tokenListIdx > 0 {
+			m.tokenListIdx--
+		}
+		if err := m.applyChainChanges(); err != nil {
+			m.statusMessage = fmt.Sprintf("Failed to save config: %v", err)
+		} else {
+			m.statusMessage = fmt.Sprintf("Removed token %s", removed.Symbol)
+		}
+		return m, clearStatusAfter(2 * time.Second)
+	}
+	return m, nil
+}
+
+func (m model) updateAddingToken(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.confirmingTokenDecimals {
+		return m.updateConfirmingTokenDecimals(msg)
+	}
+	if m.updateCoinSuggestions(msg) {
+		return m, nil
+	}
+	focusIdx := focusedInput(m.tokenInputs)
+	chain := m.chains[m.selectedChainForTokens]
+	switch msg.String() {
+	case "esc":
+		m.addingToken = false
+		resetInputs(m.tokenInputs)
+		return m, nil
+	case "tab", "down", "shift+tab", "up", "enter":
+		next := focusIdx + 1
+		if msg.String() == "shift+tab" || msg.String() == "up" {
+			next = focusIdx - 1
+		} else if msg.String() == "enter" && focusIdx == len(m.tokenInputs)-1 {
+			return m.saveNewToken()
+		}
+		focusInputs(m.tokenInputs, next)
+		// Leaving the address field: look up symbol and decimals on-chain
+		addr := strings.TrimSpace(m.tokenInputs[1].Value())
+		if focusIdx == 1 && common.IsHexAddress(addr) {
+			m.statusMessage = "Fetching token metadata..."
+			return m, fetchTokenMetadataCmd(chain.ActiveRPCURLs(), chain.Timeout(rpc.DefaultRequestTimeout), addr)
+		}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.tokenInputs[focusIdx], cmd = m.tokenInputs[focusIdx].Update(msg)
+	return m, tea.Batch(cmd, m.scheduleCoinSearch())
+}
+
+func (m model) saveNewToken() (tea.Model, tea.Cmd) {
+	symbol := strings.TrimSpace(m.tokenInputs[0].Value())
+	addr := strings.TrimSpace(m.tokenInputs[1].Value())
+	decimals, err := strconv.Atoi(strings.TrimSpace(m.tokenInputs[2].Value()))
+
+	switch {
+	case symbol == "":
+		m.statusMessage = "Token symbol is required"
+	case !common.IsHexAddress(addr):
+		m.statusMessage = "Invalid token address"
+	case err != nil || decimals < 0:
+		m.statusMessage = "Invalid decimals"
+	case chainHasToken(m.chains[m.selectedChainForTokens], addr):
+		m.statusMessage = "Token is already configured on this chain"
+	default:
+		// Save once the decimals are checked against the contract
+		m.statusMessage = "Checking decimals on-chain..."
+		chain := m.chains[m.selectedChainForTokens]
+		return m, verifyTokenDecimalsCmd(chain.ActiveRPCURLs(), chain.Timeout(rpc.DefaultRequestTimeout), addr, decimals)
+	}
+	return m, clearStatusAfter(2 * time.Second)
+}
+
+// tokenDecimalsMsg carries the on-chain metadata of a token about to be added
+// with the decimals that were entered for it.
+type tokenDecimalsMsg struct {
+	address string
+	entered int
+	meta    models.TokenMetadata
+}
+
+func verifyTokenDecimalsCmd(rpcURLs []string, timeout time.Duration, address string, entered int) tea.Cmd {
+	return func() tea.Msg {
+		meta, _ := rpc.FetchTokenMetadata(rpcURLs, address, timeout)
+		return tokenDecimalsMsg{address: address, entered: entered, meta: meta}
+	}
+}
+
+// applyTokenDecimalsCheck saves the new token if its decimals match the
+// contract's, or asks which to use if they differ. Tokens whose decimals cannot
+// be read are saved as entered.
+func (m model) applyTokenDecimalsCheck(msg tokenDecimalsMsg) (tea.Model, tea.Cmd) {
+	if !m.addingToken || !strings.EqualFold(strings.TrimSpace(m.tokenInputs[1].Value()), msg.address) {
+		return m, nil
+	}
+	switch {
+	case msg.meta.Err != nil || msg.meta.DecimalsDefaulted:
+		m.addToken(msg.entered)
+		m.statusMessage += " (decimals could not be verified on-chain)"
+	case msg.meta.Decimals != msg.entered:
+		m.confirmingTokenDecimals = true
+		m.tokenDecimalsOnChain = msg.meta.Decimals
+		m.statusMessage = ""
+		return m, nil
+	default:
+		m.addToken(msg.entered)
+	}
+	return m, clearStatusAfter(3 * time.Second)
+}
+
+// updateConfirmingTokenDecimals asks whether to use the on-chain decimals after
+// they turned out to differ from the entered ones.
+func (m model) updateConfirmingTokenDecimals(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	entered, _ := strconv.Atoi(strings.TrimSpace(m.tokenInputs[2].Value()))
+	switch msg.String() {
+	case "y", "Y":
+		m.confirmingTokenDecimals = false
+		m.addToken(m.tokenDecimalsOnChain)
+	case "n", "N":
+		m.confirmingTokenDecimals = false
+		m.addToken(entered)
+	case "esc":
+		m.confirmingTokenDecimals = false
+		return m, nil
+	default:
+		return m, nil
+	}
+	return m, clearStatusAfter(2 * time.Second)
+}
+
+// addToken saves the token in the add-token form with the given decimals.
+func (m *model) addToken(decimals int) {
+	symbol := strings.TrimSpace(m.tokenInputs[0].Value())
+	m.chains[m.selectedChainForTokens].Tokens = append(m.chains[m.selectedChainForTokens].Tokens, config.TokenConfig{
+		Symbol:      symbol,
+		Address:     strings.TrimSpace(m.tokenInputs[1].Value()),
+		Decimals:    decimals,
+		CoinGeckoID: strings.TrimSpace(m.tokenInputs[3].Value()),
+	})
+	if err := m.applyChainChanges(); err != nil {
+		m.statusMessage = fmt.Sprintf("Failed to save config: %v", err)
+	} else {
+		m.statusMessage = fmt.Sprintf("Added token %s", symbol)
+	}
+	m.addingToken = false
+	resetInputs(m.tokenInputs)
+}
+
+func (m model) updateImportingTokens(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.importingTokens = false
+		m.tokenImportInput.Blur()
+		return m, nil
+	case "enter":
+		chain := m.chains[m.selectedChainForTokens]
+		input := strings.TrimSpace(m.tokenImportInput.Value())
+		if strings.HasPrefix(input, "http://") || strings.HasPrefix(input, "https://") {
+			m.importingTokens = false
+			m.tokenImportInput.Blur()
+			m.statusMessage = "Downloading token list..."
+			return m, fetchTokenListCmd(m.selectedChainForTokens, input, chain.ChainID)
+		}
+		addrs, skipped := parseTokenAddresses(input)
+		var pending []string
+		for _, a := range addrs {
+			if chainHasToken(chain, a) {
+				skipped++
+				continue
+			}
+			pending = append(pending, a)
+		}
+		if len(pending) == 0 {
+			m.statusMessage = "No new token addresses to import"
+			return m, clearStatusAfter(2 * time.Second)
+		}
+		m.importingTokens = false
+		m.tokenImportInput.Blur()
+		m.statusMessage = fmt.Sprintf("Fetching metadata for %d tokens...", len(pending))
+		return m, fetchTokenMetadataBatchCmd(m.selectedChainForTokens, chain.ActiveRPCURLs(), chain.Timeout(rpc.DefaultRequestTimeout), pending, skipped)
+	}
+	var cmd tea.Cmd
+	m.tokenImportInput, cmd = m.tokenImportInput.Update(msg)
+	return m, cmd
+}
+
+// applyTokenImport appends successfully resolved tokens from a batch import.
+func (m *model) applyTokenImport(msg tokenImportMsg) {
+	if msg.err != nil {
+		m.statusMessage = fmt.Sprintf("Token import failed: %v", msg.err)
+		return
+	}
+	if msg.chainIdx >= len(m.chains) {
+		return
+	}
+
+	added, failed := 0, 0
+	for i, meta := range msg.metas {
+		if meta.Err != nil || meta.Symbol == "" {
+			failed++
+			continue
+		}
+		if chainHasToken(m.chains[msg.chainIdx], msg.addresses[i]) {
+			continue
+		}
+		m.chains[msg.chainIdx].Tokens = append(m.chains[msg.chainIdx].Tokens, config.TokenConfig{
+			Symbol:   meta.Symbol,
+			Address:  msg.addresses[i],
+			Decimals: meta.Decimals,
+		})
+		added++
+	}
+
+	status := fmt.Sprintf("Imported %d tokens", added)
+	if failed > 0 {
+		status += fmt.Sprintf(", %d failed", failed)
+	}
+	if msg.skipped > 0 {
+		status += fmt.Sprintf(", %d skipped", msg.skipped)
+	}
+	if added > 0 {
+		if err := m.applyChainChanges(); err != nil {
+			status = fmt.Sprintf("Failed to save config: %v", err)
+		}
+	}
+	m.statusMessage = status
+}
+
+// reviewTokenList stages new tokens from a downloaded list for confirmation.
+func (m *model) reviewTokenList(msg tokenListMsg) {
+	if msg.err != nil {
+		m.statusMessage = fmt.Sprintf("Token list import failed: %v", msg.err)
+		return
+	}
+	if msg.chainIdx >= len(m.chains) {
+		return
+	}
+
+	var pending []config.TokenConfig
+	for _, t := range msg.tokens {
+		if chainHasToken(m.chains[msg.chainIdx], t.Address) {
+			continue
+		}
+		pending = append(pending, t)
+	}
+	if len(pending) == 0 {
+		m.statusMessage = "No new tokens for this chain in the list"
+		return
+	}
+	if len(pending) > maxTokenListImport {
+		m.statusMessage = fmt.Sprintf("List has %d new tokens; only the first %d will be imported", len(pending), maxTokenListImport)
+		pending = pending[:maxTokenListImport]
+	}
+	m.pendingTokens = pending
+	m.pendingTokensChainIdx = msg.chainIdx
+	m.reviewingTokens = true
+}
+
+func (m model) updateReviewingTokens(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter", "y":
+		idx := m.pendingTokensChainIdx
+		if idx < len(m.chains) {
+			m.chains[idx].Tokens = append(m.chains[idx].Tokens, m.pendingTokens...)
+			if err := m.applyChainChanges(); err != nil {
+				m.statusMessage = fmt.Sprintf("Failed to save config: %v", err)
+			} else {
+				m.statusMessage = fmt.Sprintf("Imported %d tokens", len(m.pendingTokens))
+			}
+		}
+	case "esc", "n", "q":
+		m.statusMessage = "Token import discarded"
+	default:
+		return m, nil
+	}
+	m.reviewingTokens = false
+	m.pendingTokens = nil
+	return m, clearStatusAfter(2 * time.Second)
+}
+
+// focusInputs moves focus to the given field of a form, wrapping around.
+func focusInputs(inputs []textinput.Model, idx int) {
+	n := len(inputs)
+	idx = ((idx % n) + n) % n
+	for i := range inputs {
+		if i == idx {
+			inputs[i].Focus()
+		} else {
+			inputs[i].Blur()
+		}
+	}
+}
+
+func focusedInput(inputs []textinput.Model) int {
+	for i := range inputs {
+		if inputs[i].Focused() {
+			return i
+		}
+	}
+	return 0
+}
+
+func resetInputs(inputs []textinput.Model) {
+	for i := range inputs {
+		inputs[i].Reset()
+		inputs[i].Blur()
+	}
+}
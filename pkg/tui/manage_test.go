@@ -0,0 +1,328 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"evmbal/pkg/config"
+	"evmbal/pkg/models"
+	"evmbal/pkg/rpc"
+	"evmbal/pkg/watcher"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTokenAddresses(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantValid   int
+		wantInvalid int
+	}{
+		{"JSON array", `["0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", "0xdAC17F958D2ee523a2206206994597C13D831ec7"]`, 2, 0},
+		{"Comma separated", "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48, 0xdAC17F958D2ee523a2206206994597C13D831ec7", 2, 0},
+		{"Invalid entries", "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48 nope 0x123", 1, 2},
+		{"Malformed JSON", `["0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"`, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, invalid := parseTokenAddresses(tt.input)
+			assert.Len(t, valid, tt.wantValid)
+			assert.Equal(t, tt.wantInvalid, invalid)
+		})
+	}
+}
+
+func TestSaveNewToken_RejectsDuplicateAddress(t *testing.T) {
+	chains := []config.ChainConfig{{
+		Name:    "Eth",
+		Symbol:  "ETH",
+		RPCURLs: []string{"http://localhost:8545"},
+		Tokens:  []config.TokenConfig{{Symbol: "USDC", Address: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", Decimals: 6}},
+	}}
+	w := watcher.NewWatcher(nil, chains, config.GlobalConfig{}, "")
+	m := initialModel(w, nil, chains, 0, config.GlobalConfig{}, filepath.Join(t.TempDir(), "config.json"))
+	m.addingToken = true
+	m.tokenInputs[0].SetValue("usdc")
+	m.tokenInputs[1].SetValue("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	m.tokenInputs[2].SetValue("6")
+
+	updated, _ := m.saveNewToken()
+	m = updated.(model)
+
+	assert.Len(t, m.chains[0].Tokens, 1)
+	assert.True(t, m.addingToken, "form should stay open on rejection")
+	assert.Contains(t, m.statusMessage, "already configured")
+}
+
+func TestUpdateManagingChains_ToggleEnabled(t *testing.T) {
+	chains := []config.ChainConfig{
+		{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://localhost:8545"}},
+		{Name: "Base", Symbol: "ETH", RPCURLs: []string{"http://localhost:8546"}},
+	}
+	path := filepath.Join(t.TempDir(), "config.json")
+	w := watcher.NewWatcher(nil, chains, config.GlobalConfig{}, "")
+	m := initialModel(w, nil, chains, 0, config.GlobalConfig{}, path)
+	m.managingChains = true
+	m.chainListIdx = 1
+
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+	updated, _ := m.updateManagingChains(space)
+	m = updated.(model)
+	assert.False(t, m.chains[1].IsEnabled())
+	assert.True(t, chains[1].IsEnabled(), "the caller's slice is not modified")
+	assert.Contains(t, m.View(), "(disabled)")
+
+	_, saved, _, _, err := config.LoadConfigFromFile(path)
+	assert.NoError(t, err)
+	assert.Len(t, saved, 2, "disabled chains keep their config")
+	assert.False(t, saved[1].IsEnabled())
+
+	updated, _ = m.updateManagingChains(space)
+	m = updated.(model)
+	assert.True(t, m.chains[1].IsEnabled())
+	assert.Nil(t, m.chains[1].Enabled)
+}
+
+func TestBackups_DeleteAndRestore(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://localhost:8545"}}}
+	path := filepath.Join(t.TempDir(), "config.json")
+	addrs := []config.AddressConfig{{Address: "0x1111111111111111111111111111111111111111", Name: "Old"}}
+	assert.NoError(t, config.SaveConfig(addrs, chains, 0, config.GlobalConfig{}, path))
+	// The second save backs up the first, which only has the "Old" address.
+	addrs = append(addrs, config.AddressConfig{Address: "0x2222222222222222222222222222222222222222", Name: "New"})
+	assert.NoError(t, config.SaveConfig(addrs, chains, 0, config.GlobalConfig{}, path))
+	assert.NoError(t, os.WriteFile(path+".20000101-000000.bak", []byte(`{}`), 0644))
+
+	w := watcher.NewWatcher(addrs, chains, config.GlobalConfig{}, "")
+	m := initialModel(w, addrs, chains, 0, config.GlobalConfig{}, path)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("B")})
+	m = updated.(model)
+	assert.True(t, m.restoringBackup)
+	assert.Len(t, m.backups, 2)
+	assert.Contains(t, m.View(), "2000-01-01 00:00:00")
+
+	// The oldest backup is listed last; delete it.
+	m.backupListIdx = 1
+	updated, _ = m.updateRestoringBackup(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	m = updated.(model)
+	assert.Len(t, m.backups, 1)
+	assert.NoFileExists(t, path+".20000101-000000.bak")
+
+	updated, _ = m.updateRestoringBackup(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	assert.True(t, m.confirmingRestore)
+	assert.NoError(t, m.restoreDiffErr)
+	assert.Equal(t, config.ConfigDiff{AddressesRemoved: 1}, m.restoreDiff)
+	assert.Contains(t, m.View(), "1 address(es) in the current config will be lost")
+	updated, _ = m.updateRestoringBackup(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = updated.(model)
+	assert.Equal(t, "Backup restored", m.statusMessage)
+	assert.Len(t, m.accounts, 1, "the restored config is applied")
+	assert.Len(t, w.GetAccounts(), 1, "and handed to the watcher")
+}
+
+func TestExportConfig_Encrypted(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://localhost:8545"}}}
+	addrs := []config.AddressConfig{{Address: "0x1111111111111111111111111111111111111111", Name: "Cold"}}
+	w := watcher.NewWatcher(addrs, chains, config.GlobalConfig{}, "")
+	m := initialModel(w, addrs, chains, 0, config.GlobalConfig{}, filepath.Join(t.TempDir(), "config.json"))
+	out := filepath.Join(t.TempDir(), "export.json")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("X")})
+	m = updated.(model)
+	assert.True(t, m.exportingConfig)
+	for _, msg := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune(out)},
+		{Type: tea.KeyTab},
+		{Type: tea.KeyRunes, Runes: []rune("hunter2")},
+		{Type: tea.KeyTab},
+		{Type: tea.KeyRunes, Runes: []rune("hunter3")},
+	} {
+		updated, _ = m.Update(msg)
+		m = updated.(model)
+	}
+	assert.NotContains(t, m.View(), "hunter")
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+
+	assert.True(t, m.exportingConfig, "a mismatched confirmation keeps the form open")
+	assert.Equal(t, "Passphrases do not match", m.statusMessage)
+	assert.NoFileExists(t, out+config.EncryptedExtension)
+
+	for _, msg := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("hunter2")},
+		{Type: tea.KeyEnter},
+	} {
+		updated, _ = m.Update(msg)
+		m = updated.(model)
+	}
+
+	assert.False(t, m.exportingConfig)
+	st, err := os.Stat(out + config.EncryptedExtension)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), st.Mode().Perm())
+	data, err := os.ReadFile(out + config.EncryptedExtension)
+	assert.NoError(t, err)
+	plain, err := config.Decrypt(data, "hunter2")
+	assert.NoError(t, err)
+	assert.Contains(t, string(plain), "Cold")
+}
+
+func TestAddingChain_CoinGeckoSuggestions(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://localhost:8545"}}}
+	w := watcher.NewWatcher(nil, chains, config.GlobalConfig{}, "")
+	var m tea.Model = initialModel(w, nil, chains, 0, config.GlobalConfig{}, "")
+	mm := m.(model)
+	mm.addingChain = true
+	focusInputs(mm.chainInputs, 2)
+	m = mm
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("usdc")})
+	assert.NotNil(t, cmd, "a search is scheduled")
+
+	coins := []rpc.CoinSuggestion{{ID: "usd-coin", Name: "USDC", Symbol: "usdc", MarketCapRank: 7}, {ID: "bridged-usdc", Name: "Bridged USDC", Symbol: "usdc"}}
+	m, _ = m.Update(coinSearchMsg{query: "usd", coins: coins})
+	assert.Empty(t, m.(model).coinSuggestions, "results for an outdated query are dropped")
+	m, _ = m.Update(coinSearchMsg{query: "usdc", coins: coins})
+	assert.Contains(t, m.View(), "usd-coin")
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	assert.Equal(t, "bridged-usdc", m.(model).chainInputs[2].Value())
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	assert.Equal(t, "usd-coin", m.(model).chainInputs[2].Value())
+}
+
+func TestSaveNewToken_DecimalsMismatch(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://localhost:8545"}}}
+	w := watcher.NewWatcher(nil, chains, config.GlobalConfig{}, "")
+	m := initialModel(w, nil, chains, 0, config.GlobalConfig{}, filepath.Join(t.TempDir(), "config.json"))
+	const usdc = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	fill := func(m model) model {
+		m.addingToken = true
+		m.tokenInputs[0].SetValue("USDC")
+		m.tokenInputs[1].SetValue(usdc)
+		m.tokenInputs[2].SetValue("18")
+		return m
+	}
+	m = fill(m)
+
+	updated, cmd := m.saveNewToken()
+	m = updated.(model)
+	assert.NotNil(t, cmd, "decimals are verified before saving")
+	assert.Empty(t, m.chains[0].Tokens)
+
+	updated, _ = m.Update(tokenDecimalsMsg{address: usdc, entered: 18, meta: models.TokenMetadata{Symbol: "USDC", Decimals: 6}})
+	m = updated.(model)
+	assert.True(t, m.confirmingTokenDecimals)
+	assert.Contains(t, m.View(), "The contract reports 6 decimals, but 18 were entered.")
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = updated.(model)
+	assert.False(t, m.addingToken)
+	assert.Equal(t, 6, m.chains[0].Tokens[0].Decimals)
+
+	// Unverifiable decimals are saved as entered.
+	m.chains[0].Tokens = nil
+	m = fill(m)
+	updated, _ = m.Update(tokenDecimalsMsg{address: usdc, entered: 18, meta: models.TokenMetadata{Decimals: 18, DecimalsDefaulted: true}})
+	m = updated.(model)
+	assert.Equal(t, 18, m.chains[0].Tokens[0].Decimals)
+	assert.Contains(t, m.statusMessage, "could not be verified")
+}
+
+func TestSetupWizard_FirstRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	w := watcher.NewWatcher(nil, nil, config.GlobalConfig{}, path)
+	m := initialModel(w, nil, nil, 0, config.GlobalConfig{}, path)
+	assert.True(t, m.settingUp, "no chains opens the wizard")
+	assert.Contains(t, m.View(), "Welcome to evmbal")
+
+	// Accept the Ethereum defaults, keep the RPC and add an address.
+	for _, msg := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("y")},
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyRunes, Runes: []rune("0x1111111111111111111111111111111111111111")},
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyRunes, Runes: []rune("Cold")},
+	} {
+		updated, _ := m.Update(msg)
+		m = updated.(model)
+	}
+	assert.Equal(t, setupAddress, m.setupStep)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+
+	assert.False(t, m.settingUp)
+	assert.NotNil(t, cmd, "the dashboard starts")
+	addrs, chains, _, _, err := config.LoadConfigFromFile(path)
+	assert.NoError(t, err)
+	if assert.Len(t, chains, 1) {
+		assert.Equal(t, "Ethereum", chains[0].Name)
+		assert.Equal(t, setupDefaultChain.RPCURLs, chains[0].RPCURLs)
+	}
+	if assert.Len(t, addrs, 1) {
+		assert.Equal(t, "Cold", addrs[0].Name)
+	}
+	assert.Len(t, m.accounts, 1)
+	assert.Len(t, w.GetAccounts(), 1)
+}
+
+func TestManageRPCs(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://a.example", "http://b.example"}, DisabledRPCs: []string{"http://b.example"}}}
+	path := filepath.Join(t.TempDir(), "config.json")
+	w := watcher.NewWatcher(nil, chains, config.GlobalConfig{}, "")
+	var m tea.Model = initialModel(w, nil, chains, 0, config.GlobalConfig{}, path)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	key := func(k string) {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+	}
+	rpcs := func() []string { return m.(model).chains[0].RPCURLs }
+
+	key("E")
+	key("r")
+	assert.True(t, m.(model).managingRPCs)
+	assert.Contains(t, m.View(), "Manage RPCs (Eth)")
+
+	key("a")
+	key("not a url")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.True(t, m.(model).addingRPC, "an invalid URL is not saved")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	key("a")
+	key("https://c.example/key")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.False(t, m.(model).addingRPC)
+	assert.Equal(t, []string{"http://a.example", "http://b.example", "https://c.example/key"}, rpcs())
+	assert.Equal(t, 2, m.(model).rpcListIdx)
+
+	key("K")
+	key("K")
+	assert.Equal(t, []string{"https://c.example/key", "http://a.example", "http://b.example"}, rpcs())
+	assert.Equal(t, 0, m.(model).rpcListIdx)
+
+	key("d")
+	key("d")
+	assert.Contains(t, m.(model).statusMessage, "Cannot delete the last enabled RPC")
+	assert.Equal(t, []string{"http://a.example", "http://b.example"}, rpcs())
+	key("j")
+	key("d")
+	assert.Equal(t, []string{"http://a.example"}, rpcs())
+	assert.Empty(t, m.(model).chains[0].DisabledRPCs, "a deleted RPC no longer counts as disabled")
+
+	_, saved, _, _, err := config.LoadConfigFromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"http://a.example"}, saved[0].RPCURLs)
+	assert.Equal(t, []string{"http://a.example"}, w.GetChains()[0].RPCURLs)
+	assert.Len(t, chains[0].RPCURLs, 2, "the caller's chains are not modified")
+
+	key("q")
+	assert.False(t, m.(model).managingRPCs)
+	assert.True(t, m.(model).managingChains)
+}
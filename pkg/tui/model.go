@@ -7,6 +7,7 @@ import (
 
 	"evmbal/pkg/config"
 	"evmbal/pkg/models"
+	"evmbal/pkg/rpc"
 	"evmbal/pkg/watcher"
 
 	"github.com/charmbracelet/bubbles/spinner"
@@ -26,81 +27,164 @@ type uiTickMsg time.Time
 type privacyTimeoutMsg struct{}
 type autoCycleMsg struct{}
 
+// priceStaleThreshold is how old the last price update may be before it is flagged as stale.
+const priceStaleThreshold = 5 * time.Minute
+
 // --- Model ---
 
 type model struct {
-	chains                 []config.ChainConfig
-	activeChainIdx         int
-	prices                 map[string]float64 // Key: CoinGecko ID
-	gasPrice               *big.Int
-	gasTrend               int
-	accounts               []*models.Account
-	activeIdx              int
-	width                  int
-	height                 int
-	loading                bool
-	lastUpdate             time.Time
-	spinner                spinner.Model
-	statusMessage          string
-	showSummary            bool
-	addressInputs          []textinput.Model
-	adding                 bool
-	configPath             string
-	managingChains         bool
-	chainListIdx           int
-	addingChain            bool
-	chainInputs            []textinput.Model
-	managingTokens         bool
-	tokenListIdx           int
-	addingToken            bool
-	tokenInputs            []textinput.Model
-	selectedChainForTokens int
-	portfolioHistory       []float64
-	editingAddress         bool
-	editAddressInput       textinput.Model
-	rpcCooldowns           map[string]time.Time
-	showNetworkStatus      bool
-	rpcLatencies           map[string]time.Duration
-	rpcLatencyHistory      map[string][]time.Duration
-	showDetail             bool
-	viewport               viewport.Model
-	restoringBackup        bool
-	showHelp               bool
-	exportingConfig        bool
-	exportInput            textinput.Model
-	compactMode            bool
-	showSummaryGraph       bool
-	summarySortCol         int // 0: Name, 1: Value, 2: Balance
-	summarySortDesc        bool
-	gasPriceHistory        []models.GasPricePoint
-	showGasTracker         bool
-	gasTrackerRangeIndex   int // 0: 30m, 1: 1h, 2: 6h, 3: 24h
-	privacyMode            bool
-	lastInteraction        time.Time
-	config                 config.GlobalConfig
-	editingGlobalConfig    bool
-	globalConfigInputs     []textinput.Model
-	showTxList             bool
-	txListIdx              int
-	showTxDetail           bool
-	txFilter               string // "all", "in", "out"
-	nextAutoCycleTime      time.Time
-	watcher                *watcher.Watcher
+	chains                  []config.ChainConfig
+	activeChainIdx          int
+	prices                  map[string]float64 // Key: CoinGecko ID
+	priceTrends             map[string]int     // Key: CoinGecko ID
+	priceChanges24h         map[string]float64 // Key: CoinGecko ID
+	pricesUpdatedAt         time.Time
+	gasPrices               map[string]*big.Int // Key: Chain Name
+	gasTrends               map[string]int      // Key: Chain Name
+	accounts                []*models.Account
+	activeIdx               int
+	width                   int
+	height                  int
+	loading                 bool
+	lastUpdate              time.Time
+	chainLoading            map[string]bool      // Key: Chain Name
+	chainLastUpdate         map[string]time.Time // Key: Chain Name
+	refreshProgress         map[string]int       // Key: Chain Name; 0 fetching, 1 done, -1 failed. Non-nil during a manual refresh
+	spinner                 spinner.Model
+	statusMessage           string
+	showSummary             bool
+	addressInputs           []textinput.Model
+	addressFocusIdx         int
+	addressCheck            addressCheck // Live validation of addressInputs[0]
+	confirmingTokenDecimals bool
+	tokenDecimalsOnChain    int
+	coinSuggestions         []rpc.CoinSuggestion
+	coinSuggestionsQuery    string // What coinSuggestions were searched for
+	coinSuggestionIdx       int    // Picked suggestion, -1 for none
+	adding                  bool
+	configPath              string
+	managingChains          bool
+	chainListIdx            int
+	managingRPCs            bool // RPC list of the chain at chainListIdx
+	rpcListIdx              int
+	addingRPC               bool
+	rpcInput                textinput.Model
+	addingChain             bool
+	chainInputs             []textinput.Model
+	managingTokens          bool
+	tokenListIdx            int
+	addingToken             bool
+	tokenInputs             []textinput.Model
+	selectedChainForTokens  int
+	importingTokens         bool
+	tokenImportInput        textinput.Model
+	reviewingTokens         bool
+	pendingTokens           []config.TokenConfig
+	pendingTokensChainIdx   int
+	portfolioHistory        []float64
+	portfolioSampledAt      time.Time
+	dashboardMode           bool // Set by --dashboard; full-screen totals for unattended displays
+	editingAddress          bool
+	editAddressInput        textinput.Model
+	editNoteInput           textinput.Model
+	editFocusIdx            int
+	rpcCooldowns            map[string]time.Time
+	showNetworkStatus       bool
+	networkStatusIdx        int                  // Highlighted RPC in the network status view
+	rpcProbing              string               // RPC being tested from the network status view
+	rpcProbe                *models.RPCProbeData // Test result shown over the network status view
+	rpcLatencies            map[string]time.Duration
+	rpcLatencyHistory       map[string][]time.Duration
+	rpcLagging              map[string]models.RPCLatencyData // Key: RPC URL; RPCs whose head is stale
+	showDetail              bool
+	detailAssetIdx          int // Highlighted row of detailAssets
+	showTokenHistory        bool
+	tokenHistoryUSD         bool
+	assetHistory            map[string][]assetHistoryPoint // Key: assetHistoryKey
+	viewport                viewport.Model
+	restoringBackup         bool
+	confirmingRestore       bool
+	restoreDiff             config.ConfigDiff // Current config -> selected backup
+	restoreDiffErr          error
+	backups                 []config.BackupInfo // Newest first
+	backupListIdx           int
+	showHelp                bool
+	exportingConfig         bool
+	exportInput             textinput.Model
+	exportPassInput         textinput.Model
+	exportConfirmInput      textinput.Model
+	exportFocusIdx          int
+	compactMode             bool
+	showSummaryGraph        bool
+	summarySortCol          int // 0: Name, 1: Value, 2: Balance
+	summarySortDesc         bool
+	summarySorted           bool                              // Rows keep the configured order until a sort key is pressed
+	showBelowMinValue       bool                              // Summary shows accounts under min_display_value_usd
+	gasPriceHistory         map[string][]models.GasPricePoint // Key: Chain Name
+	showGasTracker          bool
+	gasTrackerRangeIndex    int // 0: 30m, 1: 1h, 2: 6h, 3: 24h
+	gasShowUSD              bool
+	privacyMode             bool
+	updatesPaused           bool // Watcher events are held in pausedEvents instead of applied
+	pausedEvents            []watcher.Event
+	lastInteraction         time.Time
+	config                  config.GlobalConfig
+	editingGlobalConfig     bool
+	globalConfigInputs      []textinput.Model
+	showTxList              bool
+	txListIdx               int
+	showTxDetail            bool
+	txFilter                string         // "all", "in", "out"
+	txPages                 map[string]int // Last explorer page loaded, by txPageKey
+	scanningTxRange         bool
+	txScanInput             textinput.Model
+	nextAutoCycleTime       time.Time
+	showQR                  bool
+	watcher                 *watcher.Watcher
+	sub                     watcher.Subscriber
+	lastEventAt             map[watcher.EventType]time.Time
+	debugEnabled            bool // Set by --debug; gates the ctrl+d overlay
+	showDebug               bool
+	settingUp               bool // First-run wizard, shown while there are no chains
+	setupStep               setupStep
+}
+
+func newAccount(address, name string) *models.Account {
+	return &models.Account{
+		Address:       address,
+		Name:          name,
+		Balances:      make(map[string]*big.Float),
+		TokenBalances: make(map[string]map[string]*big.Float),
+		Balances24h:   make(map[string]*big.Float),
+		Errors:        make(map[string]error),
+		Fetched:       make(map[string]bool),
+		Baselines:     make(map[string]*big.Float),
+	}
+}
+
+// accountFromConfig builds an account with its pinned baselines, or returns nil if
+// the address is empty.
+func accountFromConfig(a config.AddressConfig) *models.Account {
+	clean := strings.TrimSpace(a.Address)
+	if clean == "" {
+		return nil
+	}
+	acc := newAccount(clean, a.Name)
+	acc.Note = a.Note
+	acc.ExcludeFromTotal = a.ExcludeFromTotal
+	for chainName, v := range a.BaselineBalances {
+		if f, ok := new(big.Float).SetString(v); ok {
+			acc.Baselines[chainName] = f
+		}
+	}
+	return acc
 }
 
 func initialModel(w *watcher.Watcher, addresses []config.AddressConfig, chains []config.ChainConfig, activeChainIdx int, globalCfg config.GlobalConfig, configPath string) model {
 	var accounts []*models.Account
 	for _, a := range addresses {
-		clean := strings.TrimSpace(a.Address)
-		if clean != "" {
-			accounts = append(accounts, &models.Account{
-				Address:       clean,
-				Name:          a.Name,
-				Balances:      make(map[string]*big.Float),
-				TokenBalances: make(map[string]map[string]*big.Float),
-				Balances24h:   make(map[string]*big.Float),
-				Errors:        make(map[string]error),
-			})
+		if acc := accountFromConfig(a); acc != nil {
+			accounts = append(accounts, acc)
 		}
 	}
 
@@ -137,14 +221,43 @@ func initialModel(w *watcher.Watcher, addresses []config.AddressConfig, chains [
 	tis[2].Placeholder = "Decimals (e.g. 6)"
 	tis[3].Placeholder = "CoinGecko ID (e.g. usd-coin)"
 
+	importTi := textinput.New()
+	importTi.Placeholder = `["0x...", "0x..."], 0x..., 0x... or https://...`
+	importTi.Width = 60
+	importTi.CharLimit = 0
+
+	rpcTi := textinput.New()
+	rpcTi.Placeholder = "https://..."
+	rpcTi.Width = 60
+	rpcTi.CharLimit = 0
+
 	editTi := textinput.New()
 	editTi.Placeholder = "Tag/Name"
 	editTi.Width = 40
 
+	editNoteTi := textinput.New()
+	editNoteTi.Placeholder = "Note (e.g. Ledger, seed in safe)"
+	editNoteTi.Width = 50
+	editNoteTi.CharLimit = 200
+
+	scanTi := textinput.New()
+	scanTi.Placeholder = "Start block (e.g. 19000000)"
+	scanTi.Width = 30
+
 	exportTi := textinput.New()
 	exportTi.Placeholder = "/path/to/config.json"
 	exportTi.Width = 50
 
+	exportPassTi := textinput.New()
+	exportPassTi.Placeholder = "leave empty for plain JSON"
+	exportPassTi.Width = 50
+	exportPassTi.EchoMode = textinput.EchoPassword
+
+	exportConfirmTi := textinput.New()
+	exportConfirmTi.Placeholder = "repeat the passphrase"
+	exportConfirmTi.Width = 50
+	exportConfirmTi.EchoMode = textinput.EchoPassword
+
 	gcis := make([]textinput.Model, 5)
 	for i := range gcis {
 		gcis[i] = textinput.New()
@@ -153,32 +266,50 @@ func initialModel(w *watcher.Watcher, addresses []config.AddressConfig, chains [
 
 	vp := viewport.New(0, 0)
 
+	chainLoading := make(map[string]bool)
+	for _, c := range chains {
+		chainLoading[c.Name] = c.IsEnabled()
+	}
+
 	return model{
 		accounts:             accounts,
 		chains:               chains,
 		activeChainIdx:       activeChainIdx,
 		loading:              true,
+		chainLoading:         chainLoading,
+		chainLastUpdate:      make(map[string]time.Time),
 		spinner:              s,
 		addressInputs:        ais,
 		configPath:           configPath,
 		chainInputs:          cis,
 		tokenInputs:          tis,
+		tokenImportInput:     importTi,
+		rpcInput:             rpcTi,
 		prices:               make(map[string]float64),
+		priceTrends:          make(map[string]int),
+		priceChanges24h:      make(map[string]float64),
 		editAddressInput:     editTi,
+		editNoteInput:        editNoteTi,
 		rpcCooldowns:         make(map[string]time.Time),
 		rpcLatencies:         make(map[string]time.Duration),
 		rpcLatencyHistory:    make(map[string][]time.Duration),
+		rpcLagging:           make(map[string]models.RPCLatencyData),
 		showDetail:           false,
+		assetHistory:         make(map[string][]assetHistoryPoint),
 		viewport:             vp,
 		restoringBackup:      false,
 		showHelp:             false,
 		exportingConfig:      false,
 		exportInput:          exportTi,
+		exportPassInput:      exportPassTi,
+		exportConfirmInput:   exportConfirmTi,
 		compactMode:          true,
 		showSummaryGraph:     false,
 		summarySortCol:       1,
 		summarySortDesc:      true,
-		gasPriceHistory:      make([]models.GasPricePoint, 0),
+		gasPrices:            make(map[string]*big.Int),
+		gasTrends:            make(map[string]int),
+		gasPriceHistory:      make(map[string][]models.GasPricePoint),
 		showGasTracker:       false,
 		gasTrackerRangeIndex: 0,
 		privacyMode:          false,
@@ -189,32 +320,45 @@ func initialModel(w *watcher.Watcher, addresses []config.AddressConfig, chains [
 		txListIdx:            0,
 		showTxDetail:         false,
 		txFilter:             "all",
+		txPages:              make(map[string]int),
+		txScanInput:          scanTi,
 		nextAutoCycleTime:    time.Now(),
 		watcher:              w,
+		sub:                  w.Subscribe(),
+		lastEventAt:          make(map[watcher.EventType]time.Time),
+		settingUp:            len(chains) == 0,
 	}
 }
 
 func (m model) Init() tea.Cmd {
+	if m.settingUp {
+		return nil
+	}
 	var cmds []tea.Cmd
 
 	// Subscribe to watcher events
-	cmds = append(cmds, listenForWatcher(m.watcher.Subscribe()))
+	cmds = append(cmds, listenForWatcher(m.sub))
 
 	m.spinner.Tick()
 	cmds = append(cmds, m.spinner.Tick)
 
-	if !m.privacyMode && m.config.PrivacyTimeoutSeconds > 0 {
+	// An unattended dashboard has no interaction to reset the timeouts.
+	if !m.privacyMode && m.config.PrivacyTimeoutSeconds > 0 && !m.dashboardMode {
 		cmds = append(cmds, tea.Tick(time.Duration(m.config.PrivacyTimeoutSeconds)*time.Second, func(t time.Time) tea.Msg {
 			return privacyTimeoutMsg{}
 		}))
 	}
 
-	if m.config.AutoCycleEnabled && m.config.AutoCycleIntervalSeconds > 0 {
+	if m.config.AutoCycleEnabled && m.config.AutoCycleIntervalSeconds > 0 && !m.dashboardMode {
 		interval := time.Duration(m.config.AutoCycleIntervalSeconds) * time.Second
 		cmds = append(cmds, tea.Tick(interval, func(t time.Time) tea.Msg {
 			return autoCycleMsg{}
 		}))
 	}
+	if m.statusMessage != "" {
+		// Startup warnings, e.g. unknown config keys.
+		cmds = append(cmds, clearStatusAfter(10*time.Second))
+	}
 	cmds = append(cmds, tea.Tick(time.Second, func(t time.Time) tea.Msg { return uiTickMsg(t) }))
 	return tea.Batch(cmds...)
 }
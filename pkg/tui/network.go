@@ -0,0 +1,124 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"evmbal/pkg/config"
+	"evmbal/pkg/models"
+	"evmbal/pkg/rpc"
+	"evmbal/pkg/utils"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func probeRPCCmd(rpcURL string, timeout time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		return rpc.ProbeRPC(rpcURL, timeout)
+	}
+}
+
+// updateNetworkStatus handles keys in the network status view. While a probe
+// result is shown, any key closes it.
+func (m model) updateNetworkStatus(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.rpcProbe != nil {
+		m.rpcProbe = nil
+		return m, nil
+	}
+	rpcs := m.chains[m.activeChainIdx].RPCURLs
+	switch msg.String() {
+	case "N", "q", "esc", "ctrl+c":
+		m.showNetworkStatus = false
+	case "up", "k":
+		if m.networkStatusIdx > 0 {
+			m.networkStatusIdx--
+		}
+	case "down", "j":
+		if m.networkStatusIdx < len(rpcs)-1 {
+			m.networkStatusIdx++
+		}
+	case "r":
+		m.watcher.Refresh()
+	case "R":
+		m.rpcCooldowns = make(map[string]time.Time)
+	case "d":
+		if m.networkStatusIdx < len(rpcs) {
+			return m, m.toggleRPCDisabled(rpcs[m.networkStatusIdx])
+		}
+	case "t", "enter":
+		if m.networkStatusIdx >= len(rpcs) || m.rpcProbing != "" {
+			return m, nil
+		}
+		m.rpcProbing = rpcs[m.networkStatusIdx]
+		return m, probeRPCCmd(m.rpcProbing, m.chains[m.activeChainIdx].Timeout(rpc.DefaultRequestTimeout))
+	}
+	return m, nil
+}
+
+// toggleRPCDisabled switches rpcURL of the active chain off or back on and
+// saves the change, so the watcher routes around it across restarts.
+func (m *model) toggleRPCDisabled(rpcURL string) tea.Cmd {
+	m.chains = append([]config.ChainConfig(nil), m.chains...)
+	chain := &m.chains[m.activeChainIdx]
+	state := "Enabled"
+	if chain.RPCDisabled(rpcURL) {
+		chain.DisabledRPCs = slices.DeleteFunc(slices.Clone(chain.DisabledRPCs), func(u string) bool { return u == rpcURL })
+	} else {
+		if len(chain.ActiveRPCURLs()) <= 1 {
+			m.statusMessage = fmt.Sprintf("Cannot disable the last RPC of %s", chain.Name)
+			return clearStatusAfter(2 * time.Second)
+		}
+		chain.DisabledRPCs = append(slices.Clone(chain.DisabledRPCs), rpcURL)
+		state = "Disabled"
+	}
+	if err := m.applyChainChanges(); err != nil {
+		m.statusMessage = fmt.Sprintf("Failed to save config: %v", err)
+	} else {
+		m.statusMessage = fmt.Sprintf("%s %s", state, utils.TruncateString(rpcURL, 40))
+	}
+	return clearStatusAfter(2 * time.Second)
+}
+
+// applyRPCProbe shows a probe result, unless the view was left meanwhile.
+func (m *model) applyRPCProbe(res models.RPCProbeData) {
+	if res.RPCURL != m.rpcProbing {
+		return
+	}
+	m.rpcProbing = ""
+	if m.showNetworkStatus {
+		m.rpcProbe = &res
+	}
+}
+
+// viewRPCProbe lists the result of a probe for the popup in the network status view.
+func (m model) viewRPCProbe() string {
+	p := m.rpcProbe
+	header := titleStyle.Render("RPC Test")
+	lines := []string{header, "", subtleStyle.Render(p.RPCURL), ""}
+	if p.Err != nil {
+		lines = append(lines, errStyle.Render(fmt.Sprintf("Error: %v", p.Err)))
+	}
+	if p.ChainID != 0 {
+		chainID := fmt.Sprintf("Chain ID:     %d", p.ChainID)
+		if want := m.chains[m.activeChainIdx].ChainID; want != 0 && want != p.ChainID {
+			chainID += errStyle.Render(fmt.Sprintf(" (expected %d)", want))
+		}
+		lines = append(lines, chainID)
+	}
+	if p.Err == nil {
+		lines = append(lines,
+			fmt.Sprintf("Block:        %d", p.BlockNumber),
+			"Latency:      "+latencyStyle(p.Latency).Render(p.Latency.Round(time.Millisecond).String()))
+		switch {
+		case p.SyncErr != nil:
+			lines = append(lines, subtleStyle.Render(fmt.Sprintf("Sync status:  unknown (%v)", p.SyncErr)))
+		case p.Syncing:
+			lines = append(lines, warnStyle.Render(fmt.Sprintf("Sync status:  syncing (%d of %d)", p.CurrentBlock, p.HighestBlock)))
+		default:
+			lines = append(lines, "Sync status:  "+infoStyle.Render("synced"))
+		}
+	}
+	return boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"evmbal/pkg/watcher"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxPausedEvents bounds the events held while updates are paused; the oldest
+// are dropped past it.
+const maxPausedEvents = 1000
+
+// bufferPausedEvent holds a watcher event received while updates are paused so
+// it can be applied on resume. The watcher keeps polling in the meantime.
+func (m *model) bufferPausedEvent(msg watcher.Event) {
+	m.pausedEvents = append(m.pausedEvents, msg)
+	if len(m.pausedEvents) > maxPausedEvents {
+		m.pausedEvents = m.pausedEvents[len(m.pausedEvents)-maxPausedEvents:]
+	}
+}
+
+// togglePauseUpdates freezes the displayed numbers, or resumes by applying the
+// events that arrived while they were frozen.
+func (m *model) togglePauseUpdates() []tea.Cmd {
+	if !m.updatesPaused {
+		m.updatesPaused = true
+		m.statusMessage = "Updates paused; press space to resume"
+		return []tea.Cmd{clearStatusAfter(3 * time.Second)}
+	}
+	m.updatesPaused = false
+	var cmds []tea.Cmd
+	for _, ev := range m.pausedEvents {
+		cmds = append(cmds, m.applyWatcherEvent(ev)...)
+	}
+	m.statusMessage = fmt.Sprintf("Updates resumed (%d applied)", len(m.pausedEvents))
+	m.pausedEvents = nil
+	return append(cmds, clearStatusAfter(2*time.Second))
+}
@@ -0,0 +1,173 @@
+package tui
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"evmbal/pkg/config"
+	"evmbal/pkg/rpc"
+	"evmbal/pkg/utils"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// updateManagingRPCs handles keys in the RPC list of the chain selected in
+// Manage Chains. The watcher tries healthy, fast RPCs first and keeps the list
+// order among equals, so moving an RPC up makes it the preferred fallback.
+func (m model) updateManagingRPCs(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	rpcs := m.chains[m.chainListIdx].RPCURLs
+	switch msg.String() {
+	case "q", "esc":
+		m.managingRPCs = false
+	case "up", "k":
+		if m.rpcListIdx > 0 {
+			m.rpcListIdx--
+		}
+	case "down", "j":
+		if m.rpcListIdx < len(rpcs)-1 {
+			m.rpcListIdx++
+		}
+	case "K", "shift+up":
+		if m.rpcListIdx > 0 {
+			return m, m.moveRPC(-1)
+		}
+	case "J", "shift+down":
+		if m.rpcListIdx < len(rpcs)-1 {
+			return m, m.moveRPC(1)
+		}
+	case "a":
+		m.addingRPC = true
+		m.rpcInput.Reset()
+		m.rpcInput.Focus()
+		return m, textinput.Blink
+	case "d":
+		if len(rpcs) == 0 {
+			return m, nil
+		}
+		return m, m.deleteRPC(rpcs[m.rpcListIdx])
+	}
+	return m, nil
+}
+
+// editChainRPCs applies edit to a copy of the chain selected in Manage Chains
+// and saves the config. It returns false if saving failed.
+func (m *model) editChainRPCs(edit func(chain *config.ChainConfig)) bool {
+	m.chains = append([]config.ChainConfig(nil), m.chains...)
+	chain := &m.chains[m.chainListIdx]
+	chain.RPCURLs = slices.Clone(chain.RPCURLs)
+	edit(chain)
+	if err := m.applyChainChanges(); err != nil {
+		m.statusMessage = fmt.Sprintf("Failed to save config: %v", err)
+		return false
+	}
+	return true
+}
+
+// moveRPC swaps the highlighted RPC with its neighbour delta rows away.
+func (m *model) moveRPC(delta int) tea.Cmd {
+	i, j := m.rpcListIdx, m.rpcListIdx+delta
+	if m.editChainRPCs(func(c *config.ChainConfig) { c.RPCURLs[i], c.RPCURLs[j] = c.RPCURLs[j], c.RPCURLs[i] }) {
+		m.rpcListIdx = j
+		m.statusMessage = fmt.Sprintf("Moved %s to position %d", rpcHost(m.chains[m.chainListIdx].RPCURLs[j]), j+1)
+	}
+	return clearStatusAfter(2 * time.Second)
+}
+
+// deleteRPC removes rpcURL from the chain, refusing to remove its last enabled RPC.
+func (m *model) deleteRPC(rpcURL string) tea.Cmd {
+	chain := m.chains[m.chainListIdx]
+	if !chain.RPCDisabled(rpcURL) && len(chain.ActiveRPCURLs()) <= 1 {
+		m.statusMessage = fmt.Sprintf("Cannot delete the last enabled RPC of %s", chain.Name)
+		return clearStatusAfter(2 * time.Second)
+	}
+	isURL := func(u string) bool { return u == rpcURL }
+	if m.editChainRPCs(func(c *config.ChainConfig) {
+		c.RPCURLs = slices.DeleteFunc(c.RPCURLs, isURL)
+		c.DisabledRPCs = slices.DeleteFunc(slices.Clone(c.DisabledRPCs), isURL)
+	}) {
+		m.statusMessage = fmt.Sprintf("Removed %s", rpcHost(rpcURL))
+	}
+	if n := len(m.chains[m.chainListIdx].RPCURLs); m.rpcListIdx >= n {
+		m.rpcListIdx = n - 1
+	}
+	return clearStatusAfter(2 * time.Second)
+}
+
+func (m model) updateAddingRPC(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.addingRPC = false
+		m.rpcInput.Blur()
+		return m, nil
+	case "enter":
+		rpcURL := strings.TrimSpace(m.rpcInput.Value())
+		chain := m.chains[m.chainListIdx]
+		u, err := url.Parse(rpcURL)
+		switch {
+		case err != nil || u.Host == "" || !slices.Contains([]string{"http", "https", "ws", "wss"}, u.Scheme):
+			m.statusMessage = "Enter an http(s) or ws(s) URL"
+		case slices.Contains(chain.RPCURLs, rpcURL):
+			m.statusMessage = "RPC is already configured on this chain"
+		default:
+			m.addingRPC = false
+			m.rpcInput.Blur()
+			if m.editChainRPCs(func(c *config.ChainConfig) { c.RPCURLs = append(c.RPCURLs, rpcURL) }) {
+				m.rpcListIdx = len(m.chains[m.chainListIdx].RPCURLs) - 1
+				m.statusMessage = fmt.Sprintf("Added %s", rpcHost(rpcURL))
+				if len(chain.RPCHeaders) > 0 {
+					// The chain's headers are registered per URL.
+					rpc.SetRPCHeaders(m.chains)
+				}
+			}
+		}
+		return m, clearStatusAfter(2 * time.Second)
+	}
+	var cmd tea.Cmd
+	m.rpcInput, cmd = m.rpcInput.Update(msg)
+	return m, cmd
+}
+
+// viewManagingRPCs lists the selected chain's RPCs in configured order.
+func (m model) viewManagingRPCs() string {
+	chain := m.chains[m.chainListIdx]
+	header := titleStyle.Render(fmt.Sprintf("Manage RPCs (%s)", chain.Name))
+	rows := ""
+	for i, u := range chain.RPCURLs {
+		cursor := "  "
+		if i == m.rpcListIdx {
+			cursor = "> "
+		}
+		row := fmt.Sprintf("%s%d. %s", cursor, i+1, utils.TruncateString(u, 60))
+		if chain.RPCDisabled(u) {
+			row = subtleStyle.Render(row + " (disabled)")
+		}
+		rows += row + "\n"
+	}
+	content := boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, header, "\n", rows))
+	footer := subtleStyle.Render("a: add • d: delete • K/J: move up/down • q: back")
+	if m.statusMessage != "" {
+		footer = lipgloss.JoinVertical(lipgloss.Center, infoStyle.Render(m.statusMessage), footer)
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, lipgloss.JoinVertical(lipgloss.Center, content, "\n", footer))
+}
+
+func (m model) viewAddingRPC() string {
+	chain := m.chains[m.chainListIdx]
+	view := boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render(fmt.Sprintf("Add RPC (%s)", chain.Name)),
+		"\n",
+		"URL of the new RPC, added at the end of the list:",
+		m.rpcInput.View(),
+		"\n",
+		subtleStyle.Render("Enter to save • Esc to cancel"),
+	))
+	if m.statusMessage != "" {
+		view = lipgloss.JoinVertical(lipgloss.Center, view, "\n", infoStyle.Render(m.statusMessage))
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, view)
+}
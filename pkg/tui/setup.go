@@ -0,0 +1,236 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"evmbal/pkg/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// setupStep is a page of the first-run wizard, shown when the config has no chains.
+type setupStep int
+
+const (
+	setupWelcome setupStep = iota // Offer the Ethereum defaults
+	setupChain                    // Chain form, including the RPC URLs
+	setupAddress                  // First address to watch
+)
+
+// setupDefaultChain prefills the chain form when the defaults are accepted.
+var setupDefaultChain = config.DefaultChains()[0]
+
+// updateSetup handles the first-run wizard. The watcher subscription and tickers
+// only start once it has saved a config, see finishSetup.
+func (m model) updateSetup(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.setSize(msg.Width, msg.Height)
+		return m, nil
+	case clearStatusMsg:
+		m.statusMessage = ""
+		return m, nil
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		switch m.setupStep {
+		case setupWelcome:
+			return m.updateSetupWelcome(msg)
+		case setupChain:
+			return m.updateSetupChain(msg)
+		case setupAddress:
+			return m.updateSetupAddress(msg)
+		}
+	}
+	return m, nil
+}
+
+func (m model) updateSetupWelcome(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc":
+		return m, tea.Quit
+	case "y", "enter":
+		c := setupDefaultChain
+		m.chainInputs[0].SetValue(c.Name)
+		m.chainInputs[1].SetValue(c.Symbol)
+		m.chainInputs[2].SetValue(c.CoinGeckoID)
+		m.chainInputs[3].SetValue(strings.Join(c.RPCURLs, ", "))
+		m.chainInputs[4].SetValue(c.ExplorerURL)
+		// Most people only want to swap in their own RPC.
+		m.setupStep = setupChain
+		focusInputs(m.chainInputs, 3)
+	case "n":
+		resetInputs(m.chainInputs)
+		m.setupStep = setupChain
+		focusInputs(m.chainInputs, 0)
+	}
+	return m, nil
+}
+
+func (m model) updateSetupChain(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	focusIdx := focusedInput(m.chainInputs)
+	switch msg.String() {
+	case "esc":
+		m.setupStep = setupWelcome
+		resetInputs(m.chainInputs)
+		return m, nil
+	case "tab", "down":
+		focusInputs(m.chainInputs, focusIdx+1)
+		return m, nil
+	case "shift+tab", "up":
+		focusInputs(m.chainInputs, focusIdx-1)
+		return m, nil
+	case "enter":
+		if focusIdx < len(m.chainInputs)-1 {
+			focusInputs(m.chainInputs, focusIdx+1)
+			return m, nil
+		}
+		chain := m.setupChainConfig()
+		switch {
+		case chain.Name == "":
+			m.statusMessage = "Chain name is required"
+		case len(chain.RPCURLs) == 0:
+			m.statusMessage = "At least one RPC URL is required"
+		default:
+			m.setupStep = setupAddress
+			m.resetAddressInputs()
+			m.focusAddressInput(0)
+			return m, nil
+		}
+		return m, clearStatusAfter(2 * time.Second)
+	}
+	var cmd tea.Cmd
+	m.chainInputs[focusIdx], cmd = m.chainInputs[focusIdx].Update(msg)
+	return m, cmd
+}
+
+func (m model) updateSetupAddress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.setupStep = setupChain
+		m.resetAddressInputs()
+		focusInputs(m.chainInputs, 0)
+		return m, nil
+	case "tab", "down":
+		m.focusAddressInput(m.addressFocusIdx + 1)
+		return m, nil
+	case "shift+tab", "up":
+		m.focusAddressInput(m.addressFocusIdx - 1)
+		return m, nil
+	case "enter":
+		if m.addressFocusIdx < len(m.addressInputs)-1 && m.addressCheck != addressList {
+			m.focusAddressInput(m.addressFocusIdx + 1)
+			return m, nil
+		}
+		if m.addressCheck == addressInvalid {
+			m.statusMessage = "Invalid address"
+			return m, clearStatusAfter(2 * time.Second)
+		}
+		return m.finishSetup()
+	}
+	var cmd tea.Cmd
+	m.addressInputs[m.addressFocusIdx], cmd = m.addressInputs[m.addressFocusIdx].Update(msg)
+	if m.addressFocusIdx == 0 {
+		m.addressCheck = m.checkAddress(m.addressInputs[0].Value())
+	}
+	return m, cmd
+}
+
+// setupChainConfig builds the chain entered in the wizard.
+func (m model) setupChainConfig() config.ChainConfig {
+	var rpcURLs []string
+	for _, u := range strings.Split(m.chainInputs[3].Value(), ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			rpcURLs = append(rpcURLs, u)
+		}
+	}
+	return config.ChainConfig{
+		Name:        strings.TrimSpace(m.chainInputs[0].Value()),
+		Symbol:      strings.TrimSpace(m.chainInputs[1].Value()),
+		CoinGeckoID: strings.TrimSpace(m.chainInputs[2].Value()),
+		RPCURLs:     rpcURLs,
+		ExplorerURL: strings.TrimSpace(m.chainInputs[4].Value()),
+	}
+}
+
+// finishSetup saves the wizard's chain and addresses, loads them like any other
+// config and starts the dashboard. The address may be left empty.
+func (m model) finishSetup() (tea.Model, tea.Cmd) {
+	var addrs []config.AddressConfig
+	input := strings.TrimSpace(m.addressInputs[0].Value())
+	if m.addressCheck == addressList {
+		list, _ := m.parseAddressList(input)
+		for _, a := range list {
+			addrs = append(addrs, config.AddressConfig{Address: a})
+		}
+	} else if input != "" {
+		addrs = append(addrs, config.AddressConfig{Address: input, Name: strings.TrimSpace(m.addressInputs[1].Value())})
+	}
+
+	chains := []config.ChainConfig{m.setupChainConfig()}
+	if err := config.SaveConfig(addrs, chains, 0, m.config, m.configPath); err != nil {
+		m.statusMessage = fmt.Sprintf("Failed to save config: %v", err)
+		return m, clearStatusAfter(3 * time.Second)
+	}
+	if err := m.reloadConfig(); err != nil {
+		m.statusMessage = fmt.Sprintf("Failed to load config: %v", err)
+		return m, clearStatusAfter(3 * time.Second)
+	}
+	for _, c := range m.chains {
+		m.chainLoading[c.Name] = c.IsEnabled()
+	}
+	m.settingUp = false
+	resetInputs(m.chainInputs)
+	m.resetAddressInputs()
+	m.statusMessage = fmt.Sprintf("Config saved to %s", m.configPath)
+	if len(addrs) == 0 {
+		m.statusMessage += " • press a to add an address"
+	}
+	return m, tea.Batch(m.Init(), clearStatusAfter(5*time.Second))
+}
+
+func (m model) viewSetup() string {
+	var title, body, help string
+	switch m.setupStep {
+	case setupWelcome:
+		title = "Welcome to evmbal"
+		body = lipgloss.JoinVertical(lipgloss.Left,
+			"No chains are configured yet. This will set up a first chain",
+			"and address and save them to:",
+			subtleStyle.Render(m.configPath),
+			"\n",
+			fmt.Sprintf("Start with %s defaults (%s, public RPCs)?", setupDefaultChain.Name, setupDefaultChain.Symbol),
+		)
+		help = "y/enter: use defaults • n: enter a chain • q: quit"
+	case setupChain:
+		title = "Setup 1/2: Chain"
+		labels := []string{"Name", "Symbol", "CoinGecko ID", "RPC URLs", "Explorer URL"}
+		var inputs []string
+		for i, label := range labels {
+			inputs = append(inputs, fmt.Sprintf("%-15s %s", label, m.chainInputs[i].View()))
+		}
+		body = lipgloss.JoinVertical(lipgloss.Left,
+			strings.Join(inputs, "\n"),
+			"\n",
+			subtleStyle.Render("Public RPCs work, but your own endpoint is faster and more reliable."),
+		)
+		help = "Enter to next • Esc to go back"
+	case setupAddress:
+		title = "Setup 2/2: Address"
+		body = lipgloss.JoinVertical(lipgloss.Left,
+			fmt.Sprintf("%-8s %s %s", "Address", m.addressInputs[0].View(), m.addressCheckView()),
+			fmt.Sprintf("%-8s %s", "Name", m.addressInputs[1].View()),
+		)
+		help = "Enter to save and start (leave empty to skip) • Esc to go back"
+	}
+
+	content := boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, titleStyle.Render(title), "\n", body, "\n", subtleStyle.Render(help)))
+	if m.statusMessage != "" {
+		content = lipgloss.JoinVertical(lipgloss.Center, content, errStyle.Render(m.statusMessage))
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"evmbal/pkg/config"
 	"evmbal/pkg/watcher"
@@ -10,11 +11,18 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-func Start(w *watcher.Watcher, addresses []config.AddressConfig, chains []config.ChainConfig, activeChainIdx int, globalCfg config.GlobalConfig, configPath, version string) {
+func Start(w *watcher.Watcher, addresses []config.AddressConfig, chains []config.ChainConfig, activeChainIdx int, globalCfg config.GlobalConfig, configPath, version string, debug, dashboard bool) {
 	Version = version
+	m := initialModel(w, addresses, chains, activeChainIdx, globalCfg, configPath)
+	m.debugEnabled = debug
+	m.dashboardMode = dashboard
+	if unknown, _ := config.UnknownFieldsInFile(configPath); len(unknown) > 0 {
+		m.statusMessage = fmt.Sprintf("Unknown config keys ignored (misspelled?): %s", strings.Join(unknown, ", "))
+	}
 	p := tea.NewProgram(
-		initialModel(w, addresses, chains, activeChainIdx, globalCfg, configPath),
+		m,
 		tea.WithAltScreen(),
+		tea.WithReportFocus(),
 	)
 
 	if _, err := p.Run(); err != nil {
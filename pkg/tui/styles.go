@@ -1,6 +1,12 @@
 package tui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"math/big"
+
+	"evmbal/pkg/config"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 // --- Styles ---
 var (
@@ -12,6 +18,7 @@ var (
 			Bold(true)
 	infoStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#04B575"))
 	errStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000"))
+	warnStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#E5C07B"))
 	boxStyle  = lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
 			BorderForeground(lipgloss.Color("#874BFD")).
@@ -21,3 +28,35 @@ var (
 				Bold(true).
 				Padding(0, 1)
 )
+
+// gasLevelStyle colors a gas price (Gwei) on chain green, amber or red by the
+// chain's gas_thresholds.
+func gasLevelStyle(chain config.ChainConfig, gwei float64) lipgloss.Style {
+	low, high := chain.GasLevels()
+	switch {
+	case gwei < low:
+		return infoStyle
+	case gwei < high:
+		return warnStyle
+	default:
+		return errStyle
+	}
+}
+
+var highValueStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD700")).Bold(true)
+
+// valueStyle picks the style for a holding worth usd: highlighted from
+// high_value_usd up, dimmed below dust_value_usd, plain otherwise. The value is
+// compared as a big.Float so huge balances are not squashed into float64 first.
+func (m model) valueStyle(usd *big.Float) lipgloss.Style {
+	if usd == nil {
+		usd = new(big.Float)
+	}
+	switch {
+	case m.config.HighValueUSD > 0 && usd.Cmp(big.NewFloat(m.config.HighValueUSD)) >= 0:
+		return highValueStyle
+	case m.config.DustValueUSD > 0 && usd.Cmp(big.NewFloat(m.config.DustValueUSD)) < 0:
+		return subtleStyle
+	}
+	return lipgloss.NewStyle()
+}
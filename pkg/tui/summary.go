@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateSummary handles keys in the summary view.
+func (m model) updateSummary(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "s", "q", "esc":
+		m.showSummary = false
+		m.showSummaryGraph = false
+	case "n":
+		m.sortSummaryBy(0)
+	case "v":
+		m.sortSummaryBy(1)
+	case "b":
+		m.sortSummaryBy(2)
+	case "g":
+		m.showSummaryGraph = !m.showSummaryGraph
+	case "m":
+		if m.config.MinDisplayValueUSD <= 0 {
+			m.statusMessage = "Set min_display_value_usd in the config to filter small accounts"
+			return m, clearStatusAfter(3 * time.Second)
+		}
+		m.showBelowMinValue = !m.showBelowMinValue
+	}
+	return m, nil
+}
+
+// sortSummaryBy sorts the summary by col, flipping the direction if it is
+// already the sort column.
+func (m *model) sortSummaryBy(col int) {
+	if !m.summarySorted {
+		m.summarySorted = true
+		m.summarySortCol = col
+		m.summarySortDesc = col != 0
+		return
+	}
+	if m.summarySortCol == col {
+		m.summarySortDesc = !m.summarySortDesc
+		return
+	}
+	m.summarySortCol = col
+	m.summarySortDesc = col != 0
+}
+
+// belowMinValue reports whether an account worth total is hidden from the
+// summary by min_display_value_usd. Pressing m in the summary shows them again.
+func (m model) belowMinValue(total *big.Float) bool {
+	if m.config.MinDisplayValueUSD <= 0 || m.showBelowMinValue {
+		return false
+	}
+	return total == nil || total.Cmp(big.NewFloat(m.config.MinDisplayValueUSD)) < 0
+}
+
+// minValueNote explains how many accounts belowMinValue hid.
+func (m model) minValueNote(hidden int) string {
+	threshold := m.fiat(m.displayValue(big.NewFloat(m.config.MinDisplayValueUSD), m.config.FiatDecimals))
+	return fmt.Sprintf("  %d hidden below %s (still in the total; m to show)", hidden, threshold)
+}
+
+// nilAsZero lets balances that were never fetched sort as zero.
+func nilAsZero(f *big.Float) *big.Float {
+	if f == nil {
+		return new(big.Float)
+	}
+	return f
+}
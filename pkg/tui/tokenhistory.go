@@ -0,0 +1,179 @@
+package tui
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"evmbal/pkg/config"
+	"evmbal/pkg/models"
+	"evmbal/pkg/portfolio"
+	"evmbal/pkg/utils"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/guptarohit/asciigraph"
+)
+
+// maxAssetHistory bounds the points kept per asset, a few hours of polling.
+const maxAssetHistory = 720
+
+// assetHistoryPoint is one balance of an asset, with its USD value at the time
+// (0 if unpriced).
+type assetHistoryPoint struct {
+	At      time.Time
+	Balance float64
+	USD     float64
+}
+
+// detailAsset is a balance row in the detail view.
+type detailAsset struct {
+	chain    config.ChainConfig
+	symbol   string
+	balance  *big.Float
+	priceID  string
+	decimals int
+}
+
+// detailAssets lists the account's native and non-zero token balances in the
+// order the detail view shows them.
+func (m model) detailAssets(acc *models.Account) []detailAsset {
+	var assets []detailAsset
+	for _, chain := range m.chains {
+		if bal, ok := acc.Balances[chain.Name]; ok {
+			assets = append(assets, detailAsset{chain: chain, symbol: chain.Symbol, balance: bal, priceID: chain.CoinGeckoID, decimals: m.config.TokenDecimals})
+		}
+		tokens := acc.TokenBalances[chain.Name]
+		for _, t := range chain.Tokens {
+			if bal, ok := tokens[t.Symbol]; ok && bal.Sign() > 0 {
+				assets = append(assets, detailAsset{chain: chain, symbol: t.Symbol, balance: bal, priceID: t.PriceID(), decimals: m.tokenDisplayDecimals(t)})
+			}
+		}
+	}
+	return assets
+}
+
+// assetHistoryKey identifies an asset of one account on one chain.
+func assetHistoryKey(address, chain, symbol string) string {
+	return strings.ToLower(address) + "/" + chain + "/" + symbol
+}
+
+// recordAssetHistory appends the balances in a chain-data result to the
+// per-asset history graphed by viewTokenHistory.
+func (m *model) recordAssetHistory(chainName string, res models.AccountChainData) {
+	idx := -1
+	for i, c := range m.chains {
+		if c.Name == chainName {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return
+	}
+	chain := m.chains[idx]
+	now := time.Now()
+	m.appendAssetHistory(assetHistoryKey(res.Address, chain.Name, chain.Symbol), now, res.Balance, chain.CoinGeckoID)
+	for _, t := range chain.Tokens {
+		if bal, ok := res.TokenBalances[t.Symbol]; ok {
+			m.appendAssetHistory(assetHistoryKey(res.Address, chain.Name, t.Symbol), now, bal, t.PriceID())
+		}
+	}
+}
+
+func (m *model) appendAssetHistory(key string, at time.Time, bal *big.Float, priceID string) {
+	if bal == nil {
+		return
+	}
+	balance, ok := utils.Float64(bal)
+	if !ok {
+		return
+	}
+	usd, _ := utils.Float64(portfolio.Value(bal, m.prices, priceID))
+	h := append(m.assetHistory[key], assetHistoryPoint{At: at, Balance: balance, USD: usd})
+	if len(h) > maxAssetHistory {
+		h = h[len(h)-maxAssetHistory:]
+	}
+	m.assetHistory[key] = h
+}
+
+// selectedDetailAsset returns the asset highlighted in the detail view.
+func (m model) selectedDetailAsset() (detailAsset, bool) {
+	if len(m.accounts) == 0 {
+		return detailAsset{}, false
+	}
+	assets := m.detailAssets(m.accounts[m.activeIdx])
+	if m.detailAssetIdx < 0 || m.detailAssetIdx >= len(assets) {
+		return detailAsset{}, false
+	}
+	return assets[m.detailAssetIdx], true
+}
+
+// moveDetailSelection moves the detail view's highlighted asset by delta, wrapping around.
+func (m *model) moveDetailSelection(delta int) {
+	n := len(m.detailAssets(m.accounts[m.activeIdx]))
+	if n == 0 {
+		return
+	}
+	m.detailAssetIdx = ((m.detailAssetIdx+delta)%n + n) % n
+	m.updateDetailViewport()
+}
+
+// updateTokenHistory handles keys in the asset history graph opened from the detail view.
+func (m model) updateTokenHistory(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc", "g":
+		m.showTokenHistory = false
+	case "u":
+		m.tokenHistoryUSD = !m.tokenHistoryUSD
+	}
+	return m, nil
+}
+
+// viewTokenHistory graphs the selected asset's balance, or its USD value, over the session.
+func (m model) viewTokenHistory() string {
+	asset, ok := m.selectedDetailAsset()
+	if !ok {
+		return m.viewDetail()
+	}
+	acc := m.accounts[m.activeIdx]
+	label := acc.Name
+	if label == "" {
+		label = utils.TruncateString(acc.Address, 14)
+	}
+	header := titleStyle.Render(fmt.Sprintf("%s on %s • %s", asset.symbol, asset.chain.Name, label))
+
+	history := m.assetHistory[assetHistoryKey(acc.Address, asset.chain.Name, asset.symbol)]
+	unit := asset.symbol
+	series := make([]float64, len(history))
+	for i, p := range history {
+		series[i] = p.Balance
+		if m.tokenHistoryUSD {
+			series[i] = p.USD
+		}
+	}
+	if m.tokenHistoryUSD {
+		unit = "USD"
+	}
+
+	var graph string
+	switch {
+	case m.privacyMode:
+		graph = subtleStyle.Render("Hidden in Privacy Mode")
+	case len(series) < 2:
+		graph = "Not enough data to draw graph yet; a point is added on every refresh."
+	default:
+		width := max(m.width-16, 10)
+		height := max(m.height-14, 1)
+		graph = asciigraph.Plot(series,
+			asciigraph.Height(height),
+			asciigraph.Width(width),
+			asciigraph.Caption(fmt.Sprintf("%s balance (%s) since %s", asset.symbol, unit, history[0].At.Format("15:04"))),
+		)
+	}
+
+	content := boxStyle.Render(lipgloss.JoinVertical(lipgloss.Center, header, "\n", graph))
+	footer := subtleStyle.Render("u: toggle USD • g/q/esc: back")
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, lipgloss.JoinVertical(lipgloss.Center, content, "\n", footer))
+}
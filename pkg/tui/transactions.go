@@ -0,0 +1,193 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"evmbal/pkg/config"
+	"evmbal/pkg/models"
+	"evmbal/pkg/rpc"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// txRangeMsg carries the result of a historical transaction scan.
+type txRangeMsg struct {
+	address string
+	chain   string
+	txs     []models.Transaction
+	err     error
+}
+
+// txRangeScanLimit caps how many transactions a single range scan collects.
+const txRangeScanLimit = 100
+
+// maxTxListRows is how many transactions the list view shows at once.
+const maxTxListRows = 15
+
+// maxStoredTxPerChain bounds the transactions kept per account and chain, so
+// polling and range scans do not grow the list for the whole session.
+const maxStoredTxPerChain = 500
+
+func fetchTransactionsRangeCmd(address, chain string, rpcURLs []string, timeout time.Duration, fromBlock uint64, tokenDecimals int) tea.Cmd {
+	return func() tea.Msg {
+		txs, err := rpc.FetchTransactionsRange(address, rpcURLs, fromBlock, 0, txRangeScanLimit, tokenDecimals, timeout)
+		for i := range txs {
+			txs[i].Chain = chain
+		}
+		return txRangeMsg{address: address, chain: chain, txs: txs, err: err}
+	}
+}
+
+// txPageMsg carries one page of explorer history, normal transactions and token
+// transfers, requested from the list view.
+type txPageMsg struct {
+	address string
+	chain   string
+	page    int
+	txs     []models.Transaction
+	err     error
+}
+
+func fetchTxPageCmd(address string, chain config.ChainConfig, page, tokenDecimals int) tea.Cmd {
+	return func() tea.Msg {
+		txs, err := rpc.FetchExplorerTransactions(chain, address, page, tokenDecimals)
+		if err == nil {
+			var transfers []models.Transaction
+			transfers, err = rpc.FetchTokenTransfers(address, chain, page, tokenDecimals)
+			txs = append(txs, transfers...)
+		}
+		for i := range txs {
+			txs[i].Chain = chain.Name
+		}
+		return txPageMsg{address: address, chain: chain.Name, page: page, txs: txs, err: err}
+	}
+}
+
+// txPageKey identifies the explorer history of an account on a chain.
+func txPageKey(address, chain string) string {
+	return strings.ToLower(address) + "|" + chain
+}
+
+// loadMoreTransactions requests the next explorer page of the active account's
+// history on the active chain. Polling keeps page 1 current, so the first
+// request is for page 2.
+func (m *model) loadMoreTransactions() tea.Cmd {
+	chain := m.chains[m.activeChainIdx]
+	if chain.ExplorerAPIURL == "" {
+		m.statusMessage = fmt.Sprintf("%s has no explorer API; use s to scan older blocks", chain.Name)
+		return clearStatusAfter(3 * time.Second)
+	}
+	acc := m.accounts[m.activeIdx]
+	page := max(m.txPages[txPageKey(acc.Address, chain.Name)]+1, 2)
+	if (page-1)*rpc.ExplorerPageSize >= maxStoredTxPerChain {
+		m.statusMessage = fmt.Sprintf("The list keeps at most %d transactions per chain", maxStoredTxPerChain)
+		return clearStatusAfter(3 * time.Second)
+	}
+	m.statusMessage = fmt.Sprintf("Loading page %d from %s...", page, chain.Name)
+	return fetchTxPageCmd(acc.Address, chain, page, m.config.TokenDecimals)
+}
+
+// applyTxPage merges a loaded page into the account it was requested for and
+// remembers it as the last page loaded.
+func (m *model) applyTxPage(msg txPageMsg) {
+	// Entries of a partly failed page are kept; loading it again skips them.
+	if idx := m.findAccount(msg.address); idx >= 0 {
+		acc := m.accounts[idx]
+		acc.Transactions = mergeTransactions(acc.Transactions, msg.txs)
+	}
+	if msg.err != nil {
+		m.statusMessage = fmt.Sprintf("Failed to load page %d: %v", msg.page, msg.err)
+		return
+	}
+	m.txPages[txPageKey(msg.address, msg.chain)] = msg.page
+	if len(msg.txs) == 0 {
+		m.statusMessage = fmt.Sprintf("No older transactions on %s", msg.chain)
+	} else {
+		m.statusMessage = fmt.Sprintf("Loaded %d older transactions", len(msg.txs))
+	}
+}
+
+// txKey identifies a list entry. A token transfer shares its hash with the
+// transaction that caused it, so the token and counterparties are part of the key.
+func txKey(tx models.Transaction) string {
+	return strings.ToLower(tx.Chain + "|" + tx.Hash + "|" + tx.TokenSymbol + "|" + tx.From + "|" + tx.To)
+}
+
+// mergeTransactions adds found to existing, skipping entries already present, and
+// orders the result by chain and then newest block first, keeping the newest
+// maxStoredTxPerChain of each chain. Block numbers of different chains are not
+// comparable, so views show one chain at a time.
+func mergeTransactions(existing, found []models.Transaction) []models.Transaction {
+	seen := make(map[string]bool, len(existing))
+	merged := append([]models.Transaction(nil), existing...)
+	for _, tx := range existing {
+		seen[txKey(tx)] = true
+	}
+	for _, tx := range found {
+		if !seen[txKey(tx)] {
+			seen[txKey(tx)] = true
+			merged = append(merged, tx)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].Chain != merged[j].Chain {
+			return merged[i].Chain < merged[j].Chain
+		}
+		return merged[i].BlockNumber > merged[j].BlockNumber
+	})
+	perChain := make(map[string]int)
+	kept := merged[:0]
+	for _, tx := range merged {
+		if perChain[tx.Chain]++; perChain[tx.Chain] <= maxStoredTxPerChain {
+			kept = append(kept, tx)
+		}
+	}
+	return kept
+}
+
+func (m model) updateScanningTxRange(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.scanningTxRange = false
+		m.txScanInput.Blur()
+		return m, nil
+	case "enter":
+		fromBlock, err := strconv.ParseUint(strings.TrimSpace(m.txScanInput.Value()), 10, 64)
+		if err != nil {
+			m.statusMessage = "Invalid block number"
+			return m, clearStatusAfter(2 * time.Second)
+		}
+		m.scanningTxRange = false
+		m.txScanInput.Blur()
+		acc := m.accounts[m.activeIdx]
+		chain := m.chains[m.activeChainIdx]
+		m.statusMessage = fmt.Sprintf("Scanning %s from block %d...", chain.Name, fromBlock)
+		return m, fetchTransactionsRangeCmd(acc.Address, chain.Name, chain.ActiveRPCURLs(), chain.Timeout(rpc.DefaultRangeScanTimeout), fromBlock, m.config.TokenDecimals)
+	}
+	var cmd tea.Cmd
+	m.txScanInput, cmd = m.txScanInput.Update(msg)
+	return m, cmd
+}
+
+// applyTxRange merges scanned transactions into the account they were scanned for.
+func (m *model) applyTxRange(msg txRangeMsg) {
+	idx := m.findAccount(msg.address)
+	if idx >= 0 {
+		acc := m.accounts[idx]
+		acc.Transactions = mergeTransactions(acc.Transactions, msg.txs)
+	}
+	switch {
+	case msg.err != nil && len(msg.txs) > 0:
+		m.statusMessage = fmt.Sprintf("Scan stopped after %d transactions: %v", len(msg.txs), msg.err)
+	case msg.err != nil:
+		m.statusMessage = fmt.Sprintf("Scan failed: %v", msg.err)
+	case len(msg.txs) >= txRangeScanLimit:
+		m.statusMessage = fmt.Sprintf("Found %d transactions (limit reached)", len(msg.txs))
+	default:
+		m.statusMessage = fmt.Sprintf("Found %d transactions", len(msg.txs))
+	}
+}
@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"evmbal/pkg/config"
+	"evmbal/pkg/models"
+	"evmbal/pkg/watcher"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeTransactions(t *testing.T) {
+	existing := []models.Transaction{
+		{Hash: "0xc", BlockNumber: 300},
+		{Hash: "0xb", BlockNumber: 200},
+	}
+	found := []models.Transaction{
+		{Hash: "0xa", BlockNumber: 100},
+		{Hash: "0xb", BlockNumber: 200},
+	}
+
+	merged := mergeTransactions(existing, found)
+
+	var hashes []string
+	for _, tx := range merged {
+		hashes = append(hashes, tx.Hash)
+	}
+	assert.Equal(t, []string{"0xc", "0xb", "0xa"}, hashes)
+	assert.Len(t, existing, 2, "existing slice must not be modified")
+}
+
+func TestMergeTransactions_KeepsChainsApart(t *testing.T) {
+	existing := []models.Transaction{{Hash: "0xa", BlockNumber: 100, Chain: "Ethereum"}}
+	found := []models.Transaction{{Hash: "0xa", BlockNumber: 100, Chain: "Base"}}
+
+	merged := mergeTransactions(existing, found)
+
+	assert.Len(t, merged, 2, "the same hash on another chain is a different transaction")
+}
+
+func TestMergeTransactions_CapsEachChain(t *testing.T) {
+	var found []models.Transaction
+	for i := 0; i < maxStoredTxPerChain+10; i++ {
+		found = append(found, models.Transaction{Hash: fmt.Sprintf("0x%x", i), BlockNumber: uint64(i), Chain: "Ethereum"})
+	}
+	found = append(found, models.Transaction{Hash: "0xbase", BlockNumber: 1, Chain: "Base"})
+
+	merged := mergeTransactions(nil, found)
+
+	assert.Len(t, merged, maxStoredTxPerChain+1)
+	assert.Equal(t, "Base", merged[0].Chain, "the cap of one chain does not drop another chain's entries")
+	assert.Equal(t, uint64(maxStoredTxPerChain+9), merged[1].BlockNumber, "newest entries are kept")
+}
+
+func TestLoadMoreTransactions(t *testing.T) {
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, r.URL.Query().Get("action")+" "+page)
+		switch {
+		case page == "3":
+			_, _ = w.Write([]byte(`{"status":"0","message":"No transactions found","result":[]}`))
+		case r.URL.Query().Get("action") == "tokentx":
+			_, _ = w.Write([]byte(`{"status":"1","message":"OK","result":[
+				{"blockNumber":"90","hash":"0xtoken","nonce":"0","from":"0xfrom","to":"0xto","value":"1000000","gas":"60000","gasPrice":"1","tokenSymbol":"USDC","tokenDecimal":"6"}
+			]}`))
+		default:
+			_, _ = w.Write([]byte(`{"status":"1","message":"OK","result":[
+				{"blockNumber":"100","hash":"0xold","nonce":"1","from":"0xfrom","to":"0xto","value":"0","gas":"21000","gasPrice":"1"}
+			]}`))
+		}
+	}))
+	defer server.Close()
+
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://localhost:8545"}, ExplorerAPIURL: server.URL}}
+	addrs := []config.AddressConfig{{Address: "0x1111111111111111111111111111111111111111", Name: "Main"}}
+	w := watcher.NewWatcher(addrs, chains, config.GlobalConfig{}, "")
+	m := initialModel(w, addrs, chains, 0, config.GlobalConfig{}, "")
+	m.showTxList = true
+
+	for range 2 {
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+		m = updated.(model)
+		if !assert.NotNil(t, cmd) {
+			return
+		}
+		updated, _ = m.Update(cmd())
+		m = updated.(model)
+	}
+
+	assert.Equal(t, []string{"txlist 2", "tokentx 2", "txlist 3", "tokentx 3"}, pages, "polling covers page 1, so loading starts at page 2")
+	assert.Equal(t, "No older transactions on Eth", m.statusMessage)
+	if assert.Len(t, m.accounts[0].Transactions, 2) {
+		assert.Equal(t, "0xold", m.accounts[0].Transactions[0].Hash)
+		assert.Equal(t, "USDC", m.accounts[0].Transactions[1].TokenSymbol)
+		assert.Equal(t, "Eth", m.accounts[0].Transactions[1].Chain)
+	}
+}
+
+func TestMergeTransactions_OrdersWithinEachChain(t *testing.T) {
+	existing := []models.Transaction{{Hash: "0xeth1", BlockNumber: 20_000_000, Chain: "Ethereum"}}
+	found := []models.Transaction{
+		{Hash: "0xpoly", BlockNumber: 60_000_000, Chain: "Polygon"},
+		{Hash: "0xeth2", BlockNumber: 20_000_001, Chain: "Ethereum"},
+	}
+
+	merged := mergeTransactions(existing, found)
+
+	var hashes []string
+	for _, tx := range merged {
+		hashes = append(hashes, tx.Hash)
+	}
+	assert.Equal(t, []string{"0xeth2", "0xeth1", "0xpoly"}, hashes, "block numbers are only compared within a chain")
+}
@@ -6,20 +6,41 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
+	"evmbal/pkg/config"
 	"evmbal/pkg/models"
 	"evmbal/pkg/watcher"
 
 	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.settingUp {
+		return m.updateSetup(msg)
+	}
 	var cmds []tea.Cmd
 	activeChain := m.chains[m.activeChainIdx]
 
 	switch msg := msg.(type) {
 
+	case coinSearchTickMsg:
+		// Only search if typing has paused on this query
+		if in := m.coinGeckoInput(); in != nil && strings.TrimSpace(in.Value()) == msg.query {
+			return m, searchCoinGeckoCmd(msg.query)
+		}
+		return m, nil
+
+	case coinSearchMsg:
+		m.applyCoinSearch(msg)
+		return m, nil
+
+	case tokenDecimalsMsg:
+		return m.applyTokenDecimalsCheck(msg)
+
 	// Handle Token Metadata Result (still separate as it's a one-off UI action)
 	case models.TokenMetadata:
 		if m.addingToken {
@@ -30,102 +51,65 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.tokenInputs[2].Value() == "" && msg.Decimals != 0 {
 					m.tokenInputs[2].SetValue(strconv.Itoa(msg.Decimals))
 				}
-				m.statusMessage = "Token metadata fetched!"
+				m.statusMessage = fmt.Sprintf("Token metadata fetched via %s!", rpcHost(msg.RPCURL))
+				if msg.Partial {
+					m.statusMessage = fmt.Sprintf("Token metadata partially fetched via %s, please verify", rpcHost(msg.RPCURL))
+				}
+			} else {
+				m.statusMessage = fmt.Sprintf("Token metadata: %v", msg.Err)
 			}
 			cmds = append(cmds, tea.Tick(time.Second*2, func(t time.Time) tea.Msg {
 				return clearStatusMsg{}
 			}))
 		}
 
-	case watcher.Event:
-		// Re-subscribe to next event
-		cmds = append(cmds, listenForWatcher(m.watcher.Subscribe()))
-
-		switch msg.Type {
-		case watcher.EventPriceUpdated:
-			if data, ok := msg.Data.(models.PriceData); ok {
-				m.prices[data.CoinID] = data.Price
-			}
-		case watcher.EventChainDataUpdated:
-			if data, ok := msg.Data.(models.ChainData); ok {
-				m.loading = false
-				for _, res := range data.Results {
-					for _, acc := range m.accounts {
-						if strings.EqualFold(acc.Address, res.Address) {
-							if acc.Balances == nil {
-								acc.Balances = make(map[string]*big.Float)
-							}
-							if acc.TokenBalances == nil {
-								acc.TokenBalances = make(map[string]map[string]*big.Float)
-							}
-							if acc.Balances24h == nil {
-								acc.Balances24h = make(map[string]*big.Float)
-							}
-							if acc.Errors == nil {
-								acc.Errors = make(map[string]error)
-							}
-							acc.Balances[data.ChainName] = res.Balance
-							acc.Balances24h[data.ChainName] = res.Balance24h
-							if acc.TokenBalances[data.ChainName] == nil {
-								acc.TokenBalances[data.ChainName] = make(map[string]*big.Float)
-							}
-							for sym, bal := range res.TokenBalances {
-								acc.TokenBalances[data.ChainName][sym] = bal
-							}
-							delete(acc.Errors, data.ChainName)
-							break
-						}
-					}
-				}
-			}
-		case watcher.EventGasPriceUpdated:
-			if data, ok := msg.Data.(models.GasPriceData); ok {
-				if m.gasPrice != nil {
-					m.gasTrend = data.Price.Cmp(m.gasPrice)
-				}
-				m.gasPrice = data.Price
-				gwei := new(big.Float).Quo(new(big.Float).SetInt(data.Price), big.NewFloat(1e9))
-				val, _ := gwei.Float64()
-				m.gasPriceHistory = append(m.gasPriceHistory, models.GasPricePoint{Timestamp: time.Now(), Value: val})
-				if len(m.gasPriceHistory) > 2880 {
-					m.gasPriceHistory = m.gasPriceHistory[len(m.gasPriceHistory)-2880:]
-				}
-			}
-		case watcher.EventTransactionsUpdated:
-			if data, ok := msg.Data.(map[string]interface{}); ok {
-				addr, _ := data["address"].(string)
-				txs, _ := data["txs"].([]models.Transaction)
-				for _, acc := range m.accounts {
-					if acc.Address == addr {
-						acc.Transactions = txs
-						break
-					}
-				}
-			}
-		}
+	case tokenImportMsg:
+		m.applyTokenImport(msg)
+		cmds = append(cmds, clearStatusAfter(3*time.Second))
+
+	case txRangeMsg:
+		m.applyTxRange(msg)
+		cmds = append(cmds, clearStatusAfter(5*time.Second))
 
-		m.lastUpdate = time.Now()
-		if m.showDetail {
-			m.updateDetailViewport()
+	case txPageMsg:
+		m.applyTxPage(msg)
+		cmds = append(cmds, clearStatusAfter(3*time.Second))
+
+	case tokenListMsg:
+		m.reviewTokenList(msg)
+		if !m.reviewingTokens {
+			cmds = append(cmds, clearStatusAfter(3*time.Second))
 		}
 
-	case models.RPCLatencyData:
-		if m.rpcLatencyHistory == nil {
-			m.rpcLatencyHistory = make(map[string][]time.Duration)
+	case watcher.Event:
+		// Wait for the next event on the same subscription
+		cmds = append(cmds, listenForWatcher(m.sub))
+		if m.updatesPaused {
+			m.bufferPausedEvent(msg)
+			break
 		}
-		val := msg.Latency
-		if msg.Err != nil {
-			m.rpcLatencies[msg.RPCURL] = -1
-			val = -1
-		} else {
-			m.rpcLatencies[msg.RPCURL] = msg.Latency
+		cmds = append(cmds, m.applyWatcherEvent(msg)...)
+
+	case tea.BlurMsg:
+		if m.config.PauseWhenUnfocused {
+			m.watcher.Pause()
 		}
-		hist := m.rpcLatencyHistory[msg.RPCURL]
-		hist = append(hist, val)
-		if len(hist) > 15 {
-			hist = hist[len(hist)-15:]
+
+	case tea.FocusMsg:
+		if m.watcher.Paused() && m.config.PauseWhenUnfocused {
+			m.watcher.Resume()
+			m.statusMessage = "Welcome back, refreshing..."
+			cmds = append(cmds, clearStatusAfter(2*time.Second))
 		}
-		m.rpcLatencyHistory[msg.RPCURL] = hist
+
+	case tea.WindowSizeMsg:
+		m.setSize(msg.Width, msg.Height)
+
+	case models.RPCLatencyData:
+		m.recordRPCLatency(msg)
+
+	case models.RPCProbeData:
+		m.applyRPCProbe(msg)
 
 	case privacyTimeoutMsg:
 		if m.config.PrivacyTimeoutSeconds <= 0 {
@@ -156,8 +140,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return autoCycleMsg{}
 				}))
 			} else {
-				if len(m.accounts) > 1 {
-					m.activeIdx = (m.activeIdx + 1) % len(m.accounts)
+				if cmd := m.autoCycle(); cmd != nil {
+					cmds = append(cmds, cmd)
 				}
 				interval := time.Duration(m.config.AutoCycleIntervalSeconds) * time.Second
 				m.nextAutoCycleTime = time.Now().Add(interval)
@@ -169,7 +153,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.KeyMsg:
 		m.lastInteraction = time.Now()
-		isInputMode := m.editingAddress || m.addingToken || m.addingChain || m.adding || m.exportingConfig || m.editingGlobalConfig
+		if m.dashboardMode {
+			return m.updateDashboard(msg)
+		}
+		isInputMode := m.editingAddress || m.addingToken || m.addingChain || m.adding || m.importingTokens || m.addingRPC || m.reviewingTokens || m.exportingConfig || m.editingGlobalConfig || m.scanningTxRange
+		if m.debugEnabled && msg.String() == "ctrl+d" {
+			m.showDebug = !m.showDebug
+			return m, nil
+		}
 		if !isInputMode && msg.String() == "?" {
 			m.showHelp = !m.showHelp
 			return m, nil
@@ -181,7 +172,85 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		if msg.String() == "P" {
+		if m.adding {
+			switch msg.String() {
+			case "esc":
+				m.adding = false
+				m.resetAddressInputs()
+				return m, nil
+			case "tab", "down":
+				m.focusAddressInput(m.addressFocusIdx + 1)
+				return m, nil
+			case "shift+tab", "up":
+				m.focusAddressInput(m.addressFocusIdx - 1)
+				return m, nil
+			case "enter":
+				// A pasted list has no use for the name field, so save it right away.
+				if m.addressFocusIdx < len(m.addressInputs)-1 && m.addressCheck != addressList {
+					m.focusAddressInput(m.addressFocusIdx + 1)
+					return m, nil
+				}
+				addr := strings.TrimSpace(m.addressInputs[0].Value())
+				name := strings.TrimSpace(m.addressInputs[1].Value())
+				if m.addressCheck == addressList {
+					m.addAddressList(addr)
+				} else if !common.IsHexAddress(addr) {
+					m.statusMessage = "Invalid address"
+				} else if m.findAccount(addr) >= 0 {
+					m.statusMessage = "Address is already monitored"
+				} else {
+					m.accounts = append(m.accounts, newAccount(addr, name))
+					m.activeIdx = len(m.accounts) - 1
+					m.watcher.AddAccount(config.AddressConfig{Address: addr, Name: name})
+					if err := m.saveConfig(); err != nil {
+						m.statusMessage = fmt.Sprintf("Failed to save config: %v", err)
+					} else {
+						m.statusMessage = "Address added"
+					}
+					m.adding = false
+					m.resetAddressInputs()
+				}
+				cmds = append(cmds, tea.Tick(time.Second*2, func(t time.Time) tea.Msg {
+					return clearStatusMsg{}
+				}))
+				return m, tea.Batch(cmds...)
+			}
+			var cmd tea.Cmd
+			m.addressInputs[m.addressFocusIdx], cmd = m.addressInputs[m.addressFocusIdx].Update(msg)
+			if m.addressFocusIdx == 0 {
+				m.addressCheck = m.checkAddress(m.addressInputs[0].Value())
+			}
+			return m, cmd
+		}
+
+		switch {
+		case m.scanningTxRange:
+			return m.updateScanningTxRange(msg)
+		case m.reviewingTokens:
+			return m.updateReviewingTokens(msg)
+		case m.editingAddress:
+			return m.updateEditingAddress(msg)
+		case m.addingChain:
+			return m.updateAddingChain(msg)
+		case m.addingToken:
+			return m.updateAddingToken(msg)
+		case m.importingTokens:
+			return m.updateImportingTokens(msg)
+		case m.managingTokens:
+			return m.updateManagingTokens(msg)
+		case m.addingRPC:
+			return m.updateAddingRPC(msg)
+		case m.managingRPCs:
+			return m.updateManagingRPCs(msg)
+		case m.managingChains:
+			return m.updateManagingChains(msg)
+		case m.restoringBackup:
+			return m.updateRestoringBackup(msg)
+		case m.exportingConfig:
+			return m.updateExportingConfig(msg)
+		}
+
+		if !isInputMode && msg.String() == "P" {
 			m.privacyMode = !m.privacyMode
 			if !m.privacyMode && m.config.PrivacyTimeoutSeconds > 0 {
 				cmds = append(cmds, tea.Tick(time.Duration(m.config.PrivacyTimeoutSeconds)*time.Second, func(t time.Time) tea.Msg {
@@ -190,7 +259,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-		if msg.String() == "A" {
+		if !isInputMode && msg.String() == "A" {
 			m.config.AutoCycleEnabled = !m.config.AutoCycleEnabled
 			status := "disabled"
 			if m.config.AutoCycleEnabled {
@@ -215,17 +284,26 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.showTxDetail = false
 				return m, nil
 			case "o":
-				if activeChain.ExplorerURL == "" {
-					m.statusMessage = "Explorer URL not configured for this chain"
-					cmds = append(cmds, tea.Tick(time.Second*2, func(t time.Time) tea.Msg {
-						return clearStatusMsg{}
-					}))
-					return m, tea.Batch(cmds...)
+				if len(m.accounts) == 0 {
+					return m, nil
 				}
-				acc := m.accounts[m.activeIdx]
-				if len(acc.Transactions) > m.txListIdx {
-					tx := acc.Transactions[m.txListIdx]
-					url := fmt.Sprintf("%s/tx/%s", strings.TrimRight(activeChain.ExplorerURL, "/"), tx.Hash)
+				txs := m.getFilteredTransactions(m.accounts[m.activeIdx])
+				if len(txs) <= m.txListIdx {
+					return m, nil
+				}
+				tx := txs[m.txListIdx]
+				// The explorer of the chain the transaction was found on, which
+				// need not be the active one.
+				explorerURL := activeChain.ExplorerURL
+				for _, c := range m.chains {
+					if tx.Chain != "" && c.Name == tx.Chain {
+						explorerURL = c.ExplorerURL
+					}
+				}
+				if explorerURL == "" {
+					m.statusMessage = "Explorer URL not configured for this chain"
+				} else {
+					url := fmt.Sprintf("%s/tx/%s", strings.TrimRight(explorerURL, "/"), tx.Hash)
 					if err := openBrowser(url); err != nil {
 						m.statusMessage = fmt.Sprintf("Failed to open browser: %v", err)
 					} else {
@@ -240,7 +318,71 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if m.showSummary {
+			return m.updateSummary(msg)
+		}
+
+		if m.showNetworkStatus {
+			return m.updateNetworkStatus(msg)
+		}
+
+		if m.showQR {
+			switch msg.String() {
+			case "q", "esc", "Q":
+				m.showQR = false
+			}
+			return m, nil
+		}
+
+		if m.showTokenHistory {
+			return m.updateTokenHistory(msg)
+		}
+
+		if m.showDetail && msg.String() != "c" {
+			switch msg.String() {
+			case "q", "esc", "enter":
+				m.showDetail = false
+				return m, nil
+			case "tab":
+				m.moveDetailSelection(1)
+				return m, nil
+			case "shift+tab":
+				m.moveDetailSelection(-1)
+				return m, nil
+			case "g":
+				if _, ok := m.selectedDetailAsset(); ok {
+					m.showTokenHistory = true
+				}
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.viewport, cmd = m.viewport.Update(msg)
+			return m, cmd
+		}
+
+		if m.showGasTracker && msg.String() != "r" {
+			switch msg.String() {
+			case "G", "q", "esc", "ctrl+c":
+				m.showGasTracker = false
+			case "<", ",":
+				if m.gasTrackerRangeIndex > 0 {
+					m.gasTrackerRangeIndex--
+				}
+			case ">", ".":
+				if m.gasTrackerRangeIndex < 3 {
+					m.gasTrackerRangeIndex++
+				}
+			case "u":
+				m.gasShowUSD = !m.gasShowUSD
+			}
+			return m, nil
+		}
+
 		if m.showTxList {
+			if len(m.accounts) == 0 {
+				m.showTxList = false
+				return m, nil
+			}
 			switch msg.String() {
 			case "q", "esc":
 				m.showTxList = false
@@ -253,10 +395,21 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.txFilter = "out"
 				m.txListIdx = 0
 				return m, nil
+			case "t":
+				m.txFilter = "token"
+				m.txListIdx = 0
+				return m, nil
 			case "a":
 				m.txFilter = "all"
 				m.txListIdx = 0
 				return m, nil
+			case "s":
+				m.scanningTxRange = true
+				m.txScanInput.Reset()
+				m.txScanInput.Focus()
+				return m, textinput.Blink
+			case "m":
+				return m, m.loadMoreTransactions()
 			case "up", "k":
 				if m.txListIdx > 0 {
 					m.txListIdx--
@@ -281,14 +434,6 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.showSummary = false
 				return m, nil
 			}
-			if m.showNetworkStatus {
-				m.showNetworkStatus = false
-				return m, nil
-			}
-			if m.showGasTracker {
-				m.showGasTracker = false
-				return m, nil
-			}
 			if m.editingAddress || m.adding || m.addingChain || m.addingToken || m.editingGlobalConfig || m.exportingConfig || m.restoringBackup {
 				return m, nil
 			}
@@ -296,18 +441,162 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "G":
 			m.showGasTracker = true
 			return m, nil
+		case " ":
+			return m, tea.Batch(m.togglePauseUpdates()...)
+		case "N":
+			m.showNetworkStatus = true
+			m.networkStatusIdx = 0
+			return m, nil
+		case "T":
+			if len(m.accounts) > 0 {
+				m.showTxList = true
+				m.txListIdx = 0
+			}
+			return m, nil
+		case "n":
+			cmds = append(cmds, m.nextEnabledChain())
+		case "H":
+			active := m.chains[m.activeChainIdx].Name
+			if m.config.PrimaryChain == active {
+				m.config.PrimaryChain = ""
+				m.statusMessage = "Top bar follows the active chain"
+			} else {
+				m.config.PrimaryChain = active
+				m.statusMessage = fmt.Sprintf("Top bar pinned to %s", active)
+			}
+			if err := m.saveConfig(); err != nil {
+				m.statusMessage = fmt.Sprintf("Failed to save config: %v", err)
+			}
+			cmds = append(cmds, clearStatusAfter(2*time.Second))
+		case "X":
+			m.exportingConfig = true
+			m.focusExportInput(0)
+			return m, nil
+		case "B":
+			if err := m.loadBackupList(); err != nil {
+				m.statusMessage = fmt.Sprintf("Failed to list backups: %v", err)
+				return m, clearStatusAfter(2 * time.Second)
+			}
+			m.restoringBackup = true
+			m.backupListIdx = 0
+			return m, nil
+		case "E":
+			m.managingChains = true
+			m.chainListIdx = m.activeChainIdx
+			return m, nil
+		case "t":
+			m.compactMode = !m.compactMode
+		case "z":
+			m.config.HideZeroBalances = !m.config.HideZeroBalances
+			if m.config.HideZeroBalances {
+				m.statusMessage = "Hiding zero-balance tokens"
+			} else {
+				m.statusMessage = "Showing zero-balance tokens"
+			}
+			cmds = append(cmds, clearStatusAfter(2*time.Second))
+		case "Q":
+			if m.privacyMode {
+				m.statusMessage = "QR code disabled in Privacy Mode"
+				cmds = append(cmds, tea.Tick(time.Second*2, func(t time.Time) tea.Msg {
+					return clearStatusMsg{}
+				}))
+			} else if len(m.accounts) > 0 {
+				m.showQR = true
+				return m, nil
+			}
 		case "r":
+			for _, c := range m.chains {
+				m.chainLoading[c.Name] = c.IsEnabled()
+			}
+			if !m.loading {
+				cmds = append(cmds, m.spinner.Tick)
+			}
 			m.loading = true
-			// Manual refresh: in the new world, we tell the watcher to fetch now
-			// For now, it's automatic anyway.
-			m.statusMessage = "Refreshing data..."
-			cmds = append(cmds, tea.Tick(time.Second*2, func(t time.Time) tea.Msg {
-				return clearStatusMsg{}
-			}))
+			m.refreshProgress = make(map[string]int, len(m.chains))
+			for _, c := range m.chains {
+				if c.IsEnabled() {
+					m.refreshProgress[c.Name] = 0
+				}
+			}
+			m.statusMessage = ""
+			m.watcher.Refresh()
+
+		case "a":
+			m.adding = true
+			m.focusAddressInput(0)
+			return m, textinput.Blink
+
+		case "d":
+			if len(m.accounts) > 0 {
+				removed := m.accounts[m.activeIdx]
+				m.accounts = append(m.accounts[:m.activeIdx], m.accounts[m.activeIdx+1:]...)
+				if m.activeIdx >= len(m.accounts) {
+					m.activeIdx = len(m.accounts) - 1
+				}
+				if m.activeIdx < 0 {
+					m.activeIdx = 0
+				}
+				m.watcher.RemoveAccount(removed.Address)
+				if err := m.saveConfig(); err != nil {
+					m.statusMessage = fmt.Sprintf("Failed to save config: %v", err)
+				} else {
+					m.statusMessage = fmt.Sprintf("Removed %s", removed.Address)
+				}
+				cmds = append(cmds, tea.Tick(time.Second*2, func(t time.Time) tea.Msg {
+					return clearStatusMsg{}
+				}))
+			}
+
+		case "b":
+			if len(m.accounts) > 0 {
+				acc := m.accounts[m.activeIdx]
+				if !acc.Fetched[activeChain.Name] {
+					m.statusMessage = "Balance not fetched yet; cannot set baseline"
+				} else {
+					if acc.Baselines == nil {
+						acc.Baselines = make(map[string]*big.Float)
+					}
+					bal := acc.Balances[activeChain.Name]
+					if bal == nil {
+						bal = new(big.Float)
+					}
+					acc.Baselines[activeChain.Name] = new(big.Float).Copy(bal)
+					if err := m.saveConfig(); err != nil {
+						m.statusMessage = fmt.Sprintf("Failed to save config: %v", err)
+					} else {
+						m.statusMessage = fmt.Sprintf("Baseline pinned at %s %s", m.displayValue(bal, m.config.TokenDecimals), activeChain.Symbol)
+					}
+				}
+				cmds = append(cmds, tea.Tick(time.Second*2, func(t time.Time) tea.Msg {
+					return clearStatusMsg{}
+				}))
+			}
+
+		case "M":
+			return m, m.startEditingAddress()
+
+		case "s":
+			m.showSummary = true
+			return m, nil
+
+		case "x":
+			if len(m.accounts) > 0 {
+				acc := m.accounts[m.activeIdx]
+				acc.ExcludeFromTotal = !acc.ExcludeFromTotal
+				if err := m.saveConfig(); err != nil {
+					m.statusMessage = fmt.Sprintf("Failed to save config: %v", err)
+				} else if acc.ExcludeFromTotal {
+					m.statusMessage = "Account excluded from the portfolio total"
+				} else {
+					m.statusMessage = "Account included in the portfolio total"
+				}
+				cmds = append(cmds, clearStatusAfter(2*time.Second))
+			}
 
 		case "enter":
 			if len(m.accounts) > 0 {
 				m.showDetail = true
+				m.detailAssetIdx = 0
 				m.updateDetailViewport()
 				m.viewport.YOffset = 0
 			}
@@ -329,6 +618,19 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}))
 			}
 
+		case "C":
+			switch {
+			case m.privacyMode:
+				m.statusMessage = "Copying the summary is disabled in Privacy Mode"
+			case len(m.accounts) == 0:
+				m.statusMessage = "No addresses to summarize"
+			case clipboard.WriteAll(m.portfolioSummaryText()) != nil:
+				m.statusMessage = "Failed to copy to clipboard"
+			default:
+				m.statusMessage = fmt.Sprintf("Portfolio summary of %d addresses copied to clipboard!", len(m.accounts))
+			}
+			cmds = append(cmds, clearStatusAfter(2*time.Second))
+
 		case "tab", "right", "l":
 			if len(m.accounts) > 0 {
 				m.activeIdx++
@@ -343,6 +645,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.activeIdx = len(m.accounts) - 1
 				}
 			}
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			// Jump straight to an account, like browser tabs
+			if idx := int(msg.String()[0] - '1'); idx < len(m.accounts) {
+				m.activeIdx = idx
+			}
 		}
 
 	case uiTickMsg:
@@ -350,6 +657,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case clearStatusMsg:
 		m.statusMessage = ""
+
+	case refreshDoneMsg:
+		m.refreshProgress = nil
 	}
 
 	if m.loading {
@@ -360,3 +670,126 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	return m, tea.Batch(cmds...)
 }
+
+// setSize records the terminal size and fits the detail viewport inside it.
+func (m *model) setSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.viewport.Width = max(width-8, 0)
+	m.viewport.Height = max(height-10, 0)
+}
+
+// focusAddressInput moves focus to the given add-address field, wrapping around.
+func (m *model) focusAddressInput(idx int) {
+	n := len(m.addressInputs)
+	m.addressFocusIdx = ((idx % n) + n) % n
+	for i := range m.addressInputs {
+		if i == m.addressFocusIdx {
+			m.addressInputs[i].Focus()
+		} else {
+			m.addressInputs[i].Blur()
+		}
+	}
+}
+
+func (m *model) resetAddressInputs() {
+	for i := range m.addressInputs {
+		m.addressInputs[i].Reset()
+		m.addressInputs[i].Blur()
+	}
+	m.addressFocusIdx = 0
+	m.addressCheck = addressUnchecked
+}
+
+// addressCheck is the live validation state of the address being typed.
+type addressCheck int
+
+const (
+	addressUnchecked addressCheck = iota // Nothing typed yet
+	addressValid
+	addressInvalid
+	addressDuplicate
+	addressList // Several addresses, e.g. a pasted watchlist
+)
+
+func (m model) checkAddress(s string) addressCheck {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "":
+		return addressUnchecked
+	case len(splitAddressList(s)) > 1:
+		return addressList
+	case !common.IsHexAddress(s):
+		return addressInvalid
+	case m.findAccount(s) >= 0:
+		return addressDuplicate
+	}
+	return addressValid
+}
+
+// splitAddressList splits input on whitespace and commas.
+func splitAddressList(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ';' || unicode.IsSpace(r)
+	})
+}
+
+// parseAddressList returns the valid addresses in a pasted list that are not
+// monitored yet, without duplicates, and how many entries were skipped.
+func (m model) parseAddressList(s string) (addrs []string, skipped int) {
+	seen := make(map[string]bool)
+	for _, a := range splitAddressList(s) {
+		key := strings.ToLower(a)
+		if !common.IsHexAddress(a) || seen[key] || m.findAccount(a) >= 0 {
+			skipped++
+			continue
+		}
+		seen[key] = true
+		addrs = append(addrs, a)
+	}
+	return addrs, skipped
+}
+
+// addAddressList adds every new valid address in a pasted list and reports the
+// outcome in the status line.
+func (m *model) addAddressList(s string) {
+	addrs, skipped := m.parseAddressList(s)
+	if len(addrs) == 0 {
+		m.statusMessage = "No new valid addresses found"
+		return
+	}
+	for _, addr := range addrs {
+		m.accounts = append(m.accounts, newAccount(addr, ""))
+		m.watcher.AddAccount(config.AddressConfig{Address: addr})
+	}
+	m.activeIdx = len(m.accounts) - 1
+	m.statusMessage = fmt.Sprintf("Added %d addresses", len(addrs))
+	if skipped > 0 {
+		m.statusMessage += fmt.Sprintf(" (%d invalid or duplicate skipped)", skipped)
+	}
+	if err := m.saveConfig(); err != nil {
+		m.statusMessage = fmt.Sprintf("Failed to save config: %v", err)
+	}
+	m.adding = false
+	m.resetAddressInputs()
+}
+
+// addressCheckView renders the check as a mark to show beside the address input.
+func (m model) addressCheckView() string {
+	switch m.addressCheck {
+	case addressList:
+		addrs, skipped := m.parseAddressList(m.addressInputs[0].Value())
+		mark := infoStyle.Render(fmt.Sprintf("✓ %d new addresses", len(addrs)))
+		if skipped > 0 {
+			mark += " " + warnStyle.Render(fmt.Sprintf("(%d skipped)", skipped))
+		}
+		return mark
+	case addressValid:
+		return infoStyle.Render("✓")
+	case addressInvalid:
+		return errStyle.Render("✗ invalid address")
+	case addressDuplicate:
+		return warnStyle.Render("✗ already monitored")
+	}
+	return ""
+}
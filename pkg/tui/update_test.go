@@ -0,0 +1,471 @@
+package tui
+
+import (
+	"errors"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"evmbal/pkg/config"
+	"evmbal/pkg/models"
+	"evmbal/pkg/utils"
+	"evmbal/pkg/watcher"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdate_NoAccounts(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://localhost:8545"}}}
+	w := watcher.NewWatcher(nil, chains, config.GlobalConfig{}, "")
+	var m tea.Model = initialModel(w, nil, chains, 0, config.GlobalConfig{}, "")
+
+	msgs := []tea.Msg{
+		tea.WindowSizeMsg{Width: 120, Height: 40},
+		uiTickMsg(time.Now()),
+		tea.KeyMsg{Type: tea.KeyTab},
+		tea.KeyMsg{Type: tea.KeyEnter},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Q")},
+	}
+	for _, msg := range msgs {
+		assert.NotPanics(t, func() {
+			m, _ = m.Update(msg)
+			_ = m.View()
+		})
+	}
+	assert.Contains(t, m.View(), "add an address to begin")
+}
+
+func TestUpdate_WatcherEventsReuseSubscription(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://localhost:8545"}}}
+	w := watcher.NewWatcher(nil, chains, config.GlobalConfig{}, "")
+	var m tea.Model = initialModel(w, nil, chains, 0, config.GlobalConfig{}, "")
+
+	for i := 0; i < 3; i++ {
+		m, _ = m.Update(watcher.Event{Type: watcher.EventStatusUpdated})
+	}
+	assert.Equal(t, 1, w.SubscriberCount())
+}
+
+func TestUpdate_ChainDataCopiedIntoOwnAccounts(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH"}}
+	addresses := []config.AddressConfig{{Address: "0xAbC", Name: "Main"}}
+	w := watcher.NewWatcher(addresses, chains, config.GlobalConfig{}, "")
+	var m tea.Model = initialModel(w, addresses, chains, 0, config.GlobalConfig{}, "")
+
+	bal := big.NewFloat(1.5)
+	usdc := big.NewFloat(10)
+	m, _ = m.Update(watcher.Event{Type: watcher.EventChainDataUpdated, Data: models.ChainData{
+		ChainName: "Eth",
+		Results: []models.AccountChainData{
+			{Address: "0xabc", Balance: bal, TokenBalances: map[string]*big.Float{"USDC": usdc}},
+		},
+	}})
+
+	// Mutating the shared payload must not reach the TUI's accounts.
+	bal.SetFloat64(99)
+	usdc.SetFloat64(99)
+
+	acc := m.(model).accounts[0]
+	assert.Equal(t, 1.5, utils.BigFloatToFloat64(acc.Balances["Eth"]))
+	assert.Equal(t, 10.0, utils.BigFloatToFloat64(acc.TokenBalances["Eth"]["USDC"]))
+	assert.True(t, acc.Fetched["Eth"])
+	assert.NotSame(t, w.GetAccounts()[0], acc)
+}
+
+func TestUpdate_PauseUpdates(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH"}}
+	addresses := []config.AddressConfig{{Address: "0xAbC", Name: "Main"}}
+	w := watcher.NewWatcher(addresses, chains, config.GlobalConfig{}, "")
+	var m tea.Model = initialModel(w, addresses, chains, 0, config.GlobalConfig{}, "")
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	assert.True(t, m.(model).updatesPaused)
+	m, _ = m.Update(watcher.Event{Type: watcher.EventChainDataUpdated, Data: models.ChainData{
+		ChainName: "Eth",
+		Results:   []models.AccountChainData{{Address: "0xabc", Balance: big.NewFloat(2)}},
+	}})
+	assert.Nil(t, m.(model).accounts[0].Balances["Eth"], "paused updates are held back")
+	assert.Contains(t, m.View(), "PAUSED (1 pending)")
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	assert.False(t, m.(model).updatesPaused)
+	assert.Equal(t, 2.0, utils.BigFloatToFloat64(m.(model).accounts[0].Balances["Eth"]))
+	assert.Empty(t, m.(model).pausedEvents)
+}
+
+func TestUpdate_ManualRefreshProgress(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Ethereum", Symbol: "ETH"}, {Name: "Base", Symbol: "ETH"}}
+	addresses := []config.AddressConfig{{Address: "0xabc"}}
+	w := watcher.NewWatcher(addresses, chains, config.GlobalConfig{}, "")
+	var m tea.Model = initialModel(w, addresses, chains, 0, config.GlobalConfig{}, "")
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	assert.Equal(t, map[string]int{"Ethereum": 0, "Base": 0}, m.(model).refreshProgress)
+
+	m, _ = m.Update(watcher.Event{Type: watcher.EventFetchStarted, Data: watcher.FetchProgress{ChainName: "Ethereum"}})
+	m, cmd := m.Update(watcher.Event{Type: watcher.EventFetchCompleted, Data: watcher.FetchProgress{ChainName: "Ethereum"}})
+	assert.Contains(t, m.(model).refreshProgressView(), "Ethereum ✓")
+	assert.Contains(t, m.(model).refreshProgressView(), "Base …")
+	assert.NotNil(t, cmd)
+
+	m, _ = m.Update(watcher.Event{Type: watcher.EventFetchCompleted, Data: watcher.FetchProgress{ChainName: "Base", Err: assert.AnError}})
+	assert.Contains(t, m.(model).refreshProgressView(), "Base ✗")
+
+	m, _ = m.Update(refreshDoneMsg{})
+	assert.Empty(t, m.(model).refreshProgressView())
+}
+
+func TestUpdate_AddAddressLiveValidation(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://localhost:8545"}}}
+	addrs := []config.AddressConfig{{Address: "0x1111111111111111111111111111111111111111"}}
+	w := watcher.NewWatcher(addrs, chains, config.GlobalConfig{}, "")
+	var m tea.Model = initialModel(w, addrs, chains, 0, config.GlobalConfig{}, "")
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	assert.Equal(t, addressUnchecked, m.(model).addressCheck)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("0x12")})
+	assert.Equal(t, addressInvalid, m.(model).addressCheck)
+	assert.Contains(t, m.View(), "invalid address")
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("22222222222222222222222222222222222222")})
+	assert.Equal(t, addressValid, m.(model).addressCheck)
+	assert.Contains(t, m.View(), "✓")
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(addrs[0].Address)})
+	assert.Equal(t, addressDuplicate, m.(model).addressCheck)
+}
+
+func TestUpdate_AddAddressList(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://localhost:8545"}}}
+	addrs := []config.AddressConfig{{Address: "0x1111111111111111111111111111111111111111"}}
+	w := watcher.NewWatcher(addrs, chains, config.GlobalConfig{}, "")
+	var m tea.Model = initialModel(w, addrs, chains, 0, config.GlobalConfig{}, filepath.Join(t.TempDir(), "config.json"))
+
+	list := "0x2222222222222222222222222222222222222222, 0x3333333333333333333333333333333333333333\n" +
+		"0x2222222222222222222222222222222222222222 0x1111111111111111111111111111111111111111,nope"
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(list), Paste: true})
+	assert.Equal(t, addressList, m.(model).addressCheck)
+	assert.Contains(t, m.View(), "2 new addresses")
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	got := m.(model)
+	assert.False(t, got.adding)
+	assert.Equal(t, "Added 2 addresses (3 invalid or duplicate skipped)", got.statusMessage)
+	assert.Len(t, got.accounts, 3)
+	assert.Len(t, w.GetAccounts(), 3)
+}
+
+func TestUpdate_AccountErrorBadge(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH"}, {Name: "Base", Symbol: "ETH"}}
+	addrs := []config.AddressConfig{
+		{Address: "0x1111111111111111111111111111111111111111", Name: "Main"},
+		{Address: "0x2222222222222222222222222222222222222222", Name: "Cold"},
+	}
+	w := watcher.NewWatcher(addrs, chains, config.GlobalConfig{}, "")
+	m := initialModel(w, addrs, chains, 0, config.GlobalConfig{}, "")
+	m.setSize(160, 40)
+	m.loading, m.chainLoading["Eth"] = false, false
+	m.accounts[1].Errors["Base"] = errors.New("execution reverted")
+
+	assert.Equal(t, []string{"Base"}, m.erroredChains(m.accounts[1]))
+	assert.Empty(t, m.erroredChains(m.accounts[0]))
+
+	m.showSummary = true
+	assert.Contains(t, m.View(), errorMarker+"Cold")
+	assert.NotContains(t, m.View(), errorMarker+"Main")
+
+	m.showSummary = false
+	m.activeIdx = 1
+	assert.Contains(t, m.View(), errorMarker+"Base", "the main view flags the failing chain while showing Eth")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	assert.Contains(t, m.viewport.View(), "Base: execution reverted")
+}
+
+func TestSummary_MinDisplayValue(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", CoinGeckoID: "ethereum", RPCURLs: []string{"http://localhost:8545"}}}
+	addrs := []config.AddressConfig{
+		{Address: "0x1111111111111111111111111111111111111111", Name: "Main"},
+		{Address: "0x2222222222222222222222222222222222222222", Name: "Dusty"},
+	}
+	cfg := config.GlobalConfig{FiatDecimals: 2, MinDisplayValueUSD: 5}
+	w := watcher.NewWatcher(addrs, chains, cfg, "")
+	m := initialModel(w, addrs, chains, 0, cfg, "")
+	m.width, m.height = 120, 40
+	m.prices["ethereum"] = 2000
+	m.accounts[0].Balances["Eth"] = big.NewFloat(1)
+	m.accounts[1].Balances["Eth"] = big.NewFloat(0.001)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m = updated.(model)
+	assert.True(t, m.showSummary)
+	view := m.View()
+	assert.NotContains(t, view, "Dusty")
+	assert.Contains(t, view, "1 hidden below $5.00")
+	assert.Contains(t, view, "$2,002.00", "hidden accounts still count towards the total")
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	m = updated.(model)
+	view = m.View()
+	assert.Contains(t, view, "Dusty")
+	assert.Less(t, strings.Index(view, "Main"), strings.Index(view, "Dusty"), "rows keep the configured order until sorted")
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	m = updated.(model)
+	view = m.View()
+	assert.Less(t, strings.Index(view, "Dusty"), strings.Index(view, "Main"), "n sorts by name")
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	assert.False(t, updated.(model).showSummary)
+}
+
+func TestUpdate_TokenHistory(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", CoinGeckoID: "ethereum", Tokens: []config.TokenConfig{{Symbol: "USDC", Decimals: 6}}}}
+	addrs := []config.AddressConfig{{Address: "0xAbC", Name: "Main"}}
+	w := watcher.NewWatcher(addrs, chains, config.GlobalConfig{}, "")
+	m := initialModel(w, addrs, chains, 0, config.GlobalConfig{}, "")
+	m.width, m.height = 120, 40
+	m.prices["ethereum"] = 2000
+
+	var updated tea.Model = m
+	for _, usdc := range []float64{10, 25} {
+		updated, _ = updated.Update(watcher.Event{Type: watcher.EventChainDataUpdated, Data: models.ChainData{
+			ChainName: "Eth",
+			Results: []models.AccountChainData{
+				{Address: "0xabc", Balance: big.NewFloat(1), TokenBalances: map[string]*big.Float{"USDC": big.NewFloat(usdc)}},
+			},
+		}})
+	}
+	m = updated.(model)
+	h := m.assetHistory[assetHistoryKey("0xAbC", "Eth", "USDC")]
+	if assert.Len(t, h, 2) {
+		assert.Equal(t, 25.0, h[1].Balance)
+	}
+	assert.Equal(t, 2000.0, m.assetHistory[assetHistoryKey("0xabc", "Eth", "ETH")][0].USD)
+
+	for _, key := range []tea.KeyMsg{{Type: tea.KeyEnter}, {Type: tea.KeyTab}, {Type: tea.KeyRunes, Runes: []rune("g")}} {
+		updated, _ = updated.Update(key)
+	}
+	m = updated.(model)
+	assert.True(t, m.showTokenHistory)
+	assert.Contains(t, m.View(), "USDC on Eth")
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(model)
+	assert.False(t, m.showTokenHistory)
+	assert.True(t, m.showDetail)
+}
+
+func TestUpdate_NextChainKeepsAccount(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH"}, {Name: "Base", Symbol: "ETH"}}
+	addrs := []config.AddressConfig{
+		{Address: "0x1111111111111111111111111111111111111111"},
+		{Address: "0x2222222222222222222222222222222222222222", Name: "Second"},
+	}
+	for _, reset := range []bool{false, true} {
+		cfg := config.GlobalConfig{ResetAccountOnChainSwitch: reset}
+		w := watcher.NewWatcher(addrs, chains, cfg, "")
+		m := initialModel(w, addrs, chains, 0, cfg, "")
+		m.width, m.height = 120, 40
+		m.activeIdx = 1
+
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+		m = updated.(model)
+		assert.Equal(t, 1, m.activeChainIdx)
+		if reset {
+			assert.Equal(t, 0, m.activeIdx)
+			continue
+		}
+		assert.Equal(t, 1, m.activeIdx)
+		assert.Contains(t, m.View(), "Connecting", "the kept account waits for the new chain's data")
+	}
+}
+
+func TestUpdate_ToggleExcludeFromTotal(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", CoinGeckoID: "ethereum", RPCURLs: []string{"http://localhost:8545"}}}
+	addrs := []config.AddressConfig{
+		{Address: "0x1111111111111111111111111111111111111111"},
+		{Address: "0x2222222222222222222222222222222222222222", Name: "Treasury"},
+	}
+	path := filepath.Join(t.TempDir(), "config.json")
+	w := watcher.NewWatcher(addrs, chains, config.GlobalConfig{}, "")
+	m := initialModel(w, addrs, chains, 0, config.GlobalConfig{}, path)
+	m.prices["ethereum"] = 2000
+	for _, acc := range m.accounts {
+		acc.Balances["Eth"] = big.NewFloat(1)
+	}
+	m.activeIdx = 1
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m = updated.(model)
+	assert.True(t, m.accounts[1].ExcludeFromTotal)
+	total, _ := m.calculateTotalPortfolioValue().Float64()
+	assert.Equal(t, 2000.0, total)
+	m.showSummary = true
+	assert.Contains(t, m.View(), excludedMarker+"Treasury")
+
+	saved, _, _, _, err := config.LoadConfigFromFile(path)
+	assert.NoError(t, err)
+	assert.True(t, saved[1].ExcludeFromTotal, "the flag is persisted")
+}
+
+func TestUpdate_EditNote(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://localhost:8545"}}}
+	addrs := []config.AddressConfig{{Address: "0x1111111111111111111111111111111111111111", Name: "Cold"}}
+	path := filepath.Join(t.TempDir(), "config.json")
+	w := watcher.NewWatcher(addrs, chains, config.GlobalConfig{}, "")
+	var m tea.Model = initialModel(w, addrs, chains, 0, config.GlobalConfig{}, path)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("M")})
+	assert.True(t, m.(model).editingAddress)
+	assert.Equal(t, "Cold", m.(model).editAddressInput.Value(), "the name is prefilled")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Ledger, seed in safe")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.False(t, m.(model).editingAddress)
+	assert.Equal(t, "Ledger, seed in safe", m.(model).accounts[0].Note)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Contains(t, m.View(), "Ledger, seed in safe")
+	got := m.(model)
+	got.privacyMode = true
+	assert.NotContains(t, got.View(), "Ledger", "notes are hidden in Privacy Mode")
+
+	saved, _, _, _, err := config.LoadConfigFromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "Ledger, seed in safe", saved[0].Note)
+}
+
+func TestUpdate_NumberKeysJumpToAccount(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://localhost:8545"}}}
+	addrs := []config.AddressConfig{
+		{Address: "0x1111111111111111111111111111111111111111"},
+		{Address: "0x2222222222222222222222222222222222222222"},
+		{Address: "0x3333333333333333333333333333333333333333"},
+	}
+	w := watcher.NewWatcher(addrs, chains, config.GlobalConfig{}, "")
+	var m tea.Model = initialModel(w, addrs, chains, 0, config.GlobalConfig{}, "")
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")})
+	assert.Equal(t, 2, m.(model).activeIdx)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1")})
+	assert.Equal(t, 0, m.(model).activeIdx)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("9")})
+	assert.Equal(t, 0, m.(model).activeIdx, "keys past the last account are ignored")
+}
+
+func TestDashboard(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", CoinGeckoID: "ethereum", RPCURLs: []string{"http://localhost:8545"}}}
+	addrs := []config.AddressConfig{{Address: "0x1111111111111111111111111111111111111111"}}
+	w := watcher.NewWatcher(addrs, chains, config.GlobalConfig{}, "")
+	m := initialModel(w, addrs, chains, 0, config.GlobalConfig{FiatDecimals: 2}, "")
+	m.dashboardMode = true
+	m.width, m.height = 120, 40
+	m.prices["ethereum"] = 2000
+	m.accounts[0].Balances["Eth"] = big.NewFloat(1.5)
+	m.gasPrices["Eth"] = big.NewInt(12_500_000_000)
+
+	view := m.View()
+	assert.Contains(t, view, strings.Split(bigText("$3,000.00"), "\n")[0], "the total is drawn in large figures")
+	assert.Contains(t, view, "12.50 Gwei")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	assert.False(t, updated.(model).adding, "most keybindings are disabled")
+	_, cmd := updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	assert.NotNil(t, cmd)
+}
+
+func TestUpdate_NetworkStatusProbe(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", ChainID: 1, RPCURLs: []string{"http://a.example", "http://b.example"}}}
+	w := watcher.NewWatcher(nil, chains, config.GlobalConfig{}, "")
+	m := initialModel(w, nil, chains, 0, config.GlobalConfig{}, "")
+	m.setSize(160, 40)
+
+	key := func(k string) tea.Cmd {
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		m = updated.(model)
+		return cmd
+	}
+	key("N")
+	assert.True(t, m.showNetworkStatus)
+	key("j")
+	key("j")
+	assert.Equal(t, 1, m.networkStatusIdx, "the cursor stops at the last RPC")
+
+	assert.NotNil(t, key("t"))
+	assert.Equal(t, "http://b.example", m.rpcProbing)
+	assert.Contains(t, m.View(), "testing...")
+
+	updated, _ := m.Update(models.RPCProbeData{RPCURL: "http://b.example", ChainID: 10, BlockNumber: 123})
+	m = updated.(model)
+	assert.Empty(t, m.rpcProbing)
+	view := m.View()
+	assert.Contains(t, view, "RPC Test")
+	assert.Contains(t, view, "(expected 1)")
+
+	key("x")
+	assert.Nil(t, m.rpcProbe, "any key closes the result")
+	assert.True(t, m.showNetworkStatus)
+	key("q")
+	assert.False(t, m.showNetworkStatus)
+
+	key("N")
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	m = updated.(model)
+	assert.False(t, m.showNetworkStatus, "ctrl+c closes the view like q")
+}
+
+func TestUpdate_NetworkStatusDisableRPC(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://a.example", "http://b.example"}}}
+	path := filepath.Join(t.TempDir(), "config.json")
+	w := watcher.NewWatcher(nil, chains, config.GlobalConfig{}, "")
+	var m tea.Model = initialModel(w, nil, chains, 0, config.GlobalConfig{}, path)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+
+	key := func(k string) {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+	}
+	key("N")
+	key("d")
+	assert.Equal(t, []string{"http://a.example"}, m.(model).chains[0].DisabledRPCs)
+	assert.Contains(t, m.View(), "DISABLED")
+	assert.Equal(t, []string{"http://b.example"}, w.GetChains()[0].ActiveRPCURLs(), "the watcher routes around it")
+
+	key("j")
+	key("d")
+	assert.Contains(t, m.(model).statusMessage, "Cannot disable the last RPC")
+
+	_, saved, _, _, err := config.LoadConfigFromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"http://a.example"}, saved[0].DisabledRPCs, "the choice survives a restart")
+
+	key("k")
+	key("d")
+	assert.Empty(t, m.(model).chains[0].DisabledRPCs)
+	assert.Empty(t, chains[0].DisabledRPCs, "the caller's chains are not modified")
+}
+
+func TestView_RPCLabelSkipsDisabled(t *testing.T) {
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://off.example", "http://on.example"}, DisabledRPCs: []string{"http://off.example"}}}
+	addrs := []config.AddressConfig{{Address: "0x1111111111111111111111111111111111111111", Name: "Main"}}
+	w := watcher.NewWatcher(addrs, chains, config.GlobalConfig{}, "")
+	m := initialModel(w, addrs, chains, 0, config.GlobalConfig{}, "")
+	m.setSize(160, 40)
+	m.compactMode = false
+	m.accounts[0].Balances["Eth"] = big.NewFloat(1)
+
+	view := m.View()
+	assert.Contains(t, view, "RPC: http://on.example")
+	assert.NotContains(t, view, "off.example")
+}
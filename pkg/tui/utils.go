@@ -1,9 +1,13 @@
 package tui
 
 import (
+	"net/url"
 	"os/exec"
 	"runtime"
+	"strings"
 
+	"evmbal/pkg/config"
+	"evmbal/pkg/models"
 	"evmbal/pkg/utils"
 
 	"math/big"
@@ -13,7 +17,72 @@ func (m model) displayValue(f *big.Float, decimals int) string {
 	if m.privacyMode {
 		return "****"
 	}
-	return utils.FormatBigFloat(f, decimals)
+	if m.config.CompactNumbers {
+		return utils.FormatCompactRounded(f, decimals, m.rounding())
+	}
+	return utils.FormatRounded(f, decimals, m.rounding())
+}
+
+// rounding returns the configured rounding. main rejects unknown modes at
+// startup, so a bad one here can only come from a reload and rounds normally.
+func (m model) rounding() utils.Rounding {
+	truncate, _ := utils.ParseRoundingMode(m.config.RoundingMode)
+	return utils.Rounding{Truncate: truncate, MarkTiny: m.config.MarkTinyValues}
+}
+
+// fiat places the configured currency symbol around a formatted amount.
+func (m model) fiat(amount string) string {
+	return formatFiat(amount, m.config)
+}
+
+// formatFiat places cfg's currency symbol before the amount ("$1,234.56"), or
+// after it with a space when FiatSymbolSuffix is set ("1.234,56 kr"). A "< "
+// or "> " marker from MarkTiny stays in front ("< $0.01").
+func formatFiat(amount string, cfg config.GlobalConfig) string {
+	symbol := cfg.FiatSymbol
+	if symbol == "" {
+		symbol = "$"
+	}
+	marker := ""
+	if strings.HasPrefix(amount, "< ") || strings.HasPrefix(amount, "> ") {
+		marker, amount = amount[:2], amount[2:]
+	}
+	if cfg.FiatSymbolSuffix {
+		return marker + amount + " " + symbol
+	}
+	return marker + symbol + amount
+}
+
+// rpcHost shortens an RPC URL to its host for status messages, leaving out any
+// API key in the path.
+func rpcHost(rpcURL string) string {
+	if u, err := url.Parse(rpcURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return rpcURL
+}
+
+// tokenDisplayDecimals returns the number of decimals used to render a token balance.
+func (m model) tokenDisplayDecimals(t config.TokenConfig) int {
+	if t.DisplayDecimals != nil {
+		return *t.DisplayDecimals
+	}
+	return m.config.TokenDecimals
+}
+
+// showTokenBalance reports whether the main view lists a token balance, honouring
+// HideZeroBalances. The detail view leaves out zero balances regardless.
+func (m model) showTokenBalance(bal *big.Float) bool {
+	return bal != nil && (!m.config.HideZeroBalances || bal.Sign() != 0)
+}
+
+// flatSeries returns n copies of v, used to draw horizontal reference lines in graphs.
+func flatSeries(v float64, n int) []float64 {
+	s := make([]float64, n)
+	for i := range s {
+		s[i] = v
+	}
+	return s
 }
 
 func (m model) maskString(s string) string {
@@ -23,6 +92,14 @@ func (m model) maskString(s string) string {
 	return s
 }
 
+// txValue renders a transaction's value, with the token symbol for token transfers.
+func (m model) txValue(tx models.Transaction) string {
+	if tx.TokenSymbol != "" {
+		return m.maskString(tx.Value) + " " + tx.TokenSymbol
+	}
+	return m.maskString(tx.Value)
+}
+
 func (m model) maskAddress(addr string) string {
 	if m.privacyMode {
 		return "0x**...**"
@@ -47,3 +124,27 @@ func openBrowser(url string) error {
 	args = append(args, url)
 	return exec.Command(cmd, args...).Start()
 }
+
+const (
+	defaultMainViewTxCount = 3
+	// mainViewReservedLines is the height of the main view without transaction
+	// rows: top bar, box, account details, table header and footer.
+	mainViewReservedLines = 18
+)
+
+// mainViewTxCount returns how many transactions the main view lists: the
+// configured count, reduced so the box still fits the terminal.
+func (m model) mainViewTxCount() int {
+	n := m.config.MainViewTxCount
+	if n <= 0 {
+		n = defaultMainViewTxCount
+	}
+	if m.height > 0 {
+		avail := m.height - mainViewReservedLines
+		if m.statusMessage != "" {
+			avail--
+		}
+		n = min(n, max(avail, 1))
+	}
+	return n
+}
@@ -2,8 +2,11 @@ package utils
 
 import (
 	"fmt"
+	"math"
 	"math/big"
+	"strconv"
 	"strings"
+	"sync"
 )
 
 func TruncateString(str string, num int) string {
@@ -16,10 +19,67 @@ func TruncateString(str string, num int) string {
 	return str[0:num-3] + "..."
 }
 
+// NumberFormat holds the separators used when rendering numbers.
+type NumberFormat struct {
+	Thousands string
+	Decimal   string
+}
+
+var (
+	// NumberFormatUS renders 1,234.56.
+	NumberFormatUS = NumberFormat{Thousands: ",", Decimal: "."}
+	// NumberFormatEU renders 1.234,56.
+	NumberFormatEU = NumberFormat{Thousands: ".", Decimal: ","}
+)
+
+var (
+	numberFormatMu sync.RWMutex
+	numberFormat   = NumberFormatUS
+)
+
+// SetNumberFormat selects the separators used by AddCommas, FormatFloat,
+// FormatBigFloat and FormatCompact: "us" (the default when empty) or "eu".
+func SetNumberFormat(name string) error {
+	nf, err := ParseNumberFormat(name)
+	if err != nil {
+		return err
+	}
+	numberFormatMu.Lock()
+	numberFormat = nf
+	numberFormatMu.Unlock()
+	return nil
+}
+
+// ParseNumberFormat returns the separators for a number_format config value.
+func ParseNumberFormat(name string) (NumberFormat, error) {
+	switch strings.ToLower(name) {
+	case "", "us":
+		return NumberFormatUS, nil
+	case "eu":
+		return NumberFormatEU, nil
+	}
+	return NumberFormat{}, fmt.Errorf("unknown number format %q (use \"us\" or \"eu\")", name)
+}
+
+func currentNumberFormat() NumberFormat {
+	numberFormatMu.RLock()
+	defer numberFormatMu.RUnlock()
+	return numberFormat
+}
+
+// AddCommas groups the integer part of a plain decimal string such as
+// "1234.56" using the separators chosen with SetNumberFormat.
 func AddCommas(s string) string {
+	return AddSeparators(s, currentNumberFormat())
+}
+
+// AddSeparators groups the integer part of a plain decimal string such as
+// "1234.56" and swaps in nf's decimal separator.
+func AddSeparators(s string, nf NumberFormat) string {
 	if len(s) == 0 {
 		return s
 	}
+
 	parts := strings.Split(s, ".")
 	integerPart := parts[0]
 	sign := ""
@@ -28,29 +88,30 @@ func AddCommas(s string) string {
 		integerPart = integerPart[1:]
 	}
 
-	n := len(integerPart)
-	if n <= 3 {
-		return s
-	}
-
 	var result strings.Builder
 	result.WriteString(sign)
-	remainder := n % 3
-	if remainder > 0 {
-		result.WriteString(integerPart[:remainder])
-		result.WriteString(",")
-	}
-	for i := remainder; i < n; i += 3 {
-		if i > remainder {
-			result.WriteString(",")
+	n := len(integerPart)
+	if n <= 3 {
+		result.WriteString(integerPart)
+	} else {
+		remainder := n % 3
+		if remainder > 0 {
+			result.WriteString(integerPart[:remainder])
+			result.WriteString(nf.Thousands)
+		}
+		for i := remainder; i < n; i += 3 {
+			if i > remainder {
+				result.WriteString(nf.Thousands)
+			}
+			result.WriteString(integerPart[i : i+3])
 		}
-		result.WriteString(integerPart[i : i+3])
 	}
 
 	if len(parts) > 1 {
-		result.WriteString(".")
+		result.WriteString(nf.Decimal)
 		result.WriteString(parts[1])
 	}
+
 	return result.String()
 }
 
@@ -58,13 +119,146 @@ func FormatFloat(f float64, decimals int) string {
 	return AddCommas(fmt.Sprintf("%.*f", decimals, f))
 }
 
+// FormatBigFloat formats f with decimals places and thousands separators. An
+// infinite value, which only arises from an overflow, is shown as "∞".
 func FormatBigFloat(f *big.Float, decimals int) string {
 	if f == nil {
 		return "0"
 	}
+	if f.IsInf() {
+		if f.Sign() < 0 {
+			return "-∞"
+		}
+		return "∞"
+	}
 	return AddCommas(f.Text('f', decimals))
 }
 
+// Rounding controls how FormatRounded fits a value to the displayed decimals.
+type Rounding struct {
+	Truncate bool // Cut off extra digits instead of rounding half to even
+	MarkTiny bool // Show nonzero values that would read as zero as "< 0.01"
+}
+
+// ParseRoundingMode reports whether a rounding_mode config value asks for
+// truncation: "round" (the default when empty) or "truncate".
+func ParseRoundingMode(name string) (truncate bool, err error) {
+	switch strings.ToLower(name) {
+	case "", "round":
+		return false, nil
+	case "truncate":
+		return true, nil
+	}
+	return false, fmt.Errorf("unknown rounding mode %q (use \"round\" or \"truncate\")", name)
+}
+
+// FormatRounded is FormatBigFloat with a choice of rounding. Text('f', ...)
+// rounds half to even, so e.g. 0.005 can show as 0.00 and a small balance
+// seems to vanish; r.MarkTiny shows such values as "< 0.01" instead.
+func FormatRounded(f *big.Float, decimals int, r Rounding) string {
+	if f == nil || f.IsInf() {
+		return FormatBigFloat(f, decimals)
+	}
+	s := roundedText(f, decimals, r.Truncate)
+	if r.MarkTiny && f.Sign() != 0 && strings.Trim(s, "-0.") == "" {
+		smallest := "1"
+		if decimals > 0 {
+			smallest = "0." + strings.Repeat("0", decimals-1) + "1"
+		}
+		if f.Sign() < 0 {
+			return "> -" + AddCommas(smallest)
+		}
+		return "< " + AddCommas(smallest)
+	}
+	return AddCommas(s)
+}
+
+// roundedText renders f with decimals places, rounding half to even or
+// truncating toward zero.
+func roundedText(f *big.Float, decimals int, truncate bool) string {
+	if !truncate {
+		return f.Text('f', decimals)
+	}
+	// Cut the shortest exact decimal form, so 0.29 stays 0.29 rather than
+	// becoming 0.28 from its binary approximation 0.28999...
+	s := f.Text('f', -1)
+	intPart, frac, _ := strings.Cut(s, ".")
+	if decimals <= 0 {
+		return intPart
+	}
+	if len(frac) > decimals {
+		frac = frac[:decimals]
+	}
+	return intPart + "." + frac + strings.Repeat("0", decimals-len(frac))
+}
+
+var compactSuffixes = []struct {
+	threshold *big.Float
+	suffix    string
+}{
+	{big.NewFloat(1e12), "T"},
+	{big.NewFloat(1e9), "B"},
+	{big.NewFloat(1e6), "M"},
+	{big.NewFloat(1e3), "K"},
+}
+
+// FormatCompact renders large values with K/M/B/T suffixes and values too small
+// to show at the given precision in significant-figure notation (e.g. 1.23e-9).
+// Everything in between is formatted like FormatBigFloat.
+func FormatCompact(f *big.Float, decimals int) string {
+	return FormatCompactRounded(f, decimals, Rounding{})
+}
+
+// FormatCompactRounded is FormatCompact with a choice of rounding. Tiny values
+// are already shown in significant-figure notation, so r.MarkTiny has no effect.
+func FormatCompactRounded(f *big.Float, decimals int, r Rounding) string {
+	if f == nil || f.Sign() == 0 {
+		return FormatBigFloat(f, decimals)
+	}
+	if f.IsInf() {
+		return FormatBigFloat(f, decimals)
+	}
+
+	abs := new(big.Float).Abs(f)
+	sign := ""
+	if f.Sign() < 0 {
+		sign = "-"
+	}
+
+	for _, s := range compactSuffixes {
+		if abs.Cmp(s.threshold) >= 0 {
+			scaled := new(big.Float).Quo(abs, s.threshold)
+			return sign + AddCommas(roundedText(scaled, decimals, r.Truncate)) + s.suffix
+		}
+	}
+
+	smallest := new(big.Float).Quo(big.NewFloat(1), new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+	if abs.Cmp(smallest) < 0 {
+		mantissa, exp, _ := strings.Cut(abs.Text('e', 2), "e")
+		e, err := strconv.Atoi(exp)
+		if err != nil {
+			return sign + abs.Text('e', 2)
+		}
+		return fmt.Sprintf("%s%se%d", sign, strings.Replace(mantissa, ".", currentNumberFormat().Decimal, 1), e)
+	}
+
+	return FormatRounded(f, decimals, r)
+}
+
+// Float64 converts f for float64-only consumers such as graphs and JSON. ok is
+// false if f is nil or too large for a float64, in which case the result is
+// ±math.MaxFloat64 (0 for nil) rather than ±Inf, which JSON cannot encode.
+func Float64(f *big.Float) (v float64, ok bool) {
+	if f == nil {
+		return 0, false
+	}
+	v, _ = f.Float64()
+	if math.IsInf(v, 0) {
+		return math.Copysign(math.MaxFloat64, v), false
+	}
+	return v, true
+}
+
 func BigFloatToFloat64(f *big.Float) float64 {
 	if f == nil {
 		return 0
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"math"
 	"math/big"
 	"testing"
 )
@@ -76,6 +77,8 @@ func TestFormatBigFloat(t *testing.T) {
 	}{
 		{big.NewFloat(1234.5678), 2, "1,234.57"},
 		{nil, 2, "0"},
+		{new(big.Float).SetInf(false), 2, "∞"},
+		{new(big.Float).SetInf(true), 2, "-∞"},
 	}
 
 	for _, tt := range tests {
@@ -85,3 +88,113 @@ func TestFormatBigFloat(t *testing.T) {
 		}
 	}
 }
+
+func TestFormatCompact(t *testing.T) {
+	tests := []struct {
+		input    *big.Float
+		decimals int
+		expected string
+	}{
+		{big.NewFloat(1.23e-9), 2, "1.23e-9"},
+		{big.NewFloat(1234567), 2, "1.23M"},
+		{big.NewFloat(4.5e12), 2, "4.50T"},
+		{big.NewFloat(-2500), 1, "-2.5K"},
+		{big.NewFloat(12.345), 2, "12.35"},
+		{big.NewFloat(0), 2, "0.00"},
+		{nil, 2, "0"},
+		{new(big.Float).SetInf(false), 2, "∞"},
+	}
+
+	for _, tt := range tests {
+		result := FormatCompact(tt.input, tt.decimals)
+		if result != tt.expected {
+			t.Errorf("FormatCompact(%v, %d) = %q; want %q", tt.input, tt.decimals, result, tt.expected)
+		}
+	}
+}
+
+func TestNumberFormatEU(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"123", "123"},
+		{"123.4", "123,4"},
+		{"1234.56", "1.234,56"},
+		{"-1234567.8", "-1.234.567,8"},
+	}
+	for _, tt := range tests {
+		if got := AddSeparators(tt.input, NumberFormatEU); got != tt.expected {
+			t.Errorf("AddSeparators(%q, EU) = %q; want %q", tt.input, got, tt.expected)
+		}
+	}
+
+	if err := SetNumberFormat("eu"); err != nil {
+		t.Fatalf("SetNumberFormat failed: %v", err)
+	}
+	t.Cleanup(func() { _ = SetNumberFormat("us") })
+
+	if got := FormatFloat(1234.56, 2); got != "1.234,56" {
+		t.Errorf("FormatFloat = %q; want 1.234,56", got)
+	}
+	if got := FormatBigFloat(big.NewFloat(1234567.891), 2); got != "1.234.567,89" {
+		t.Errorf("FormatBigFloat = %q; want 1.234.567,89", got)
+	}
+	if got := FormatCompact(big.NewFloat(1500000), 2); got != "1,50M" {
+		t.Errorf("FormatCompact = %q; want 1,50M", got)
+	}
+	if err := SetNumberFormat("fr"); err == nil {
+		t.Error("Expected an error for an unknown number format")
+	}
+	if got := FormatFloat(1234.56, 2); got != "1.234,56" {
+		t.Errorf("An invalid format should leave the current one in place, got %q", got)
+	}
+}
+
+func TestFloat64(t *testing.T) {
+	if v, ok := Float64(big.NewFloat(12.5)); !ok || v != 12.5 {
+		t.Errorf("Float64(12.5) = %v, %v; want 12.5, true", v, ok)
+	}
+	huge, _ := new(big.Float).SetString("1e400")
+	if v, ok := Float64(huge); ok || v != math.MaxFloat64 {
+		t.Errorf("Float64(1e400) = %v, %v; want MaxFloat64, false", v, ok)
+	}
+	if v, ok := Float64(nil); ok || v != 0 {
+		t.Errorf("Float64(nil) = %v, %v; want 0, false", v, ok)
+	}
+}
+
+func TestFormatRounded(t *testing.T) {
+	tests := []struct {
+		input    *big.Float
+		decimals int
+		r        Rounding
+		expected string
+	}{
+		{big.NewFloat(1234.5678), 2, Rounding{}, "1,234.57"},
+		{big.NewFloat(1234.5678), 2, Rounding{Truncate: true}, "1,234.56"},
+		{big.NewFloat(0.29), 2, Rounding{Truncate: true}, "0.29"},
+		{big.NewFloat(7.9), 0, Rounding{Truncate: true}, "7"},
+		{big.NewFloat(0.5), 3, Rounding{Truncate: true}, "0.500"},
+		{big.NewFloat(0.004), 2, Rounding{}, "0.00"},
+		{big.NewFloat(0.004), 2, Rounding{MarkTiny: true}, "< 0.01"},
+		{big.NewFloat(0.009), 2, Rounding{Truncate: true, MarkTiny: true}, "< 0.01"},
+		{big.NewFloat(-0.004), 2, Rounding{MarkTiny: true}, "> -0.01"},
+		{big.NewFloat(0), 2, Rounding{MarkTiny: true}, "0.00"},
+		{nil, 2, Rounding{MarkTiny: true}, "0"},
+	}
+
+	for _, tt := range tests {
+		result := FormatRounded(tt.input, tt.decimals, tt.r)
+		if result != tt.expected {
+			t.Errorf("FormatRounded(%v, %d, %+v) = %q; want %q", tt.input, tt.decimals, tt.r, result, tt.expected)
+		}
+	}
+
+	if got := FormatCompactRounded(big.NewFloat(1239000), 2, Rounding{Truncate: true}); got != "1.23M" {
+		t.Errorf("FormatCompactRounded(1239000, truncate) = %q; want %q", got, "1.23M")
+	}
+	if _, err := ParseRoundingMode("ceil"); err == nil {
+		t.Error("ParseRoundingMode(\"ceil\") should fail")
+	}
+}
@@ -7,10 +7,20 @@ const (
 	EventPriceUpdated        EventType = "price_updated"
 	EventChainDataUpdated    EventType = "chain_data_updated"
 	EventGasPriceUpdated     EventType = "gas_price_updated"
+	EventGasAlert            EventType = "gas_alert"
+	EventRPCHealthUpdated    EventType = "rpc_health_updated"
 	EventTransactionsUpdated EventType = "transactions_updated"
 	EventStatusUpdated       EventType = "status_updated"
+	EventFetchStarted        EventType = "fetch_started"
+	EventFetchCompleted      EventType = "fetch_completed"
 )
 
+// FetchProgress is the payload of EventFetchStarted and EventFetchCompleted.
+type FetchProgress struct {
+	ChainName string
+	Err       error // Set on EventFetchCompleted when the fetch failed
+}
+
 // Event represents a monitoring event.
 type Event struct {
 	Type EventType
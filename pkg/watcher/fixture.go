@@ -0,0 +1,196 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"evmbal/pkg/config"
+	"evmbal/pkg/models"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// FixturePrice is a recorded CoinGecko price.
+type FixturePrice struct {
+	Price     float64 `json:"price"`
+	Change24h float64 `json:"change_24h,omitempty"`
+}
+
+// FixtureChain is a recorded balance fetch for one chain. Error, when set, is
+// returned as the fetch error instead of the results.
+type FixtureChain struct {
+	Results []models.AccountChainData `json:"results,omitempty"`
+	Error   string                    `json:"error,omitempty"`
+}
+
+// FixtureStep is one snapshot of everything a DataSource serves. A fixture
+// directory holds one step per .json file, replayed in file name order.
+type FixtureStep struct {
+	Prices         map[string]FixturePrice                    `json:"prices,omitempty"`          // Key: CoinGecko ID
+	Chains         map[string]FixtureChain                    `json:"chains,omitempty"`          // Key: Chain Name
+	Gas            map[string]*big.Int                        `json:"gas,omitempty"`             // Key: Chain Name; price in wei
+	Transactions   map[string]map[string][]models.Transaction `json:"transactions,omitempty"`    // Key: Chain Name -> lowercase address
+	TokenTransfers map[string]map[string][]models.Transaction `json:"token_transfers,omitempty"` // Key: Chain Name -> lowercase address
+}
+
+// LoadFixtures reads every .json file in dir as a FixtureStep, in file name order.
+func LoadFixtures(dir string) ([]FixtureStep, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no fixture files in %s", dir)
+	}
+
+	steps := make([]FixtureStep, 0, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		var step FixtureStep
+		if err := json.Unmarshal(data, &step); err != nil {
+			return nil, fmt.Errorf("failed to parse fixture %s: %w", name, err)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// FixtureDataSource serves recorded data instead of querying RPCs and CoinGecko,
+// e.g. for demos and tests. Each chain, coin and address advances independently
+// through the steps that contain it, one step per fetch, and stays on the last
+// such step once reached, so a single step serves a static snapshot.
+type FixtureDataSource struct {
+	steps  []FixtureStep
+	chains []config.ChainConfig
+
+	mu     sync.Mutex
+	cursor map[string]int // Key: fetch kind and target; next step to serve
+}
+
+// NewFixtureDataSource loads the fixture steps in dir. chains maps the RPC URLs
+// passed to FetchGasPrice back to a chain name.
+func NewFixtureDataSource(dir string, chains []config.ChainConfig) (*FixtureDataSource, error) {
+	steps, err := LoadFixtures(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &FixtureDataSource{steps: steps, chains: chains, cursor: make(map[string]int)}, nil
+}
+
+// next returns the step to serve for key among the steps for which has is true,
+// and advances key's cursor. It returns false if no step has the key.
+func (d *FixtureDataSource) next(key string, has func(FixtureStep) bool) (FixtureStep, bool) {
+	var matches []int
+	for i, s := range d.steps {
+		if has(s) {
+			matches = append(matches, i)
+		}
+	}
+	if len(matches) == 0 {
+		return FixtureStep{}, false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	i := d.cursor[key]
+	if i < len(matches)-1 {
+		d.cursor[key] = i + 1
+	}
+	return d.steps[matches[i]], true
+}
+
+func (d *FixtureDataSource) FetchEthPrice(coinID string) (models.PriceData, error) {
+	step, ok := d.next("price:"+coinID, func(s FixtureStep) bool { _, ok := s.Prices[coinID]; return ok })
+	if !ok {
+		return models.PriceData{}, fmt.Errorf("no fixture price for %s", coinID)
+	}
+	p := step.Prices[coinID]
+	return models.PriceData{CoinID: coinID, Price: p.Price, Change24h: p.Change24h}, nil
+}
+
+func (d *FixtureDataSource) FetchChainData(chain config.ChainConfig, accounts []*models.Account) (models.ChainData, error) {
+	step, ok := d.next("chain:"+chain.Name, func(s FixtureStep) bool { _, ok := s.Chains[chain.Name]; return ok })
+	if !ok {
+		return models.ChainData{}, fmt.Errorf("no fixture balances for chain %s", chain.Name)
+	}
+	fc := step.Chains[chain.Name]
+	if fc.Error != "" {
+		return models.ChainData{ChainName: chain.Name, Err: errors.New(fc.Error)}, nil
+	}
+	return models.ChainData{ChainName: chain.Name, Results: fc.Results}, nil
+}
+
+func (d *FixtureDataSource) FetchGasPrice(rpcURLs []string, _ time.Duration) (models.GasPriceData, error) {
+	name := chainNameForRPCs(d.chains, rpcURLs)
+	step, ok := d.next("gas:"+name, func(s FixtureStep) bool { return s.Gas[name] != nil })
+	if !ok {
+		return models.GasPriceData{}, fmt.Errorf("no fixture gas price for chain %q", name)
+	}
+	return models.GasPriceData{ChainName: name, Price: new(big.Int).Set(step.Gas[name])}, nil
+}
+
+func (d *FixtureDataSource) FetchTransactions(address string, chain config.ChainConfig, decimals int) ([]models.Transaction, []string, error) {
+	addr := strings.ToLower(address)
+	step, _ := d.next("txs:"+chain.Name+":"+addr, func(s FixtureStep) bool { _, ok := s.Transactions[chain.Name][addr]; return ok })
+	return step.Transactions[chain.Name][addr], nil, nil
+}
+
+func (d *FixtureDataSource) FetchTokenTransfers(address string, chain config.ChainConfig, decimals int) ([]models.Transaction, error) {
+	addr := strings.ToLower(address)
+	step, _ := d.next("transfers:"+chain.Name+":"+addr, func(s FixtureStep) bool { _, ok := s.TokenTransfers[chain.Name][addr]; return ok })
+	return step.TokenTransfers[chain.Name][addr], nil
+}
+
+// SubscribeNewHeads never delivers a head; replayed chains only update on polls.
+func (d *FixtureDataSource) SubscribeNewHeads(ctx context.Context, wsURL string) (<-chan *types.Header, error) {
+	heads := make(chan *types.Header)
+	go func() {
+		<-ctx.Done()
+		close(heads)
+	}()
+	return heads, nil
+}
+
+// FetchRPCLatency reports every RPC as healthy with a current head.
+func (d *FixtureDataSource) FetchRPCLatency(rpcURL string, timeout time.Duration) (models.RPCLatencyData, error) {
+	return models.RPCLatencyData{RPCURL: rpcURL, Latency: time.Millisecond, BlockTime: time.Now()}, nil
+}
+
+// FetchChainlinkPrice fails; replays only use the recorded CoinGecko prices.
+func (d *FixtureDataSource) FetchChainlinkPrice(rpcURL, feedAddr string, timeout time.Duration) (float64, error) {
+	return 0, fmt.Errorf("no fixture price feeds")
+}
+
+// IsContract reports no code, so replayed addresses all show as wallets.
+func (d *FixtureDataSource) IsContract(rpcURL, address string, timeout time.Duration) (bool, error) {
+	return false, nil
+}
+
+// chainNameForRPCs returns the name of the chain that owns the first of rpcURLs,
+// or "" if none does.
+func chainNameForRPCs(chains []config.ChainConfig, rpcURLs []string) string {
+	if len(rpcURLs) == 0 {
+		return ""
+	}
+	for _, c := range chains {
+		for _, u := range c.RPCURLs {
+			if u == rpcURLs[0] {
+				return c.Name
+			}
+		}
+	}
+	return ""
+}
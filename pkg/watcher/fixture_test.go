@@ -0,0 +1,74 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"evmbal/pkg/config"
+	"evmbal/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFixture(t *testing.T, dir, name, content string) {
+	t.Helper()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+}
+
+func TestFixtureDataSource_Steps(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "001.json", `{
+		"prices": {"ethereum": {"price": 2000, "change_24h": 1.5}},
+		"chains": {"Eth": {"results": [{"Address": "0xABC", "Balance": "1.5", "TokenBalances": {"USDC": "10"}}]}},
+		"gas": {"Eth": 20000000000},
+		"transactions": {"Eth": {"0xabc": [{"Hash": "0x1", "BlockNumber": 7}]}}
+	}`)
+	writeFixture(t, dir, "002.json", `{
+		"prices": {"ethereum": {"price": 2100}},
+		"chains": {"Eth": {"error": "rpc down"}},
+		"gas": {"Eth": 30000000000}
+	}`)
+
+	chains := []config.ChainConfig{{Name: "Eth", RPCURLs: []string{"http://a", "http://b"}}}
+	ds, err := NewFixtureDataSource(dir, chains)
+	assert.NoError(t, err)
+
+	price, err := ds.FetchEthPrice("ethereum")
+	assert.NoError(t, err)
+	assert.Equal(t, 2000.0, price.Price)
+	assert.Equal(t, 1.5, price.Change24h)
+
+	data, err := ds.FetchChainData(chains[0], nil)
+	assert.NoError(t, err)
+	assert.Len(t, data.Results, 1)
+	assert.Equal(t, 1.5, utils.BigFloatToFloat64(data.Results[0].Balance))
+	assert.Equal(t, 10.0, utils.BigFloatToFloat64(data.Results[0].TokenBalances["USDC"]))
+
+	gas, err := ds.FetchGasPrice([]string{"http://b", "http://a"}, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "Eth", gas.ChainName)
+	assert.Equal(t, int64(20000000000), gas.Price.Int64())
+
+	txs, _, err := ds.FetchTransactions("0xABC", chains[0], 18)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 1)
+
+	// Second fetches serve the next step, and later ones stay on the last step.
+	for i := 0; i < 2; i++ {
+		price, _ = ds.FetchEthPrice("ethereum")
+		assert.Equal(t, 2100.0, price.Price)
+		data, err = ds.FetchChainData(chains[0], nil)
+		assert.NoError(t, err)
+		assert.EqualError(t, data.Err, "rpc down")
+	}
+
+	_, err = ds.FetchEthPrice("bitcoin")
+	assert.Error(t, err)
+}
+
+func TestNewFixtureDataSource_EmptyDir(t *testing.T) {
+	_, err := NewFixtureDataSource(t.TempDir(), nil)
+	assert.Error(t, err)
+}
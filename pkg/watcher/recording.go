@@ -0,0 +1,183 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"evmbal/pkg/config"
+	"evmbal/pkg/models"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// RecordingDataSource forwards every call to another DataSource and saves the
+// results as fixture steps that FixtureDataSource can replay. A new timestamped
+// step file is started whenever a value already recorded in the current step is
+// fetched again, so each file roughly holds one polling round.
+type RecordingDataSource struct {
+	inner  DataSource
+	dir    string
+	chains []config.ChainConfig
+
+	mu     sync.Mutex
+	step   FixtureStep
+	file   string // Path of the step being recorded
+	seq    int    // Steps started so far
+	logger *slog.Logger
+}
+
+// NewRecordingDataSource records inner's results into dir, creating it if needed.
+// chains maps the RPC URLs passed to FetchGasPrice back to a chain name.
+func NewRecordingDataSource(inner DataSource, dir string, chains []config.ChainConfig) (*RecordingDataSource, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create record directory: %w", err)
+	}
+	return &RecordingDataSource{
+		inner:  inner,
+		dir:    dir,
+		chains: chains,
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}, nil
+}
+
+// SetLogger sets where fixture write failures are reported.
+func (d *RecordingDataSource) SetLogger(l *slog.Logger) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.logger = l
+}
+
+// record applies add to the current step and rewrites its file. If recorded
+// reports the value is already in the current step, a new step is started first.
+func (d *RecordingDataSource) record(recorded func(FixtureStep) bool, add func(*FixtureStep)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.file == "" || recorded(d.step) {
+		d.step = FixtureStep{}
+		d.file = d.nextFile()
+	}
+	add(&d.step)
+
+	data, err := json.MarshalIndent(d.step, "", "  ")
+	if err == nil {
+		err = os.WriteFile(d.file, data, 0644)
+	}
+	if err != nil {
+		d.logger.Error("fixture write failed", "path", d.file, "err", err)
+	}
+}
+
+// nextFile returns a new timestamped step file name. The sequence number keeps
+// file name order even if the clock does not advance between steps.
+func (d *RecordingDataSource) nextFile() string {
+	d.seq++
+	return filepath.Join(d.dir, fmt.Sprintf("%s-%06d.json", time.Now().UTC().Format("20060102T150405.000000000Z"), d.seq))
+}
+
+func (d *RecordingDataSource) FetchEthPrice(coinID string) (models.PriceData, error) {
+	data, err := d.inner.FetchEthPrice(coinID)
+	if err == nil {
+		d.record(func(s FixtureStep) bool { _, ok := s.Prices[coinID]; return ok }, func(s *FixtureStep) {
+			if s.Prices == nil {
+				s.Prices = make(map[string]FixturePrice)
+			}
+			s.Prices[coinID] = FixturePrice{Price: data.Price, Change24h: data.Change24h}
+		})
+	}
+	return data, err
+}
+
+func (d *RecordingDataSource) FetchChainData(chain config.ChainConfig, accounts []*models.Account) (models.ChainData, error) {
+	data, err := d.inner.FetchChainData(chain, accounts)
+	fc := FixtureChain{Results: data.Results}
+	if err != nil {
+		fc = FixtureChain{Error: err.Error()}
+	} else if data.Err != nil {
+		fc = FixtureChain{Error: data.Err.Error()}
+	}
+	d.record(func(s FixtureStep) bool { _, ok := s.Chains[chain.Name]; return ok }, func(s *FixtureStep) {
+		if s.Chains == nil {
+			s.Chains = make(map[string]FixtureChain)
+		}
+		s.Chains[chain.Name] = fc
+	})
+	return data, err
+}
+
+func (d *RecordingDataSource) FetchGasPrice(rpcURLs []string, timeout time.Duration) (models.GasPriceData, error) {
+	data, err := d.inner.FetchGasPrice(rpcURLs, timeout)
+	if err == nil && data.Price != nil {
+		name := chainNameForRPCs(d.chains, rpcURLs)
+		price := new(big.Int).Set(data.Price)
+		d.record(func(s FixtureStep) bool { return s.Gas[name] != nil }, func(s *FixtureStep) {
+			if s.Gas == nil {
+				s.Gas = make(map[string]*big.Int)
+			}
+			s.Gas[name] = price
+		})
+	}
+	return data, err
+}
+
+func (d *RecordingDataSource) FetchTransactions(address string, chain config.ChainConfig, decimals int) ([]models.Transaction, []string, error) {
+	txs, failed, err := d.inner.FetchTransactions(address, chain, decimals)
+	if err == nil {
+		addr := strings.ToLower(address)
+		d.record(func(s FixtureStep) bool { _, ok := s.Transactions[chain.Name][addr]; return ok }, func(s *FixtureStep) {
+			s.Transactions = addRecordedTxs(s.Transactions, chain.Name, addr, txs)
+		})
+	}
+	return txs, failed, err
+}
+
+func (d *RecordingDataSource) FetchTokenTransfers(address string, chain config.ChainConfig, decimals int) ([]models.Transaction, error) {
+	txs, err := d.inner.FetchTokenTransfers(address, chain, decimals)
+	if err == nil {
+		addr := strings.ToLower(address)
+		d.record(func(s FixtureStep) bool { _, ok := s.TokenTransfers[chain.Name][addr]; return ok }, func(s *FixtureStep) {
+			s.TokenTransfers = addRecordedTxs(s.TokenTransfers, chain.Name, addr, txs)
+		})
+	}
+	return txs, err
+}
+
+func (d *RecordingDataSource) SubscribeNewHeads(ctx context.Context, wsURL string) (<-chan *types.Header, error) {
+	return d.inner.SubscribeNewHeads(ctx, wsURL)
+}
+
+func (d *RecordingDataSource) FetchRPCLatency(rpcURL string, timeout time.Duration) (models.RPCLatencyData, error) {
+	return d.inner.FetchRPCLatency(rpcURL, timeout)
+}
+
+func (d *RecordingDataSource) FetchChainlinkPrice(rpcURL, feedAddr string, timeout time.Duration) (float64, error) {
+	return d.inner.FetchChainlinkPrice(rpcURL, feedAddr, timeout)
+}
+
+// IsContract is not recorded; replays show every address as a wallet.
+func (d *RecordingDataSource) IsContract(rpcURL, address string, timeout time.Duration) (bool, error) {
+	return d.inner.IsContract(rpcURL, address, timeout)
+}
+
+func addRecordedTxs(m map[string]map[string][]models.Transaction, chainName, addr string, txs []models.Transaction) map[string]map[string][]models.Transaction {
+	if m == nil {
+		m = make(map[string]map[string][]models.Transaction)
+	}
+	if m[chainName] == nil {
+		m[chainName] = make(map[string][]models.Transaction)
+	}
+	if txs == nil {
+		txs = []models.Transaction{}
+	}
+	m[chainName][addr] = txs
+	return m
+}
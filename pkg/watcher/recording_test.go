@@ -0,0 +1,68 @@
+package watcher
+
+import (
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"evmbal/pkg/config"
+	"evmbal/pkg/models"
+	"evmbal/pkg/rpc"
+	"evmbal/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRecordingDataSource_RoundTrip(t *testing.T) {
+	mockDS := new(MockDataSource)
+	chain := config.ChainConfig{Name: "Eth", RPCURLs: []string{"http://a"}}
+	mockDS.On("FetchEthPrice", "ethereum").Return(models.PriceData{CoinID: "ethereum", Price: 2000.0}, nil).Once()
+	mockDS.On("FetchEthPrice", "ethereum").Return(models.PriceData{CoinID: "ethereum", Price: 2100.0}, nil).Once()
+	mockDS.On("FetchChainData", chain, mock.Anything).Return(models.ChainData{
+		ChainName: "Eth",
+		Results:   []models.AccountChainData{{Address: "0xABC", Balance: big.NewFloat(1.5)}},
+	}, nil).Once()
+	mockDS.On("FetchChainData", chain, mock.Anything).Return(models.ChainData{}, errors.New("rpc down")).Once()
+	mockDS.On("FetchGasPrice", chain.RPCURLs, rpc.DefaultRequestTimeout).Return(models.GasPriceData{Price: big.NewInt(5)}, nil)
+	mockDS.On("FetchTransactions", "0xABC", chain, 18).Return([]models.Transaction{{Hash: "0x1"}}, []string{}, nil)
+
+	dir := t.TempDir()
+	rec, err := NewRecordingDataSource(mockDS, dir, []config.ChainConfig{chain})
+	assert.NoError(t, err)
+
+	// Two rounds: the repeated fetches start a second step file.
+	for i := 0; i < 2; i++ {
+		_, _ = rec.FetchEthPrice("ethereum")
+		_, _ = rec.FetchChainData(chain, nil)
+	}
+	_, _ = rec.FetchGasPrice(chain.RPCURLs, rpc.DefaultRequestTimeout)
+	txs, _, err := rec.FetchTransactions("0xABC", chain, 18)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 1, "results are forwarded unchanged")
+	mockDS.AssertExpectations(t)
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	assert.Len(t, files, 2)
+
+	replay, err := NewFixtureDataSource(dir, []config.ChainConfig{chain})
+	assert.NoError(t, err)
+
+	price, _ := replay.FetchEthPrice("ethereum")
+	assert.Equal(t, 2000.0, price.Price)
+	data, err := replay.FetchChainData(chain, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, utils.BigFloatToFloat64(data.Results[0].Balance))
+
+	price, _ = replay.FetchEthPrice("ethereum")
+	assert.Equal(t, 2100.0, price.Price)
+	data, _ = replay.FetchChainData(chain, nil)
+	assert.EqualError(t, data.Err, "rpc down")
+
+	gas, err := replay.FetchGasPrice(chain.RPCURLs, rpc.DefaultRequestTimeout)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), gas.Price.Int64())
+	txs, _, _ = replay.FetchTransactions("0xabc", chain, 18)
+	assert.Equal(t, "0x1", txs[0].Hash)
+}
@@ -2,21 +2,57 @@ package watcher
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"log/slog"
 	"math/big"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"evmbal/pkg/config"
 	"evmbal/pkg/models"
 	"evmbal/pkg/rpc"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// PollInterval is how often every enabled chain is refetched.
+const PollInterval = 30 * time.Second
+
+// Timing for the websocket new-heads mode.
+var (
+	// headRefetchInterval throttles balance refetches on fast chains that produce blocks every second or two.
+	headRefetchInterval = 5 * time.Second
+	// headResubscribeDelay is how long to wait before re-subscribing after a websocket drops.
+	headResubscribeDelay = 15 * time.Second
+)
+
+// healthCheckInterval is how often every enabled chain's RPCs are measured. The
+// checks run apart from the fetches, which use the latest results.
+var healthCheckInterval = PollInterval
+
+// Thresholds for treating an RPC as lagging.
+var (
+	// maxHeadAge is how far an RPC's head timestamp may trail time.Now().
+	maxHeadAge = 60 * time.Second
+	// maxBlocksBehind is how many blocks an RPC may trail the best head seen across the chain's RPCs.
+	maxBlocksBehind uint64 = 5
 )
 
 // DataSource defines the interface for fetching data.
 type DataSource interface {
 	FetchEthPrice(coinID string) (models.PriceData, error)
 	FetchChainData(chain config.ChainConfig, accounts []*models.Account) (models.ChainData, error)
-	FetchGasPrice(rpcURLs []string) (models.GasPriceData, error)
-	FetchTransactions(address string, rpcURLs []string, decimals int) ([]models.Transaction, []string, error)
+	FetchGasPrice(rpcURLs []string, timeout time.Duration) (models.GasPriceData, error)
+	FetchTransactions(address string, chain config.ChainConfig, decimals int) ([]models.Transaction, []string, error)
+	FetchTokenTransfers(address string, chain config.ChainConfig, decimals int) ([]models.Transaction, error)
+	SubscribeNewHeads(ctx context.Context, wsURL string) (<-chan *types.Header, error)
+	FetchRPCLatency(rpcURL string, timeout time.Duration) (models.RPCLatencyData, error)
+	FetchChainlinkPrice(rpcURL, feedAddr string, timeout time.Duration) (float64, error)
+	IsContract(rpcURL, address string, timeout time.Duration) (bool, error)
 }
 
 // RealDataSource implements DataSource using the rpc package.
@@ -30,12 +66,32 @@ func (d *RealDataSource) FetchChainData(chain config.ChainConfig, accounts []*mo
 	return rpc.FetchChainData(chain, accounts)
 }
 
-func (d *RealDataSource) FetchGasPrice(rpcURLs []string) (models.GasPriceData, error) {
-	return rpc.FetchGasPrice(rpcURLs)
+func (d *RealDataSource) FetchGasPrice(rpcURLs []string, timeout time.Duration) (models.GasPriceData, error) {
+	return rpc.FetchGasPrice(rpcURLs, timeout)
+}
+
+func (d *RealDataSource) FetchTransactions(address string, chain config.ChainConfig, decimals int) ([]models.Transaction, []string, error) {
+	return rpc.FetchTransactions(address, chain, decimals)
+}
+
+func (d *RealDataSource) FetchTokenTransfers(address string, chain config.ChainConfig, decimals int) ([]models.Transaction, error) {
+	return rpc.FetchTokenTransfers(address, chain, 1, decimals)
+}
+
+func (d *RealDataSource) SubscribeNewHeads(ctx context.Context, wsURL string) (<-chan *types.Header, error) {
+	return rpc.SubscribeNewHeads(ctx, wsURL)
+}
+
+func (d *RealDataSource) FetchRPCLatency(rpcURL string, timeout time.Duration) (models.RPCLatencyData, error) {
+	return rpc.FetchRPCLatency(rpcURL, timeout)
 }
 
-func (d *RealDataSource) FetchTransactions(address string, rpcURLs []string, decimals int) ([]models.Transaction, []string, error) {
-	return rpc.FetchTransactions(address, rpcURLs, decimals)
+func (d *RealDataSource) FetchChainlinkPrice(rpcURL, feedAddr string, timeout time.Duration) (float64, error) {
+	return rpc.FetchChainlinkPrice(rpcURL, feedAddr, timeout)
+}
+
+func (d *RealDataSource) IsContract(rpcURL, address string, timeout time.Duration) (bool, error) {
+	return rpc.IsContract(rpcURL, address, timeout)
 }
 
 // Watcher manages background monitoring and state.
@@ -47,12 +103,30 @@ type Watcher struct {
 
 	prices    map[string]float64
 	gasPrices map[string]*big.Int
+	gasAlerts map[string]bool                  // Key: Chain Name; true while gas is below the alert threshold
+	rpcHealth map[string]models.RPCLatencyData // Key: RPC URL
+	contracts map[string]bool                  // Key: chain name + "/" + lowercase address; present once checked
+	lastGood  map[string]string                // Key: Chain Name; RPC that last served the chain without failing
+	lastFetch time.Time                        // Last chain fetch without error
+	nextPoll  time.Time                        // When the polling loop fetches next; zero until it starts
+	paused    bool                             // Polling and new-head refetches are skipped while set
 	accounts  []*models.Account
 
+	runCtx   context.Context    // Set by Start; new-heads loops run under it
+	headSubs map[string]headSub // Key: Chain Name; the running new-heads loop
+
 	subscribers []Subscriber
 	mu          sync.RWMutex
 	stopChan    chan struct{}
+	refreshChan chan struct{}
 	dataSource  DataSource
+	logger      *slog.Logger
+}
+
+// headSub is a running newHeadsLoop and how to stop it.
+type headSub struct {
+	url    string
+	cancel context.CancelFunc
 }
 
 // NewWatcher creates a new Watcher instance.
@@ -66,22 +140,44 @@ func NewWatcher(addresses []config.AddressConfig, chains []config.ChainConfig, g
 			TokenBalances: make(map[string]map[string]*big.Float),
 			Balances24h:   make(map[string]*big.Float),
 			Errors:        make(map[string]error),
+			Fetched:       make(map[string]bool),
 		})
 	}
 
 	return &Watcher{
-		config:     globalCfg,
-		addresses:  addresses,
-		chains:     chains,
-		configPath: configPath,
-		prices:     make(map[string]float64),
-		gasPrices:  make(map[string]*big.Int),
-		accounts:   accounts,
-		stopChan:   make(chan struct{}),
-		dataSource: &RealDataSource{},
+		config:      globalCfg,
+		addresses:   addresses,
+		chains:      chains,
+		configPath:  configPath,
+		prices:      make(map[string]float64),
+		gasPrices:   make(map[string]*big.Int),
+		gasAlerts:   make(map[string]bool),
+		rpcHealth:   make(map[string]models.RPCLatencyData),
+		contracts:   make(map[string]bool),
+		lastGood:    make(map[string]string),
+		headSubs:    make(map[string]headSub),
+		accounts:    accounts,
+		stopChan:    make(chan struct{}),
+		refreshChan: make(chan struct{}, 1),
+		dataSource:  &RealDataSource{},
+		logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
 	}
 }
 
+// SetLogger sets where the watcher records fetch failures and alerts.
+func (w *Watcher) SetLogger(l *slog.Logger) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.logger = l
+}
+
+// Logger returns the watcher's logger so other components can share it.
+func (w *Watcher) Logger() *slog.Logger {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.logger
+}
+
 // SetDataSource allows overriding the data source (useful for testing).
 func (w *Watcher) SetDataSource(ds DataSource) {
 	w.mu.Lock()
@@ -98,6 +194,13 @@ func (w *Watcher) Subscribe() Subscriber {
 	return ch
 }
 
+// SubscriberCount returns the number of active subscribers.
+func (w *Watcher) SubscriberCount() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return len(w.subscribers)
+}
+
 // Unsubscribe removes a subscriber.
 func (w *Watcher) Unsubscribe(ch Subscriber) {
 	w.mu.Lock()
@@ -123,9 +226,128 @@ func (w *Watcher) notify(event Event) {
 	}
 }
 
-// Start begins the monitoring loops.
+// Start begins the monitoring loops. Chains with a websocket RPC URL additionally
+// refetch balances on every new block; all chains keep the polling loop as fallback.
 func (w *Watcher) Start(ctx context.Context) {
 	go w.pollingLoop(ctx)
+	go w.healthLoop(ctx)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.runCtx = ctx
+	w.syncHeadSubs()
+}
+
+// syncHeadSubs runs one newHeadsLoop per enabled chain with an active websocket
+// RPC, stopping loops of chains that were removed, disabled or lost their
+// websocket RPC. Nothing runs before Start. The caller must hold w.mu.
+func (w *Watcher) syncHeadSubs() {
+	if w.runCtx == nil {
+		return
+	}
+	want := make(map[string]string) // Key: Chain Name; websocket URL
+	for _, c := range w.chains {
+		if !c.IsEnabled() {
+			continue
+		}
+		for _, u := range c.ActiveRPCURLs() {
+			if rpc.IsWebsocketURL(u) {
+				want[c.Name] = u
+				break
+			}
+		}
+	}
+	for name, sub := range w.headSubs {
+		if want[name] != sub.url {
+			sub.cancel()
+			delete(w.headSubs, name)
+		}
+	}
+	for name, u := range want {
+		if _, ok := w.headSubs[name]; ok {
+			continue
+		}
+		ctx, cancel := context.WithCancel(w.runCtx)
+		w.headSubs[name] = headSub{url: u, cancel: cancel}
+		go w.newHeadsLoop(ctx, name, u)
+	}
+}
+
+// newHeadsLoop refetches a chain's balances whenever the websocket endpoint reports
+// a new block, re-subscribing after headResubscribeDelay if the subscription drops.
+func (w *Watcher) newHeadsLoop(ctx context.Context, chainName, wsURL string) {
+	for {
+		heads, err := w.dataSource.SubscribeNewHeads(ctx, wsURL)
+		if err != nil {
+			w.Logger().Warn("new heads subscription failed", "chain", chainName, "rpc", wsURL, "err", err)
+		} else {
+			var lastFetch time.Time
+			for range heads {
+				if time.Since(lastFetch) < headRefetchInterval || w.Paused() {
+					continue
+				}
+				lastFetch = time.Now()
+				if chain, ok := w.chainByName(chainName); ok && chain.IsEnabled() {
+					chain.RPCURLs = w.chainRPCs(chain)
+					w.fetchChainData(chain)
+				}
+			}
+		}
+
+		select {
+		case <-time.After(headResubscribeDelay):
+		case <-w.stopChan:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (w *Watcher) chainByName(name string) (config.ChainConfig, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	for _, c := range w.chains {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return config.ChainConfig{}, false
+}
+
+// Refresh asks the polling loop to fetch everything now instead of waiting for the
+// next tick. Requests made while a fetch is already queued are merged.
+func (w *Watcher) Refresh() {
+	select {
+	case w.refreshChan <- struct{}{}:
+	default:
+	}
+}
+
+// Pause stops periodic polling and new-head refetches until Resume is called, to
+// save RPC quota while nobody is looking. Explicit Refresh calls still fetch.
+func (w *Watcher) Pause() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.paused = true
+}
+
+// Resume restarts polling after Pause and fetches immediately so the data is fresh.
+func (w *Watcher) Resume() {
+	w.mu.Lock()
+	wasPaused := w.paused
+	w.paused = false
+	w.mu.Unlock()
+	if wasPaused {
+		w.Refresh()
+	}
+}
+
+// Paused reports whether polling is paused.
+func (w *Watcher) Paused() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.paused
 }
 
 // Stop stops the monitoring loops.
@@ -137,12 +359,18 @@ func (w *Watcher) pollingLoop(ctx context.Context) {
 	// Initial fetch
 	w.fetchAll()
 
-	ticker := time.NewTicker(30 * time.Second)
+	ticker := time.NewTicker(PollInterval)
 	defer ticker.Stop()
+	w.setNextPoll(time.Now().Add(PollInterval))
 
 	for {
 		select {
 		case <-ticker.C:
+			w.setNextPoll(time.Now().Add(PollInterval))
+			if !w.Paused() {
+				w.fetchAll()
+			}
+		case <-w.refreshChan:
 			w.fetchAll()
 		case <-w.stopChan:
 			return
@@ -155,15 +383,33 @@ func (w *Watcher) pollingLoop(ctx context.Context) {
 func (w *Watcher) fetchAll() {
 	var wg sync.WaitGroup
 
+	w.mu.RLock()
+	var chains []config.ChainConfig
+	for _, c := range w.chains {
+		if c.IsEnabled() {
+			c.RPCURLs = c.ActiveRPCURLs()
+			chains = append(chains, c)
+		}
+	}
+	w.mu.RUnlock()
+	accounts := w.GetAccounts()
+
 	// Fetch Prices
 	uniqueCoinIDs := make(map[string]bool)
-	for _, chain := range w.chains {
+	feeds := make(map[string]priceFeed)
+	for _, chain := range chains {
 		if chain.CoinGeckoID != "" {
 			uniqueCoinIDs[chain.CoinGeckoID] = true
+			if chain.PriceFeed != "" {
+				feeds[chain.CoinGeckoID] = priceFeed{rpcURLs: chain.RPCURLs, address: chain.PriceFeed, timeout: chain.Timeout(rpc.DefaultRequestTimeout)}
+			}
 		}
 		for _, t := range chain.Tokens {
-			if t.CoinGeckoID != "" {
-				uniqueCoinIDs[t.CoinGeckoID] = true
+			if id := t.PriceID(); id != "" {
+				uniqueCoinIDs[id] = true
+				if t.PriceFeed != "" {
+					feeds[id] = priceFeed{rpcURLs: chain.RPCURLs, address: t.PriceFeed, timeout: chain.Timeout(rpc.DefaultRequestTimeout)}
+				}
 			}
 		}
 	}
@@ -173,49 +419,79 @@ func (w *Watcher) fetchAll() {
 		go func(coinID string) {
 			defer wg.Done()
 			data, err := w.dataSource.FetchEthPrice(coinID)
+			if feed, ok := feeds[coinID]; err != nil && ok {
+				var price float64
+				if price, err = w.feedPrice(feed); err == nil {
+					w.Logger().Info("price from chainlink feed", "coin", coinID, "feed", feed.address, "price", price)
+					data = models.PriceData{CoinID: coinID, Price: price}
+				}
+			}
 			if err == nil {
 				w.mu.Lock()
+				if prev, ok := w.prices[coinID]; ok && prev > 0 {
+					switch {
+					case data.Price > prev:
+						data.Trend = 1
+					case data.Price < prev:
+						data.Trend = -1
+					}
+				}
 				w.prices[coinID] = data.Price
 				w.mu.Unlock()
+				data.UpdatedAt = time.Now()
 				w.notify(Event{Type: EventPriceUpdated, Data: data})
 			}
 		}(id)
 	}
 
 	// Fetch Chain Data (Balances)
-	for _, chain := range w.chains {
+	for _, chain := range chains {
+		chain.RPCURLs = w.chainRPCs(chain)
 		wg.Add(1)
 		go func(c config.ChainConfig) {
 			defer wg.Done()
-			data, err := w.dataSource.FetchChainData(c, w.accounts)
-			if err == nil {
-				w.updateAccountsWithChainData(data)
-				w.notify(Event{Type: EventChainDataUpdated, Data: data})
-			}
+			w.fetchChainData(c)
 		}(chain)
 
 		wg.Add(1)
 		go func(c config.ChainConfig) {
 			defer wg.Done()
-			data, err := w.dataSource.FetchGasPrice(c.RPCURLs)
-			if err == nil {
+			data, err := w.dataSource.FetchGasPrice(c.RPCURLs, c.Timeout(rpc.DefaultRequestTimeout))
+			if err == nil && data.Price != nil {
+				w.rememberGoodRPC(c, data.FailedRPCs)
+				data.ChainName = c.Name
 				w.mu.Lock()
 				w.gasPrices[c.Name] = data.Price
 				w.mu.Unlock()
 				w.notify(Event{Type: EventGasPriceUpdated, Data: data})
+				w.checkGasAlert(c, data.Price)
 			}
 		}(chain)
 
-		for _, acc := range w.accounts {
+		for _, acc := range accounts {
 			wg.Add(1)
 			go func(c config.ChainConfig, address string) {
 				defer wg.Done()
-				txs, _, err := w.dataSource.FetchTransactions(address, c.RPCURLs, w.config.TokenDecimals)
+				txs, _, err := w.dataSource.FetchTransactions(address, c, w.config.TokenDecimals)
+				var transfers []models.Transaction
+				if err == nil && c.ExplorerAPIURL != "" {
+					var terr error
+					if transfers, terr = w.dataSource.FetchTokenTransfers(address, c, w.config.TokenDecimals); terr != nil {
+						w.Logger().Warn("token transfer fetch failed", "chain", c.Name, "address", address, "err", terr)
+					}
+				}
+				// Data sources may hand out shared slices, so the entries are tagged on a copy.
+				txs = append(slices.Clone(txs), transfers...)
+				for i := range txs {
+					txs[i].Chain = c.Name
+				}
 				if err == nil {
 					w.mu.Lock()
 					for _, a := range w.accounts {
 						if a.Address == address {
-							a.Transactions = txs
+							// Replace this chain's entries and keep the other chains'.
+							kept := slices.DeleteFunc(slices.Clone(a.Transactions), func(tx models.Transaction) bool { return tx.Chain == c.Name })
+							a.Transactions = append(kept, txs...)
 							break
 						}
 					}
@@ -232,9 +508,293 @@ func (w *Watcher) fetchAll() {
 	wg.Wait()
 }
 
+// healthLoop checks RPC health right away and then every healthCheckInterval,
+// so a slow RPC never holds up a fetch. Checks are skipped while paused.
+func (w *Watcher) healthLoop(ctx context.Context) {
+	w.checkAllRPCHealth()
+
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if !w.Paused() {
+				w.checkAllRPCHealth()
+			}
+		case <-w.stopChan:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// checkAllRPCHealth runs checkRPCHealth for every enabled chain at once.
+func (w *Watcher) checkAllRPCHealth() {
+	w.mu.RLock()
+	var chains []config.ChainConfig
+	for _, c := range w.chains {
+		if c.IsEnabled() {
+			c.RPCURLs = c.ActiveRPCURLs()
+			chains = append(chains, c)
+		}
+	}
+	w.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, chain := range chains {
+		wg.Add(1)
+		go func(c config.ChainConfig) {
+			defer wg.Done()
+			w.checkRPCHealth(c)
+		}(chain)
+	}
+	wg.Wait()
+}
+
+// checkRPCHealth measures every RPC of a chain and flags those whose head is older
+// than maxHeadAge or more than maxBlocksBehind behind the chain's best head. Such RPCs
+// answer quickly but serve stale balances, so latency alone would keep them on top.
+func (w *Watcher) checkRPCHealth(chain config.ChainConfig) {
+	results := make([]models.RPCLatencyData, len(chain.RPCURLs))
+	var wg sync.WaitGroup
+	for i, u := range chain.RPCURLs {
+		wg.Add(1)
+		go func(i int, u string) {
+			defer wg.Done()
+			data, err := w.dataSource.FetchRPCLatency(u, chain.Timeout(rpc.DefaultLatencyTimeout))
+			data.RPCURL = u
+			data.Err = err
+			results[i] = data
+		}(i, u)
+	}
+	wg.Wait()
+
+	var best uint64
+	for _, r := range results {
+		if r.Err == nil && r.BlockNumber > best {
+			best = r.BlockNumber
+		}
+	}
+	now := time.Now()
+	for i, r := range results {
+		if r.Err != nil {
+			continue
+		}
+		if now.Sub(r.BlockTime) > maxHeadAge || best-r.BlockNumber > maxBlocksBehind {
+			results[i].Lagging = true
+			w.Logger().Warn("rpc lagging", "chain", chain.Name, "rpc", r.RPCURL, "block", r.BlockNumber, "block_time", r.BlockTime)
+		}
+	}
+
+	w.mu.Lock()
+	for _, r := range results {
+		w.rpcHealth[r.RPCURL] = r
+	}
+	w.mu.Unlock()
+
+	for _, r := range results {
+		w.notify(Event{Type: EventRPCHealthUpdated, Data: r})
+	}
+}
+
+// prioritizedRPCs orders urls healthy-first by latency, followed by RPCs not yet
+// checked, lagging RPCs and finally failing ones. The sort is stable so configured
+// order breaks ties.
+func (w *Watcher) prioritizedRPCs(urls []string) []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	rank := func(u string) int {
+		h, ok := w.rpcHealth[u]
+		switch {
+		case !ok:
+			return 1
+		case h.Err != nil:
+			return 3
+		case h.Lagging:
+			return 2
+		}
+		return 0
+	}
+	out := append([]string(nil), urls...)
+	sort.SliceStable(out, func(i, j int) bool {
+		ri, rj := rank(out[i]), rank(out[j])
+		if ri != rj {
+			return ri < rj
+		}
+		if ri == 0 {
+			return w.rpcHealth[out[i]].Latency < w.rpcHealth[out[j]].Latency
+		}
+		return false
+	})
+	return out
+}
+
+// chainRPCs orders a chain's RPCs for the next fetch: the one that last served
+// it without failing comes first, so a flaky RPC early in the list does not
+// cost a timeout every cycle, unless the health check found it failing or
+// lagging. The rest follow in prioritizedRPCs order. Disabled RPCs are left out.
+func (w *Watcher) chainRPCs(c config.ChainConfig) []string {
+	urls := w.prioritizedRPCs(c.ActiveRPCURLs())
+	w.mu.RLock()
+	good := w.lastGood[c.Name]
+	health, checked := w.rpcHealth[good]
+	w.mu.RUnlock()
+	if good == "" || (checked && (health.Err != nil || health.Lagging)) {
+		return urls
+	}
+	for i, u := range urls {
+		if u == good {
+			copy(urls[1:i+1], urls[:i])
+			urls[0] = good
+			break
+		}
+	}
+	return urls
+}
+
+// rememberGoodRPC records the first of c.RPCURLs, as ordered for the fetch,
+// that is not in failed as the chain's last good RPC.
+func (w *Watcher) rememberGoodRPC(c config.ChainConfig, failed []string) {
+	for _, u := range c.RPCURLs {
+		if !slices.Contains(failed, u) {
+			w.mu.Lock()
+			w.lastGood[c.Name] = u
+			w.mu.Unlock()
+			return
+		}
+	}
+}
+
+// priceFeed is a Chainlink feed configured for a coin, with the RPCs and
+// request timeout of the chain it lives on.
+type priceFeed struct {
+	rpcURLs []string
+	address string
+	timeout time.Duration
+}
+
+// feedPrice reads feed through its chain's RPCs, healthiest first. It stands in
+// for CoinGecko when a price cannot be fetched, so the last price is not kept
+// around indefinitely while CoinGecko is down.
+func (w *Watcher) feedPrice(feed priceFeed) (float64, error) {
+	err := fmt.Errorf("no RPC URLs for price feed %s", feed.address)
+	for _, rpcURL := range w.prioritizedRPCs(feed.rpcURLs) {
+		var price float64
+		if price, err = w.dataSource.FetchChainlinkPrice(rpcURL, feed.address, feed.timeout); err == nil {
+			return price, nil
+		}
+	}
+	return 0, err
+}
+
+// checkGasAlert emits EventGasAlert when gas crosses below the chain's threshold.
+// It fires once per crossing and re-arms when gas rises back to the threshold.
+func (w *Watcher) checkGasAlert(chain config.ChainConfig, price *big.Int) {
+	if chain.GasAlertGwei <= 0 {
+		return
+	}
+	gwei, _ := new(big.Float).Quo(new(big.Float).SetInt(price), big.NewFloat(1e9)).Float64()
+	below := gwei < chain.GasAlertGwei
+
+	w.mu.Lock()
+	fire := below && !w.gasAlerts[chain.Name]
+	w.gasAlerts[chain.Name] = below
+	w.mu.Unlock()
+
+	if fire {
+		w.Logger().Info("gas alert", "chain", chain.Name, "gwei", gwei, "threshold", chain.GasAlertGwei)
+		w.notify(Event{Type: EventGasAlert, Data: models.GasAlert{
+			ChainName:     chain.Name,
+			GasGwei:       gwei,
+			ThresholdGwei: chain.GasAlertGwei,
+		}})
+	}
+}
+
+// fetchChainData fetches balances for one chain and publishes the result. It
+// runs from both the polling and the new-heads loop, so it works on a snapshot
+// of the accounts taken under the lock.
+func (w *Watcher) fetchChainData(c config.ChainConfig) {
+	w.notify(Event{Type: EventFetchStarted, Data: FetchProgress{ChainName: c.Name}})
+	data, err := w.dataSource.FetchChainData(c, w.GetAccounts())
+	if err != nil {
+		data = models.ChainData{ChainName: c.Name, Err: err}
+	}
+	if data.Err != nil {
+		w.Logger().Error("chain fetch failed", "chain", c.Name, "failed_rpcs", data.FailedRPCs, "err", data.Err)
+		w.mu.Lock()
+		delete(w.lastGood, c.Name)
+		w.mu.Unlock()
+	} else {
+		w.mu.Lock()
+		w.lastFetch = time.Now()
+		w.mu.Unlock()
+		w.rememberGoodRPC(c, data.FailedRPCs)
+		w.markContracts(c, &data)
+	}
+	w.updateAccountsWithChainData(data)
+	w.notify(Event{Type: EventChainDataUpdated, Data: data})
+	w.notify(Event{Type: EventFetchCompleted, Data: FetchProgress{ChainName: c.Name, Err: data.Err}})
+}
+
+// markContracts sets IsContract on data's results. Each address's code is only
+// looked up once per chain, as it practically never changes; failed lookups are
+// retried on the next fetch. Lookups run in parallel, so a long watchlist does
+// not hold back the first balances by one RPC round trip per address.
+func (w *Watcher) markContracts(c config.ChainConfig, data *models.ChainData) {
+	var wg sync.WaitGroup
+	for i := range data.Results {
+		res := &data.Results[i]
+		key := c.Name + "/" + strings.ToLower(res.Address)
+		w.mu.RLock()
+		isContract, checked := w.contracts[key]
+		w.mu.RUnlock()
+		if checked {
+			res.IsContract = isContract
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			isContract, err := w.checkContract(c, res.Address)
+			if err != nil {
+				w.Logger().Warn("contract check failed", "chain", c.Name, "address", res.Address, "err", err)
+				return
+			}
+			w.mu.Lock()
+			w.contracts[key] = isContract
+			w.mu.Unlock()
+			res.IsContract = isContract
+		}()
+	}
+	wg.Wait()
+}
+
+// checkContract looks up address's code through the chain's RPCs, healthiest first.
+func (w *Watcher) checkContract(c config.ChainConfig, address string) (bool, error) {
+	err := fmt.Errorf("no RPC URLs for %s", c.Name)
+	for _, rpcURL := range w.prioritizedRPCs(c.RPCURLs) {
+		var isContract bool
+		if isContract, err = w.dataSource.IsContract(rpcURL, address, c.Timeout(rpc.DefaultRequestTimeout)); err == nil {
+			return isContract, nil
+		}
+	}
+	return false, err
+}
+
 func (w *Watcher) updateAccountsWithChainData(data models.ChainData) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
+	if data.Err != nil {
+		for _, acc := range w.accounts {
+			if acc.Errors == nil {
+				acc.Errors = make(map[string]error)
+			}
+			acc.Errors[data.ChainName] = data.Err
+		}
+	}
 	for _, res := range data.Results {
 		for _, acc := range w.accounts {
 			if acc.Address == res.Address {
@@ -252,18 +812,114 @@ func (w *Watcher) updateAccountsWithChainData(data models.ChainData) {
 				for sym, bal := range res.TokenBalances {
 					acc.TokenBalances[data.ChainName][sym] = bal
 				}
+				delete(acc.Errors, data.ChainName)
+				if acc.Fetched == nil {
+					acc.Fetched = make(map[string]bool)
+				}
+				acc.Fetched[data.ChainName] = true
+				if res.IsContract {
+					acc.IsContract = true
+				}
 				break
 			}
 		}
 	}
 }
 
-// GetAccounts returns a copy of the current accounts state.
+// AddAccount starts monitoring a new address. It is a no-op if the address is already watched.
+func (w *Watcher) AddAccount(addr config.AddressConfig) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, a := range w.accounts {
+		if strings.EqualFold(a.Address, addr.Address) {
+			return
+		}
+	}
+	w.addresses = append(w.addresses, addr)
+	w.accounts = append(w.accounts, &models.Account{
+		Address:       addr.Address,
+		Name:          addr.Name,
+		Balances:      make(map[string]*big.Float),
+		TokenBalances: make(map[string]map[string]*big.Float),
+		Balances24h:   make(map[string]*big.Float),
+		Errors:        make(map[string]error),
+		Fetched:       make(map[string]bool),
+	})
+}
+
+// RemoveAccount stops monitoring an address.
+func (w *Watcher) RemoveAccount(address string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for i, a := range w.addresses {
+		if strings.EqualFold(a.Address, address) {
+			w.addresses = append(w.addresses[:i], w.addresses[i+1:]...)
+			break
+		}
+	}
+	for i, a := range w.accounts {
+		if strings.EqualFold(a.Address, address) {
+			w.accounts = append(w.accounts[:i], w.accounts[i+1:]...)
+			break
+		}
+	}
+}
+
+// SetChains replaces the monitored chain configuration, e.g. after tokens are
+// edited, and starts or stops new-heads subscriptions to match it.
+func (w *Watcher) SetChains(chains []config.ChainConfig) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.chains = make([]config.ChainConfig, len(chains))
+	for i, c := range chains {
+		c.Tokens = append([]config.TokenConfig(nil), c.Tokens...)
+		w.chains[i] = c
+	}
+	w.syncHeadSubs()
+}
+
+func (w *Watcher) setNextPoll(t time.Time) {
+	w.mu.Lock()
+	w.nextPoll = t
+	w.mu.Unlock()
+}
+
+// NextPoll returns when the polling loop will next refetch every chain, or the
+// zero time while polling is paused or has not started.
+func (w *Watcher) NextPoll() time.Time {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.paused {
+		return time.Time{}
+	}
+	return w.nextPoll
+}
+
+// LastSuccessfulFetch returns when chain data was last fetched without error, or the
+// zero time if no fetch has succeeded yet.
+func (w *Watcher) LastSuccessfulFetch() time.Time {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.lastFetch
+}
+
+// GetAccounts returns a deep copy of the current accounts state, safe to read while
+// the watcher keeps fetching.
 func (w *Watcher) GetAccounts() []*models.Account {
 	w.mu.RLock()
 	defer w.mu.RUnlock()
-	// Deep copy would be better but for now just return the slice
-	return w.accounts
+	accounts := make([]*models.Account, len(w.accounts))
+	for i, acc := range w.accounts {
+		accounts[i] = acc.Clone()
+	}
+	return accounts
+}
+
+// GetChains returns a copy of the monitored chain configuration.
+func (w *Watcher) GetChains() []config.ChainConfig {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return append([]config.ChainConfig(nil), w.chains...)
 }
 
 // GetPrices returns the current prices.
@@ -2,14 +2,21 @@ package watcher
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"evmbal/pkg/config"
 	"evmbal/pkg/models"
+	"evmbal/pkg/rpc"
 	"evmbal/pkg/utils"
 
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -28,16 +35,42 @@ func (m *MockDataSource) FetchChainData(chain config.ChainConfig, accounts []*mo
 	return args.Get(0).(models.ChainData), args.Error(1)
 }
 
-func (m *MockDataSource) FetchGasPrice(rpcURLs []string) (models.GasPriceData, error) {
-	args := m.Called(rpcURLs)
+func (m *MockDataSource) FetchGasPrice(rpcURLs []string, timeout time.Duration) (models.GasPriceData, error) {
+	args := m.Called(rpcURLs, timeout)
 	return args.Get(0).(models.GasPriceData), args.Error(1)
 }
 
-func (m *MockDataSource) FetchTransactions(address string, rpcURLs []string, decimals int) ([]models.Transaction, []string, error) {
-	args := m.Called(address, rpcURLs, decimals)
+func (m *MockDataSource) FetchTransactions(address string, chain config.ChainConfig, decimals int) ([]models.Transaction, []string, error) {
+	args := m.Called(address, chain, decimals)
 	return args.Get(0).([]models.Transaction), args.Get(1).([]string), args.Error(2)
 }
 
+func (m *MockDataSource) FetchTokenTransfers(address string, chain config.ChainConfig, decimals int) ([]models.Transaction, error) {
+	args := m.Called(address, chain, decimals)
+	return args.Get(0).([]models.Transaction), args.Error(1)
+}
+
+func (m *MockDataSource) SubscribeNewHeads(ctx context.Context, wsURL string) (<-chan *types.Header, error) {
+	args := m.Called(ctx, wsURL)
+	heads, _ := args.Get(0).(chan *types.Header)
+	return heads, args.Error(1)
+}
+
+func (m *MockDataSource) FetchRPCLatency(rpcURL string, timeout time.Duration) (models.RPCLatencyData, error) {
+	args := m.Called(rpcURL, timeout)
+	return args.Get(0).(models.RPCLatencyData), args.Error(1)
+}
+
+func (m *MockDataSource) FetchChainlinkPrice(rpcURL, feedAddr string, timeout time.Duration) (float64, error) {
+	args := m.Called(rpcURL, feedAddr, timeout)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockDataSource) IsContract(rpcURL, address string, timeout time.Duration) (bool, error) {
+	args := m.Called(rpcURL, address, timeout)
+	return args.Bool(0), args.Error(1)
+}
+
 func TestNewWatcher(t *testing.T) {
 	addresses := []config.AddressConfig{{Address: "0x123", Name: "Test"}}
 	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH"}}
@@ -82,7 +115,7 @@ func TestFetchAll(t *testing.T) {
 			{Address: "0x123", Balance: big.NewFloat(1.5)},
 		},
 	}, nil)
-	mockDS.On("FetchGasPrice", mock.Anything).Return(models.GasPriceData{Price: big.NewInt(20000000000)}, nil)
+	mockDS.On("FetchGasPrice", mock.Anything, mock.Anything).Return(models.GasPriceData{Price: big.NewInt(20000000000)}, nil)
 	mockDS.On("FetchTransactions", "0x123", mock.Anything, 18).Return([]models.Transaction{}, []string{}, nil)
 
 	sub := w.Subscribe()
@@ -101,7 +134,10 @@ func TestFetchAll(t *testing.T) {
 	eventsCount := 0
 	for i := 0; i < 4; i++ {
 		select {
-		case <-sub:
+		case ev := <-sub:
+			if gas, ok := ev.Data.(models.GasPriceData); ok {
+				assert.Equal(t, "Eth", gas.ChainName)
+			}
 			eventsCount++
 		case <-timeout:
 			t.Errorf("Timed out waiting for events, got %d", eventsCount)
@@ -111,6 +147,151 @@ func TestFetchAll(t *testing.T) {
 	assert.Equal(t, 4, eventsCount)
 }
 
+func TestFetchAll_PriceTrend(t *testing.T) {
+	mockDS := new(MockDataSource)
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", CoinGeckoID: "ethereum"}}
+
+	w := NewWatcher(nil, chains, config.GlobalConfig{}, "")
+	w.SetDataSource(mockDS)
+
+	mockDS.On("FetchChainData", mock.Anything, mock.Anything).Return(models.ChainData{ChainName: "Eth"}, nil)
+	mockDS.On("FetchGasPrice", mock.Anything, mock.Anything).Return(models.GasPriceData{Price: big.NewInt(1)}, nil)
+	mockDS.On("FetchEthPrice", "ethereum").Return(models.PriceData{CoinID: "ethereum", Price: 2000.0}, nil).Once()
+	mockDS.On("FetchEthPrice", "ethereum").Return(models.PriceData{CoinID: "ethereum", Price: 1900.0}, nil).Once()
+
+	sub := w.Subscribe()
+	nextPrice := func() models.PriceData {
+		for {
+			select {
+			case ev := <-sub:
+				if ev.Type == EventPriceUpdated {
+					return ev.Data.(models.PriceData)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("Timed out waiting for price event")
+			}
+		}
+	}
+
+	w.fetchAll()
+	first := nextPrice()
+	assert.Equal(t, 0, first.Trend)
+	assert.False(t, first.UpdatedAt.IsZero(), "watcher should timestamp price updates")
+
+	w.fetchAll()
+	assert.Equal(t, -1, nextPrice().Trend)
+}
+
+func TestFetchAll_PriceFeedFallback(t *testing.T) {
+	mockDS := new(MockDataSource)
+	feed := "0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419"
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", CoinGeckoID: "ethereum", RPCURLs: []string{"http://rpc"}, PriceFeed: feed}}
+
+	w := NewWatcher(nil, chains, config.GlobalConfig{}, "")
+	w.SetDataSource(mockDS)
+
+	mockDS.On("FetchRPCLatency", mock.Anything, mock.Anything).Return(models.RPCLatencyData{RPCURL: "http://rpc", BlockTime: time.Now()}, nil).Maybe()
+	mockDS.On("FetchChainData", mock.Anything, mock.Anything).Return(models.ChainData{ChainName: "Eth"}, nil)
+	mockDS.On("FetchGasPrice", mock.Anything, mock.Anything).Return(models.GasPriceData{Price: big.NewInt(1)}, nil)
+	mockDS.On("FetchEthPrice", "ethereum").Return(models.PriceData{CoinID: "ethereum"}, errors.New("coingecko returned 503"))
+	mockDS.On("FetchChainlinkPrice", "http://rpc", feed, mock.Anything).Return(1850.5, nil)
+
+	sub := w.Subscribe()
+	w.fetchAll()
+	for {
+		select {
+		case ev := <-sub:
+			if ev.Type != EventPriceUpdated {
+				continue
+			}
+			assert.Equal(t, 1850.5, ev.Data.(models.PriceData).Price)
+			assert.Equal(t, 1850.5, w.GetPrices()["ethereum"])
+			return
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for price event")
+		}
+	}
+}
+
+func TestFetchChainData_MarksContractsOnce(t *testing.T) {
+	mockDS := new(MockDataSource)
+	addrs := []config.AddressConfig{{Address: "0xSafe"}, {Address: "0xWallet"}}
+	chain := config.ChainConfig{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://rpc"}, TimeoutSeconds: 3}
+
+	w := NewWatcher(addrs, []config.ChainConfig{chain}, config.GlobalConfig{}, "")
+	w.SetDataSource(mockDS)
+
+	mockDS.On("FetchChainData", mock.Anything, mock.Anything).Return(models.ChainData{ChainName: "Eth", Results: []models.AccountChainData{
+		{Address: "0xSafe", Balance: big.NewFloat(1)},
+		{Address: "0xWallet", Balance: big.NewFloat(2)},
+	}}, nil)
+	mockDS.On("IsContract", "http://rpc", "0xSafe", 3*time.Second).Return(true, nil).Once()
+	mockDS.On("IsContract", "http://rpc", "0xWallet", 3*time.Second).Return(false, nil).Once()
+
+	w.fetchChainData(chain)
+	w.fetchChainData(chain)
+
+	accounts := w.GetAccounts()
+	assert.True(t, accounts[0].IsContract)
+	assert.False(t, accounts[1].IsContract)
+	mockDS.AssertNumberOfCalls(t, "IsContract", 2)
+}
+
+func TestFetchChainData_ChecksContractsInParallel(t *testing.T) {
+	mockDS := new(MockDataSource)
+	var addrs []config.AddressConfig
+	var results []models.AccountChainData
+	for i := 0; i < 5; i++ {
+		addr := fmt.Sprintf("0x%d", i)
+		addrs = append(addrs, config.AddressConfig{Address: addr})
+		results = append(results, models.AccountChainData{Address: addr, Balance: big.NewFloat(1)})
+		mockDS.On("IsContract", "http://rpc", addr, mock.Anything).Return(i == 0, nil).After(200 * time.Millisecond)
+	}
+	chain := config.ChainConfig{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://rpc"}}
+
+	w := NewWatcher(addrs, []config.ChainConfig{chain}, config.GlobalConfig{}, "")
+	w.SetDataSource(mockDS)
+	mockDS.On("FetchChainData", mock.Anything, mock.Anything).Return(models.ChainData{ChainName: "Eth", Results: results}, nil)
+
+	start := time.Now()
+	w.fetchChainData(chain)
+
+	assert.Less(t, time.Since(start), 600*time.Millisecond, "lookups must not run one after another")
+	accounts := w.GetAccounts()
+	assert.True(t, accounts[0].IsContract)
+	assert.False(t, accounts[4].IsContract)
+}
+
+func TestFetchAll_RateLimitedKeepsPrice(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	originalURL, originalDelay := rpc.CoinGeckoBaseURL, rpc.CoinGeckoRetryDelay
+	rpc.CoinGeckoBaseURL, rpc.CoinGeckoRetryDelay = server.URL, time.Millisecond
+	defer func() { rpc.CoinGeckoBaseURL, rpc.CoinGeckoRetryDelay = originalURL, originalDelay }()
+
+	// No RPC URLs: chain and gas fetches fail fast, only the price request goes out.
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", CoinGeckoID: "ethereum"}}
+	w := NewWatcher(nil, chains, config.GlobalConfig{}, "")
+	w.prices["ethereum"] = 2000.0
+
+	sub := w.Subscribe()
+	w.fetchAll()
+
+	for {
+		select {
+		case ev := <-sub:
+			assert.NotEqual(t, EventPriceUpdated, ev.Type, "rate-limited fetch must not emit a price update")
+			continue
+		default:
+		}
+		break
+	}
+	assert.Equal(t, 2000.0, w.GetPrices()["ethereum"])
+}
+
 func TestPollingLoop(t *testing.T) {
 	mockDS := new(MockDataSource)
 	w := NewWatcher(nil, nil, config.GlobalConfig{}, "")
@@ -119,13 +300,420 @@ func TestPollingLoop(t *testing.T) {
 	// Expect at least one fetchAll
 	mockDS.On("FetchEthPrice", mock.Anything).Return(models.PriceData{}, nil).Maybe()
 	mockDS.On("FetchChainData", mock.Anything, mock.Anything).Return(models.ChainData{}, nil).Maybe()
-	mockDS.On("FetchGasPrice", mock.Anything).Return(models.GasPriceData{}, nil).Maybe()
+	mockDS.On("FetchGasPrice", mock.Anything, mock.Anything).Return(models.GasPriceData{}, nil).Maybe()
 	mockDS.On("FetchTransactions", mock.Anything, mock.Anything, mock.Anything).Return([]models.Transaction{}, []string{}, nil).Maybe()
 
 	ctx, cancel := context.WithCancel(context.Background())
 	go w.Start(ctx)
 
 	time.Sleep(100 * time.Millisecond)
+	assert.WithinDuration(t, time.Now().Add(PollInterval), w.NextPoll(), time.Second)
+	w.Pause()
+	assert.True(t, w.NextPoll().IsZero(), "no poll is due while paused")
 	cancel()
 	time.Sleep(50 * time.Millisecond)
 }
+
+func TestFetchAll_SkipsDisabledChains(t *testing.T) {
+	mockDS := new(MockDataSource)
+	disabled := false
+	chains := []config.ChainConfig{
+		{Name: "Eth", Symbol: "ETH"},
+		{Name: "Base", Symbol: "ETH", CoinGeckoID: "base-only", Enabled: &disabled},
+	}
+	w := NewWatcher(nil, chains, config.GlobalConfig{}, "")
+	w.SetDataSource(mockDS)
+	mockDS.On("FetchChainData", chains[0], mock.Anything).Return(models.ChainData{ChainName: "Eth"}, nil).Once()
+	mockDS.On("FetchGasPrice", mock.Anything, mock.Anything).Return(models.GasPriceData{}, nil).Once()
+
+	w.fetchAll()
+
+	mockDS.AssertExpectations(t)
+	mockDS.AssertNotCalled(t, "FetchEthPrice", "base-only")
+}
+
+func TestFetchAll_SkipsDisabledRPCs(t *testing.T) {
+	mockDS := new(MockDataSource)
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://bad", "http://good"}, DisabledRPCs: []string{"http://bad"}}}
+	w := NewWatcher(nil, chains, config.GlobalConfig{}, "")
+	w.SetDataSource(mockDS)
+	mockDS.On("FetchRPCLatency", "http://good", mock.Anything).Return(models.RPCLatencyData{BlockTime: time.Now()}, nil).Once()
+	mockDS.On("FetchChainData", mock.MatchedBy(func(c config.ChainConfig) bool {
+		return assert.ObjectsAreEqual([]string{"http://good"}, c.RPCURLs)
+	}), mock.Anything).Return(models.ChainData{ChainName: "Eth"}, nil).Once()
+	mockDS.On("FetchGasPrice", []string{"http://good"}, mock.Anything).Return(models.GasPriceData{}, nil).Once()
+
+	w.checkAllRPCHealth()
+	w.fetchAll()
+
+	mockDS.AssertExpectations(t)
+	mockDS.AssertNotCalled(t, "FetchRPCLatency", "http://bad", mock.Anything)
+}
+
+func TestFetchAll_PerChainTimeout(t *testing.T) {
+	mockDS := new(MockDataSource)
+	chains := []config.ChainConfig{
+		{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://eth"}},
+		{Name: "Flaky", Symbol: "ETH", RPCURLs: []string{"http://flaky"}, TimeoutSeconds: 3},
+	}
+	w := NewWatcher(nil, chains, config.GlobalConfig{}, "")
+	w.SetDataSource(mockDS)
+	mockDS.On("FetchRPCLatency", mock.Anything, mock.Anything).Return(models.RPCLatencyData{BlockTime: time.Now()}, nil).Maybe()
+	mockDS.On("FetchChainData", mock.Anything, mock.Anything).Return(models.ChainData{}, nil)
+	mockDS.On("FetchGasPrice", []string{"http://eth"}, rpc.DefaultRequestTimeout).Return(models.GasPriceData{}, nil).Once()
+	mockDS.On("FetchGasPrice", []string{"http://flaky"}, 3*time.Second).Return(models.GasPriceData{}, nil).Once()
+
+	w.fetchAll()
+
+	mockDS.AssertExpectations(t)
+}
+
+func TestFetchChainData_EmitsProgress(t *testing.T) {
+	mockDS := new(MockDataSource)
+	chain := config.ChainConfig{Name: "Eth", Symbol: "ETH"}
+	w := NewWatcher(nil, []config.ChainConfig{chain}, config.GlobalConfig{}, "")
+	w.SetDataSource(mockDS)
+	mockDS.On("FetchChainData", mock.Anything, mock.Anything).Return(models.ChainData{}, errors.New("rpc down"))
+
+	sub := w.Subscribe()
+	w.fetchChainData(chain)
+
+	var types []EventType
+	for len(sub) > 0 {
+		types = append(types, (<-sub).Type)
+	}
+	assert.Equal(t, []EventType{EventFetchStarted, EventChainDataUpdated, EventFetchCompleted}, types)
+}
+
+func TestPauseResume_RefreshesImmediately(t *testing.T) {
+	mockDS := new(MockDataSource)
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH"}}
+	w := NewWatcher(nil, chains, config.GlobalConfig{}, "")
+	w.SetDataSource(mockDS)
+	mockDS.On("FetchChainData", mock.Anything, mock.Anything).Return(models.ChainData{ChainName: "Eth"}, nil)
+	mockDS.On("FetchGasPrice", mock.Anything, mock.Anything).Return(models.GasPriceData{}, nil)
+
+	sub := w.Subscribe()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Start(ctx)
+
+	waitForChainData := func() bool {
+		for {
+			select {
+			case ev := <-sub:
+				if ev.Type == EventChainDataUpdated {
+					return true
+				}
+			case <-time.After(time.Second):
+				return false
+			}
+		}
+	}
+	assert.True(t, waitForChainData(), "initial fetch")
+
+	w.Pause()
+	assert.True(t, w.Paused())
+	w.Resume()
+	assert.False(t, w.Paused())
+	assert.True(t, waitForChainData(), "resume fetches without waiting for the next tick")
+
+	// Resuming when not paused does not trigger another fetch.
+	w.Resume()
+	select {
+	case ev := <-sub:
+		assert.NotEqual(t, EventChainDataUpdated, ev.Type)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestCheckGasAlert_Debounced(t *testing.T) {
+	chain := config.ChainConfig{Name: "Eth", GasAlertGwei: 10}
+	w := NewWatcher(nil, []config.ChainConfig{chain}, config.GlobalConfig{}, "")
+	sub := w.Subscribe()
+
+	gwei := func(v int64) *big.Int { return new(big.Int).Mul(big.NewInt(v), big.NewInt(1e9)) }
+	alerts := func() int {
+		n := 0
+		for {
+			select {
+			case ev := <-sub:
+				if ev.Type == EventGasAlert {
+					n++
+				}
+			default:
+				return n
+			}
+		}
+	}
+
+	w.checkGasAlert(chain, gwei(20))
+	assert.Equal(t, 0, alerts(), "no alert above threshold")
+
+	w.checkGasAlert(chain, gwei(5))
+	w.checkGasAlert(chain, gwei(4))
+	assert.Equal(t, 1, alerts(), "alert once per crossing")
+
+	w.checkGasAlert(chain, gwei(15))
+	w.checkGasAlert(chain, gwei(8))
+	assert.Equal(t, 1, alerts(), "alert re-arms after gas rises")
+}
+
+func TestNewHeadsLoop_RefetchesOnHead(t *testing.T) {
+	mockDS := new(MockDataSource)
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"wss://eth.example"}}}
+	w := NewWatcher(nil, chains, config.GlobalConfig{}, "")
+	w.SetDataSource(mockDS)
+
+	heads := make(chan *types.Header, 1)
+	mockDS.On("SubscribeNewHeads", mock.Anything, "wss://eth.example").Return(heads, nil).Once()
+	mockDS.On("FetchChainData", mock.Anything, mock.Anything).Return(models.ChainData{ChainName: "Eth"}, nil)
+
+	sub := w.Subscribe()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.newHeadsLoop(ctx, "Eth", "wss://eth.example")
+
+	// Accounts change while the head-triggered fetch reads them; go test -race
+	// catches the loop reading them without the lock.
+	stop, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+				w.AddAccount(config.AddressConfig{Address: fmt.Sprintf("0x%040d", i)})
+			}
+		}
+	}()
+	heads <- &types.Header{Number: big.NewInt(1)}
+	defer func() { close(stop); <-done }()
+	for {
+		select {
+		case ev := <-sub:
+			if ev.Type == EventFetchStarted {
+				continue
+			}
+			assert.Equal(t, EventChainDataUpdated, ev.Type)
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for chain data refetch on new head")
+		}
+		break
+	}
+	close(heads)
+}
+
+func TestSetChains_StartsAndStopsHeadSubscriptions(t *testing.T) {
+	mockDS := new(MockDataSource)
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://eth.example"}}}
+	w := NewWatcher(nil, chains, config.GlobalConfig{}, "")
+	w.SetDataSource(mockDS)
+	mockDS.On("FetchRPCLatency", mock.Anything, mock.Anything).Return(models.RPCLatencyData{BlockTime: time.Now()}, nil).Maybe()
+	mockDS.On("FetchChainData", mock.Anything, mock.Anything).Return(models.ChainData{ChainName: "Eth"}, nil).Maybe()
+	mockDS.On("FetchGasPrice", mock.Anything, mock.Anything).Return(models.GasPriceData{}, nil).Maybe()
+	subscribed := make(chan context.Context, 2)
+	mockDS.On("SubscribeNewHeads", mock.Anything, "wss://eth.example").Run(func(args mock.Arguments) {
+		subscribed <- args.Get(0).(context.Context)
+	}).Return(nil, errors.New("dial failed"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w.Start(ctx)
+
+	chains[0].RPCURLs = append(chains[0].RPCURLs, "wss://eth.example")
+	w.SetChains(chains)
+	var subCtx context.Context
+	select {
+	case subCtx = <-subscribed:
+	case <-time.After(time.Second):
+		t.Fatal("Adding a websocket RPC did not subscribe to new heads")
+	}
+
+	w.SetChains(chains)
+	select {
+	case <-subscribed:
+		t.Fatal("An unchanged chain subscribed again")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	disabled := false
+	chains[0].Enabled = &disabled
+	w.SetChains(chains)
+	select {
+	case <-subCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Disabling the chain did not stop its subscription")
+	}
+}
+
+func TestFetchAll_StickyLastGoodRPC(t *testing.T) {
+	mockDS := new(MockDataSource)
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://flaky", "http://steady"}}}
+	w := NewWatcher(nil, chains, config.GlobalConfig{}, "")
+	w.SetDataSource(mockDS)
+
+	now := time.Now()
+	mockDS.On("FetchRPCLatency", "http://flaky", mock.Anything).Return(models.RPCLatencyData{Latency: 10 * time.Millisecond, BlockNumber: 100, BlockTime: now}, nil)
+	mockDS.On("FetchRPCLatency", "http://steady", mock.Anything).Return(models.RPCLatencyData{Latency: 50 * time.Millisecond, BlockNumber: 100, BlockTime: now}, nil)
+	order := func(urls ...string) interface{} {
+		return mock.MatchedBy(func(c config.ChainConfig) bool { return assert.ObjectsAreEqual(urls, c.RPCURLs) })
+	}
+	failed := []string{"http://flaky"}
+	mockDS.On("FetchChainData", order("http://flaky", "http://steady"), mock.Anything).Return(models.ChainData{ChainName: "Eth", FailedRPCs: failed}, nil).Once()
+	mockDS.On("FetchChainData", order("http://steady", "http://flaky"), mock.Anything).Return(models.ChainData{ChainName: "Eth"}, nil).Once()
+	mockDS.On("FetchGasPrice", mock.Anything, mock.Anything).Return(models.GasPriceData{FailedRPCs: failed}, nil)
+
+	w.checkAllRPCHealth()
+	w.fetchAll()
+	w.fetchAll()
+	mockDS.AssertExpectations(t)
+
+	// Once the health check finds the remembered RPC down, latency order wins again.
+	w.mu.Lock()
+	w.rpcHealth["http://steady"] = models.RPCLatencyData{Err: assert.AnError}
+	w.mu.Unlock()
+	assert.Equal(t, []string{"http://flaky", "http://steady"}, w.chainRPCs(chains[0]))
+}
+
+func TestStart_SlowHealthCheckDoesNotDelayFetch(t *testing.T) {
+	mockDS := new(MockDataSource)
+	chains := []config.ChainConfig{{Name: "Eth", Symbol: "ETH", RPCURLs: []string{"http://slow"}}}
+	w := NewWatcher(nil, chains, config.GlobalConfig{}, "")
+	w.SetDataSource(mockDS)
+	release := make(chan struct{})
+	defer close(release)
+	mockDS.On("FetchRPCLatency", "http://slow", mock.Anything).Run(func(mock.Arguments) { <-release }).Return(models.RPCLatencyData{BlockTime: time.Now()}, nil)
+	mockDS.On("FetchChainData", mock.Anything, mock.Anything).Return(models.ChainData{ChainName: "Eth"}, nil)
+	mockDS.On("FetchGasPrice", mock.Anything, mock.Anything).Return(models.GasPriceData{}, nil)
+
+	sub := w.Subscribe()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w.Start(ctx)
+
+	for {
+		select {
+		case ev := <-sub:
+			if ev.Type != EventChainDataUpdated {
+				continue
+			}
+		case <-time.After(time.Second):
+			t.Fatal("The fetch waited for the health check")
+		}
+		break
+	}
+}
+
+func TestCheckRPCHealth_DeprioritizesLagging(t *testing.T) {
+	mockDS := new(MockDataSource)
+	chain := config.ChainConfig{Name: "Eth", RPCURLs: []string{"http://fast-stale", "http://slow", "http://down", "http://behind"}}
+	w := NewWatcher(nil, []config.ChainConfig{chain}, config.GlobalConfig{}, "")
+	w.SetDataSource(mockDS)
+
+	now := time.Now()
+	mockDS.On("FetchRPCLatency", "http://fast-stale", mock.Anything).Return(models.RPCLatencyData{Latency: 10 * time.Millisecond, BlockNumber: 100, BlockTime: now.Add(-10 * time.Minute)}, nil)
+	mockDS.On("FetchRPCLatency", "http://slow", mock.Anything).Return(models.RPCLatencyData{Latency: 300 * time.Millisecond, BlockNumber: 150, BlockTime: now}, nil)
+	mockDS.On("FetchRPCLatency", "http://down", mock.Anything).Return(models.RPCLatencyData{}, assert.AnError)
+	mockDS.On("FetchRPCLatency", "http://behind", mock.Anything).Return(models.RPCLatencyData{Latency: 50 * time.Millisecond, BlockNumber: 140, BlockTime: now.Add(-30 * time.Second)}, nil)
+
+	sub := w.Subscribe()
+	w.checkRPCHealth(chain)
+
+	lagging := map[string]bool{}
+	for i := 0; i < len(chain.RPCURLs); i++ {
+		ev := <-sub
+		assert.Equal(t, EventRPCHealthUpdated, ev.Type)
+		data := ev.Data.(models.RPCLatencyData)
+		lagging[data.RPCURL] = data.Lagging
+	}
+	assert.True(t, lagging["http://fast-stale"])
+	assert.True(t, lagging["http://behind"])
+	assert.False(t, lagging["http://slow"])
+
+	assert.Equal(t, []string{"http://slow", "http://fast-stale", "http://behind", "http://down"}, w.prioritizedRPCs(chain.RPCURLs))
+	assert.Equal(t, []string{"http://unknown", "http://down"}, w.prioritizedRPCs([]string{"http://down", "http://unknown"}))
+}
+
+func TestFetchAll_KeepsTransactionsOfEachChain(t *testing.T) {
+	mockDS := new(MockDataSource)
+	addresses := []config.AddressConfig{{Address: "0x123", Name: "Test"}}
+	chains := []config.ChainConfig{
+		{Name: "Eth", Symbol: "ETH", CoinGeckoID: "ethereum"},
+		{Name: "Base", Symbol: "ETH", CoinGeckoID: "ethereum"},
+	}
+	w := NewWatcher(addresses, chains, config.GlobalConfig{TokenDecimals: 18}, "")
+	w.SetDataSource(mockDS)
+
+	onChain := func(name string) interface{} {
+		return mock.MatchedBy(func(c config.ChainConfig) bool { return c.Name == name })
+	}
+	mockDS.On("FetchEthPrice", "ethereum").Return(models.PriceData{CoinID: "ethereum", Price: 2000.0}, nil)
+	mockDS.On("FetchChainData", mock.Anything, mock.Anything).Return(models.ChainData{}, nil)
+	mockDS.On("FetchGasPrice", mock.Anything, mock.Anything).Return(models.GasPriceData{Price: big.NewInt(1)}, nil)
+	mockDS.On("FetchTransactions", "0x123", onChain("Eth"), 18).Return([]models.Transaction{{Hash: "0xeth"}}, []string{}, nil)
+	mockDS.On("FetchTransactions", "0x123", onChain("Base"), 18).Return([]models.Transaction{{Hash: "0xbase"}}, []string{}, nil)
+
+	w.fetchAll()
+	w.fetchAll()
+
+	chainOf := make(map[string]string)
+	for _, tx := range w.GetAccounts()[0].Transactions {
+		chainOf[tx.Hash] = tx.Chain
+	}
+	assert.Equal(t, map[string]string{"0xeth": "Eth", "0xbase": "Base"}, chainOf, "each chain replaces only its own entries")
+	assert.Len(t, w.GetAccounts()[0].Transactions, 2)
+}
+
+func TestGetAccounts_ReturnsSnapshot(t *testing.T) {
+	mockDS := new(MockDataSource)
+	addresses := []config.AddressConfig{{Address: "0x123", Name: "Test"}}
+	chains := []config.ChainConfig{
+		{Name: "Eth", Symbol: "ETH", CoinGeckoID: "ethereum", Tokens: []config.TokenConfig{{Symbol: "USDC"}}},
+		{Name: "Base", Symbol: "ETH", CoinGeckoID: "ethereum"},
+	}
+	w := NewWatcher(addresses, chains, config.GlobalConfig{TokenDecimals: 18}, "")
+	w.SetDataSource(mockDS)
+
+	mockDS.On("FetchEthPrice", "ethereum").Return(models.PriceData{CoinID: "ethereum", Price: 2000.0}, nil)
+	mockDS.On("FetchChainData", mock.Anything, mock.Anything).Return(models.ChainData{
+		ChainName: "Eth",
+		Results: []models.AccountChainData{
+			{Address: "0x123", Balance: big.NewFloat(1.5), TokenBalances: map[string]*big.Float{"USDC": big.NewFloat(10)}},
+		},
+	}, nil)
+	mockDS.On("FetchGasPrice", mock.Anything, mock.Anything).Return(models.GasPriceData{Price: big.NewInt(1)}, nil)
+	mockDS.On("FetchTransactions", "0x123", mock.Anything, 18).Return([]models.Transaction{{Hash: "0xabc"}}, []string{}, nil)
+
+	// Poll and marshal snapshots while fetches write the live accounts; run with -race.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 5; i++ {
+			w.fetchAll()
+		}
+	}()
+	for polling := true; polling; {
+		select {
+		case <-done:
+			polling = false
+		default:
+		}
+		for _, acc := range w.GetAccounts() {
+			_, err := json.Marshal(acc)
+			assert.NoError(t, err)
+		}
+	}
+
+	snapshot := w.GetAccounts()[0]
+	assert.Equal(t, 1.5, utils.BigFloatToFloat64(snapshot.Balances["Eth"]))
+	snapshot.Balances["Eth"].SetFloat64(99)
+	snapshot.TokenBalances["Eth"]["USDC"] = big.NewFloat(0)
+	snapshot.Transactions[0].Hash = "changed"
+
+	acc := w.GetAccounts()[0]
+	assert.Equal(t, 1.5, utils.BigFloatToFloat64(acc.Balances["Eth"]), "changing a snapshot must not affect the watcher")
+	assert.Equal(t, 10.0, utils.BigFloatToFloat64(acc.TokenBalances["Eth"]["USDC"]))
+	assert.Equal(t, "0xabc", acc.Transactions[0].Hash)
+}